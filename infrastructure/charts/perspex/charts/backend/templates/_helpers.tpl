@@ -73,6 +73,31 @@ Define default environment variables
   value: {{ .Values.service.grpcPort | quote }}
 - name: BACKEND_LOG_MODE
   value: {{ .Values.global.config.backend.logmode | default  .Values.config.backend.logmode | quote }}
+- name: BACKEND_LOG_LEVEL
+  value: {{ .Values.global.config.backend.logLevel | default .Values.config.backend.logLevel | quote }}
+{{- with .Values.global.config.backend.logLevels | default .Values.config.backend.logLevels }}
+- name: BACKEND_LOG_LEVELS
+  value: {{ . | quote }}
+{{- end }}
+{{- $cors := .Values.global.config.backend.cors | default .Values.config.backend.cors }}
+- name: BACKEND_CORS_PROFILE
+  value: {{ $cors.profile | default (ternary "development" "production" (eq (.Values.global.environment | default "") "development")) | quote }}
+{{- with $cors.allowedOrigins }}
+- name: BACKEND_CORS_ALLOWED_ORIGINS
+  value: {{ . | quote }}
+{{- end }}
+- name: BACKEND_AUTHZ_MODE
+  value: {{ .Values.global.config.backend.authzMode | default .Values.config.backend.authzMode | quote }}
+{{- with .Values.config.backend.authzAdmins }}
+- name: BACKEND_AUTHZ_ADMINS
+  value: {{ . | quote }}
+{{- end }}
+- name: BACKEND_OUTBOX_SINK
+  value: {{ .Values.global.config.backend.outboxSink | default .Values.config.backend.outboxSink | quote }}
+- name: BACKEND_STORAGE
+  value: {{ .Values.global.config.backend.storage | default .Values.config.backend.storage | quote }}
+- name: BACKEND_AUTO_MIGRATE
+  value: {{ .Values.global.config.backend.autoMigrate | default .Values.config.backend.autoMigrate | quote }}
 - name: WRITER_POSTGRES_HOST
   value: {{ .Values.global.config.database.writer.host | default .Values.config.database.writer.host | quote }}
 - name: WRITER_POSTGRES_PORT
@@ -85,12 +110,16 @@ Define default environment variables
   value: {{ .Values.global.config.database.writer.name | default .Values.config.database.writer.name | quote }}
 - name: WRITER_POSTGRES_MAX_OPEN_CONNECTIONS
   value: {{ .Values.global.config.database.writer.maxOpenConnections | default  .Values.config.database.writer.maxOpenConnections | quote }}
-- name: WRITER_POSTGRES_MAX_IDLE_CONNECTIONS
-  value: {{ .Values.global.config.database.writer.maxIdleConnections | default .Values.config.database.writer.maxIdleConnections | quote }}
+- name: WRITER_POSTGRES_MIN_CONNECTIONS
+  value: {{ .Values.global.config.database.writer.minConnections | default .Values.config.database.writer.minConnections | quote }}
 - name: WRITER_POSTGRES_CONNECTION_LIFESPAN
   value: {{ .Values.global.config.database.writer.connectionLifespan | default .Values.config.database.writer.connectionLifespan | quote }}
 - name: WRITER_POSTGRES_DEBUG
   value: {{ .Values.global.config.database.writer.debug | default .Values.config.database.writer.debug | quote }}
+{{- with .Values.global.config.database.writer.passwordFile | default .Values.config.database.writer.passwordFile }}
+- name: WRITER_POSTGRES_PASSWORD_FILE
+  value: {{ . | quote }}
+{{- end }}
 - name: READER_POSTGRES_HOST
   value: {{ .Values.global.config.database.reader.host | default .Values.config.database.reader.host | quote }}
 - name: READER_POSTGRES_PORT
@@ -103,12 +132,44 @@ Define default environment variables
   value: {{ .Values.global.config.database.reader.name | default .Values.config.database.reader.name | quote }}
 - name: READER_POSTGRES_MAX_OPEN_CONNECTIONS
   value: {{ .Values.global.config.database.reader.maxOpenConnections | default  .Values.config.database.reader.maxOpenConnections | quote }}
-- name: READER_POSTGRES_MAX_IDLE_CONNECTIONS
-  value: {{ .Values.global.config.database.reader.maxIdleConnections | default .Values.config.database.reader.maxIdleConnections | quote }}
+- name: READER_POSTGRES_MIN_CONNECTIONS
+  value: {{ .Values.global.config.database.reader.minConnections | default .Values.config.database.reader.minConnections | quote }}
 - name: READER_POSTGRES_CONNECTION_LIFESPAN
   value: {{ .Values.global.config.database.reader.connectionLifespan | default .Values.config.database.reader.connectionLifespan | quote }}
 - name: READER_POSTGRES_DEBUG
   value: {{ .Values.global.config.database.reader.debug | default .Values.config.database.reader.debug | quote }}
+{{- with .Values.global.config.database.reader.passwordFile | default .Values.config.database.reader.passwordFile }}
+- name: READER_POSTGRES_PASSWORD_FILE
+  value: {{ . | quote }}
+{{- end }}
+- name: POD_NAME
+  valueFrom:
+    fieldRef:
+      fieldPath: metadata.name
+- name: POD_NAMESPACE
+  valueFrom:
+    fieldRef:
+      fieldPath: metadata.namespace
+- name: NODE_NAME
+  valueFrom:
+    fieldRef:
+      fieldPath: spec.nodeName
+- name: OTEL_TRACES_EXPORTER
+  value: {{ .Values.telemetry.tracesExporter | quote }}
+- name: OTEL_METRICS_EXPORTER
+  value: {{ .Values.telemetry.metricsExporter | quote }}
+- name: OTEL_EXPORTER_OTLP_PROTOCOL
+  value: {{ .Values.telemetry.protocol | quote }}
+- name: OTEL_TRACES_SAMPLER_ARG
+  value: {{ .Values.telemetry.sampleRatio | quote }}
+- name: OTEL_SERVICE_VERSION
+  value: {{ .Values.image.tag | quote }}
+{{- with .Values.telemetry.endpoint }}
+- name: OTEL_EXPORTER_OTLP_ENDPOINT
+  value: {{ . | quote }}
+- name: OTEL_EXPORTER_OTLP_INSECURE
+  value: {{ $.Values.telemetry.insecure | quote }}
+{{- end }}
 {{- range $key, $value := .Values.extraEnv }}
 - name: {{ $key | quote }}
   value: {{ $value | quote }}
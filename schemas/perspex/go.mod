@@ -4,16 +4,20 @@ go 1.19
 
 require (
 	github.com/friendsofgo/errors v0.9.2
+	github.com/volatiletech/null/v8 v8.1.2
 	github.com/volatiletech/sqlboiler/v4 v4.13.0
 	github.com/volatiletech/strmangle v0.0.4
 )
 
 require (
+	github.com/ericlagergren/decimal v0.0.0-20181231230500-73749d4874d5 // indirect
 	github.com/gofrs/uuid v4.0.0+incompatible // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
+	github.com/lib/pq v1.2.1-0.20191011153232-f91d3411e481 // indirect
 	github.com/spf13/cast v1.5.0 // indirect
 	github.com/volatiletech/inflect v0.0.1 // indirect
+	github.com/volatiletech/randomize v0.0.1 // indirect
 	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2 // indirect
 )
 
-retract v0.0.0-20221211211708-1397732144fb
\ No newline at end of file
+retract v0.0.0-20221211211708-1397732144fb
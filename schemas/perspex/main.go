@@ -0,0 +1,9 @@
+package perspex
+
+import "embed"
+
+// Models holds the source of the generated sqlboiler models, so their metadata can be checked against the schema
+// the migrations produce.
+//
+//go:embed pkg/models/*.go
+var Models embed.FS
@@ -4,9 +4,25 @@
 package models
 
 var TableNames = struct {
-	Organizations string
-	Users         string
+	APIKeys             string
+	AuditEvents         string
+	OrganizationMembers string
+	Organizations       string
+	Outbox              string
+	UserChanges         string
+	Users               string
+	WebhookAttempts     string
+	WebhookDeliveries   string
+	WebhookEndpoints    string
 }{
-	Organizations: "organizations",
-	Users:         "users",
+	APIKeys:             "api_keys",
+	AuditEvents:         "audit_events",
+	OrganizationMembers: "organization_members",
+	Organizations:       "organizations",
+	Outbox:              "outbox",
+	UserChanges:         "user_changes",
+	Users:               "users",
+	WebhookAttempts:     "webhook_attempts",
+	WebhookDeliveries:   "webhook_deliveries",
+	WebhookEndpoints:    "webhook_endpoints",
 }
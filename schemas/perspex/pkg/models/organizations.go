@@ -58,73 +58,6 @@ var OrganizationTableColumns = struct {
 
 // Generated where
 
-type whereHelperint64 struct{ field string }
-
-func (w whereHelperint64) EQ(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperint64) NEQ(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperint64) LT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperint64) LTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperint64) GT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperint64) GTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperint64) IN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperint64) NIN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelpertime_Time struct{ field string }
-
-func (w whereHelpertime_Time) EQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.EQ, x)
-}
-func (w whereHelpertime_Time) NEQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.NEQ, x)
-}
-func (w whereHelpertime_Time) LT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpertime_Time) LTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpertime_Time) GT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-
 var OrganizationWhere = struct {
 	ID        whereHelperint64
 	Name      whereHelperstring
@@ -139,10 +72,20 @@ var OrganizationWhere = struct {
 
 // OrganizationRels is where relationship names are stored.
 var OrganizationRels = struct {
-}{}
+	APIKeys             string
+	OrganizationMembers string
+	WebhookEndpoints    string
+}{
+	APIKeys:             "APIKeys",
+	OrganizationMembers: "OrganizationMembers",
+	WebhookEndpoints:    "WebhookEndpoints",
+}
 
 // organizationR is where relationships are stored.
 type organizationR struct {
+	APIKeys             APIKeySlice             `boil:"APIKeys" json:"APIKeys" toml:"APIKeys" yaml:"APIKeys"`
+	OrganizationMembers OrganizationMemberSlice `boil:"OrganizationMembers" json:"OrganizationMembers" toml:"OrganizationMembers" yaml:"OrganizationMembers"`
+	WebhookEndpoints    WebhookEndpointSlice    `boil:"WebhookEndpoints" json:"WebhookEndpoints" toml:"WebhookEndpoints" yaml:"WebhookEndpoints"`
 }
 
 // NewStruct creates a new relationship struct
@@ -150,6 +93,27 @@ func (*organizationR) NewStruct() *organizationR {
 	return &organizationR{}
 }
 
+func (r *organizationR) GetAPIKeys() APIKeySlice {
+	if r == nil {
+		return nil
+	}
+	return r.APIKeys
+}
+
+func (r *organizationR) GetOrganizationMembers() OrganizationMemberSlice {
+	if r == nil {
+		return nil
+	}
+	return r.OrganizationMembers
+}
+
+func (r *organizationR) GetWebhookEndpoints() WebhookEndpointSlice {
+	if r == nil {
+		return nil
+	}
+	return r.WebhookEndpoints
+}
+
 // organizationL is where Load methods for each relationship are stored.
 type organizationL struct{}
 
@@ -439,6 +403,623 @@ func (q organizationQuery) Exists(ctx context.Context, exec boil.ContextExecutor
 	return count > 0, nil
 }
 
+// APIKeys retrieves all the api_key's APIKeys with an executor.
+func (o *Organization) APIKeys(mods ...qm.QueryMod) apiKeyQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"api_keys\".\"organization_id\"=?", o.ID),
+	)
+
+	return APIKeys(queryMods...)
+}
+
+// OrganizationMembers retrieves all the organization_member's OrganizationMembers with an executor.
+func (o *Organization) OrganizationMembers(mods ...qm.QueryMod) organizationMemberQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"organization_members\".\"organization_id\"=?", o.ID),
+	)
+
+	return OrganizationMembers(queryMods...)
+}
+
+// WebhookEndpoints retrieves all the webhook_endpoint's WebhookEndpoints with an executor.
+func (o *Organization) WebhookEndpoints(mods ...qm.QueryMod) webhookEndpointQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"webhook_endpoints\".\"organization_id\"=?", o.ID),
+	)
+
+	return WebhookEndpoints(queryMods...)
+}
+
+// LoadAPIKeys allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (organizationL) LoadAPIKeys(ctx context.Context, e boil.ContextExecutor, singular bool, maybeOrganization interface{}, mods queries.Applicator) error {
+	var slice []*Organization
+	var object *Organization
+
+	if singular {
+		var ok bool
+		object, ok = maybeOrganization.(*Organization)
+		if !ok {
+			object = new(Organization)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeOrganization))
+			}
+		}
+	} else {
+		s, ok := maybeOrganization.(*[]*Organization)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeOrganization))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &organizationR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &organizationR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`api_keys`),
+		qm.WhereIn(`api_keys.organization_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load api_keys")
+	}
+
+	var resultSlice []*APIKey
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice api_keys")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on api_keys")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for api_keys")
+	}
+
+	if len(apiKeyAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.APIKeys = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &apiKeyR{}
+			}
+			foreign.R.Organization = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.OrganizationID) {
+				local.R.APIKeys = append(local.R.APIKeys, foreign)
+				if foreign.R == nil {
+					foreign.R = &apiKeyR{}
+				}
+				foreign.R.Organization = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadOrganizationMembers allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (organizationL) LoadOrganizationMembers(ctx context.Context, e boil.ContextExecutor, singular bool, maybeOrganization interface{}, mods queries.Applicator) error {
+	var slice []*Organization
+	var object *Organization
+
+	if singular {
+		var ok bool
+		object, ok = maybeOrganization.(*Organization)
+		if !ok {
+			object = new(Organization)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeOrganization))
+			}
+		}
+	} else {
+		s, ok := maybeOrganization.(*[]*Organization)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeOrganization))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &organizationR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &organizationR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`organization_members`),
+		qm.WhereIn(`organization_members.organization_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load organization_members")
+	}
+
+	var resultSlice []*OrganizationMember
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice organization_members")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on organization_members")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for organization_members")
+	}
+
+	if len(organizationMemberAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.OrganizationMembers = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &organizationMemberR{}
+			}
+			foreign.R.Organization = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.OrganizationID {
+				local.R.OrganizationMembers = append(local.R.OrganizationMembers, foreign)
+				if foreign.R == nil {
+					foreign.R = &organizationMemberR{}
+				}
+				foreign.R.Organization = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadWebhookEndpoints allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (organizationL) LoadWebhookEndpoints(ctx context.Context, e boil.ContextExecutor, singular bool, maybeOrganization interface{}, mods queries.Applicator) error {
+	var slice []*Organization
+	var object *Organization
+
+	if singular {
+		var ok bool
+		object, ok = maybeOrganization.(*Organization)
+		if !ok {
+			object = new(Organization)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeOrganization))
+			}
+		}
+	} else {
+		s, ok := maybeOrganization.(*[]*Organization)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeOrganization)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeOrganization))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &organizationR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &organizationR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`webhook_endpoints`),
+		qm.WhereIn(`webhook_endpoints.organization_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load webhook_endpoints")
+	}
+
+	var resultSlice []*WebhookEndpoint
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice webhook_endpoints")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on webhook_endpoints")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for webhook_endpoints")
+	}
+
+	if len(webhookEndpointAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.WebhookEndpoints = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &webhookEndpointR{}
+			}
+			foreign.R.Organization = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.OrganizationID {
+				local.R.WebhookEndpoints = append(local.R.WebhookEndpoints, foreign)
+				if foreign.R == nil {
+					foreign.R = &webhookEndpointR{}
+				}
+				foreign.R.Organization = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddAPIKeys adds the given related objects to the existing relationships
+// of the organization, optionally inserting them as new records.
+// Appends related to o.R.APIKeys.
+// Sets related.R.Organization appropriately.
+func (o *Organization) AddAPIKeys(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*APIKey) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.OrganizationID, o.ID)
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"api_keys\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"organization_id"}),
+				strmangle.WhereClause("\"", "\"", 2, apiKeyPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.OrganizationID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &organizationR{
+			APIKeys: related,
+		}
+	} else {
+		o.R.APIKeys = append(o.R.APIKeys, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &apiKeyR{
+				Organization: o,
+			}
+		} else {
+			rel.R.Organization = o
+		}
+	}
+	return nil
+}
+
+// SetAPIKeys removes all previously related items of the
+// organization replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Organization's APIKeys accordingly.
+// Replaces o.R.APIKeys with related.
+// Sets related.R.Organization's APIKeys accordingly.
+func (o *Organization) SetAPIKeys(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*APIKey) error {
+	query := "update \"api_keys\" set \"organization_id\" = null where \"organization_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.APIKeys {
+			queries.SetScanner(&rel.OrganizationID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Organization = nil
+		}
+		o.R.APIKeys = nil
+	}
+
+	return o.AddAPIKeys(ctx, exec, insert, related...)
+}
+
+// RemoveAPIKeys relationships from objects passed in.
+// Removes related items from R.APIKeys (uses pointer comparison, removal does not keep order)
+// Sets related.R.Organization.
+func (o *Organization) RemoveAPIKeys(ctx context.Context, exec boil.ContextExecutor, related ...*APIKey) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.OrganizationID, nil)
+		if rel.R != nil {
+			rel.R.Organization = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("organization_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.APIKeys {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.APIKeys)
+			if ln > 1 && i < ln-1 {
+				o.R.APIKeys[i] = o.R.APIKeys[ln-1]
+			}
+			o.R.APIKeys = o.R.APIKeys[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// AddOrganizationMembers adds the given related objects to the existing relationships
+// of the organization, optionally inserting them as new records.
+// Appends related to o.R.OrganizationMembers.
+// Sets related.R.Organization appropriately.
+func (o *Organization) AddOrganizationMembers(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*OrganizationMember) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.OrganizationID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"organization_members\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"organization_id"}),
+				strmangle.WhereClause("\"", "\"", 2, organizationMemberPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.OrganizationID, rel.UserID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.OrganizationID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &organizationR{
+			OrganizationMembers: related,
+		}
+	} else {
+		o.R.OrganizationMembers = append(o.R.OrganizationMembers, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &organizationMemberR{
+				Organization: o,
+			}
+		} else {
+			rel.R.Organization = o
+		}
+	}
+	return nil
+}
+
+// AddWebhookEndpoints adds the given related objects to the existing relationships
+// of the organization, optionally inserting them as new records.
+// Appends related to o.R.WebhookEndpoints.
+// Sets related.R.Organization appropriately.
+func (o *Organization) AddWebhookEndpoints(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*WebhookEndpoint) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.OrganizationID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"webhook_endpoints\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"organization_id"}),
+				strmangle.WhereClause("\"", "\"", 2, webhookEndpointPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.OrganizationID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &organizationR{
+			WebhookEndpoints: related,
+		}
+	} else {
+		o.R.WebhookEndpoints = append(o.R.WebhookEndpoints, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &webhookEndpointR{
+				Organization: o,
+			}
+		} else {
+			rel.R.Organization = o
+		}
+	}
+	return nil
+}
+
 // Organizations retrieves all the records using an executor.
 func Organizations(mods ...qm.QueryMod) organizationQuery {
 	mods = append(mods, qm.From("\"organizations\""))
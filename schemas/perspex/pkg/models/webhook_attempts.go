@@ -0,0 +1,1107 @@
+// Code generated by SQLBoiler 4.13.0 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// WebhookAttempt is an object representing the database table.
+type WebhookAttempt struct {
+	ID          int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	DeliveryID  int64     `boil:"delivery_id" json:"delivery_id" toml:"delivery_id" yaml:"delivery_id"`
+	StatusCode  int       `boil:"status_code" json:"status_code" toml:"status_code" yaml:"status_code"`
+	Error       string    `boil:"error" json:"error" toml:"error" yaml:"error"`
+	DurationMS  int64     `boil:"duration_ms" json:"duration_ms" toml:"duration_ms" yaml:"duration_ms"`
+	AttemptedAt time.Time `boil:"attempted_at" json:"attempted_at" toml:"attempted_at" yaml:"attempted_at"`
+
+	R *webhookAttemptR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L webhookAttemptL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var WebhookAttemptColumns = struct {
+	ID          string
+	DeliveryID  string
+	StatusCode  string
+	Error       string
+	DurationMS  string
+	AttemptedAt string
+}{
+	ID:          "id",
+	DeliveryID:  "delivery_id",
+	StatusCode:  "status_code",
+	Error:       "error",
+	DurationMS:  "duration_ms",
+	AttemptedAt: "attempted_at",
+}
+
+var WebhookAttemptTableColumns = struct {
+	ID          string
+	DeliveryID  string
+	StatusCode  string
+	Error       string
+	DurationMS  string
+	AttemptedAt string
+}{
+	ID:          "webhook_attempts.id",
+	DeliveryID:  "webhook_attempts.delivery_id",
+	StatusCode:  "webhook_attempts.status_code",
+	Error:       "webhook_attempts.error",
+	DurationMS:  "webhook_attempts.duration_ms",
+	AttemptedAt: "webhook_attempts.attempted_at",
+}
+
+// Generated where
+
+var WebhookAttemptWhere = struct {
+	ID          whereHelperint64
+	DeliveryID  whereHelperint64
+	StatusCode  whereHelperint
+	Error       whereHelperstring
+	DurationMS  whereHelperint64
+	AttemptedAt whereHelpertime_Time
+}{
+	ID:          whereHelperint64{field: "\"webhook_attempts\".\"id\""},
+	DeliveryID:  whereHelperint64{field: "\"webhook_attempts\".\"delivery_id\""},
+	StatusCode:  whereHelperint{field: "\"webhook_attempts\".\"status_code\""},
+	Error:       whereHelperstring{field: "\"webhook_attempts\".\"error\""},
+	DurationMS:  whereHelperint64{field: "\"webhook_attempts\".\"duration_ms\""},
+	AttemptedAt: whereHelpertime_Time{field: "\"webhook_attempts\".\"attempted_at\""},
+}
+
+// WebhookAttemptRels is where relationship names are stored.
+var WebhookAttemptRels = struct {
+	Delivery string
+}{
+	Delivery: "Delivery",
+}
+
+// webhookAttemptR is where relationships are stored.
+type webhookAttemptR struct {
+	Delivery *WebhookDelivery `boil:"Delivery" json:"Delivery" toml:"Delivery" yaml:"Delivery"`
+}
+
+// NewStruct creates a new relationship struct
+func (*webhookAttemptR) NewStruct() *webhookAttemptR {
+	return &webhookAttemptR{}
+}
+
+func (r *webhookAttemptR) GetDelivery() *WebhookDelivery {
+	if r == nil {
+		return nil
+	}
+	return r.Delivery
+}
+
+// webhookAttemptL is where Load methods for each relationship are stored.
+type webhookAttemptL struct{}
+
+var (
+	webhookAttemptAllColumns            = []string{"id", "delivery_id", "status_code", "error", "duration_ms", "attempted_at"}
+	webhookAttemptColumnsWithoutDefault = []string{"delivery_id"}
+	webhookAttemptColumnsWithDefault    = []string{"id", "status_code", "error", "duration_ms", "attempted_at"}
+	webhookAttemptPrimaryKeyColumns     = []string{"id"}
+	webhookAttemptGeneratedColumns      = []string{}
+)
+
+type (
+	// WebhookAttemptSlice is an alias for a slice of pointers to WebhookAttempt.
+	// This should almost always be used instead of []WebhookAttempt.
+	WebhookAttemptSlice []*WebhookAttempt
+	// WebhookAttemptHook is the signature for custom WebhookAttempt hook methods
+	WebhookAttemptHook func(context.Context, boil.ContextExecutor, *WebhookAttempt) error
+
+	webhookAttemptQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	webhookAttemptType                 = reflect.TypeOf(&WebhookAttempt{})
+	webhookAttemptMapping              = queries.MakeStructMapping(webhookAttemptType)
+	webhookAttemptPrimaryKeyMapping, _ = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, webhookAttemptPrimaryKeyColumns)
+	webhookAttemptInsertCacheMut       sync.RWMutex
+	webhookAttemptInsertCache          = make(map[string]insertCache)
+	webhookAttemptUpdateCacheMut       sync.RWMutex
+	webhookAttemptUpdateCache          = make(map[string]updateCache)
+	webhookAttemptUpsertCacheMut       sync.RWMutex
+	webhookAttemptUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var webhookAttemptAfterSelectHooks []WebhookAttemptHook
+
+var webhookAttemptBeforeInsertHooks []WebhookAttemptHook
+var webhookAttemptAfterInsertHooks []WebhookAttemptHook
+
+var webhookAttemptBeforeUpdateHooks []WebhookAttemptHook
+var webhookAttemptAfterUpdateHooks []WebhookAttemptHook
+
+var webhookAttemptBeforeDeleteHooks []WebhookAttemptHook
+var webhookAttemptAfterDeleteHooks []WebhookAttemptHook
+
+var webhookAttemptBeforeUpsertHooks []WebhookAttemptHook
+var webhookAttemptAfterUpsertHooks []WebhookAttemptHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *WebhookAttempt) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *WebhookAttempt) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *WebhookAttempt) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *WebhookAttempt) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *WebhookAttempt) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *WebhookAttempt) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *WebhookAttempt) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *WebhookAttempt) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *WebhookAttempt) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range webhookAttemptAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddWebhookAttemptHook registers your hook function for all future operations.
+func AddWebhookAttemptHook(hookPoint boil.HookPoint, webhookAttemptHook WebhookAttemptHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		webhookAttemptAfterSelectHooks = append(webhookAttemptAfterSelectHooks, webhookAttemptHook)
+	case boil.BeforeInsertHook:
+		webhookAttemptBeforeInsertHooks = append(webhookAttemptBeforeInsertHooks, webhookAttemptHook)
+	case boil.AfterInsertHook:
+		webhookAttemptAfterInsertHooks = append(webhookAttemptAfterInsertHooks, webhookAttemptHook)
+	case boil.BeforeUpdateHook:
+		webhookAttemptBeforeUpdateHooks = append(webhookAttemptBeforeUpdateHooks, webhookAttemptHook)
+	case boil.AfterUpdateHook:
+		webhookAttemptAfterUpdateHooks = append(webhookAttemptAfterUpdateHooks, webhookAttemptHook)
+	case boil.BeforeDeleteHook:
+		webhookAttemptBeforeDeleteHooks = append(webhookAttemptBeforeDeleteHooks, webhookAttemptHook)
+	case boil.AfterDeleteHook:
+		webhookAttemptAfterDeleteHooks = append(webhookAttemptAfterDeleteHooks, webhookAttemptHook)
+	case boil.BeforeUpsertHook:
+		webhookAttemptBeforeUpsertHooks = append(webhookAttemptBeforeUpsertHooks, webhookAttemptHook)
+	case boil.AfterUpsertHook:
+		webhookAttemptAfterUpsertHooks = append(webhookAttemptAfterUpsertHooks, webhookAttemptHook)
+	}
+}
+
+// One returns a single webhookAttempt record from the query.
+func (q webhookAttemptQuery) One(ctx context.Context, exec boil.ContextExecutor) (*WebhookAttempt, error) {
+	o := &WebhookAttempt{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for webhook_attempts")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all WebhookAttempt records from the query.
+func (q webhookAttemptQuery) All(ctx context.Context, exec boil.ContextExecutor) (WebhookAttemptSlice, error) {
+	var o []*WebhookAttempt
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to WebhookAttempt slice")
+	}
+
+	if len(webhookAttemptAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all WebhookAttempt records in the query.
+func (q webhookAttemptQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count webhook_attempts rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q webhookAttemptQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if webhook_attempts exists")
+	}
+
+	return count > 0, nil
+}
+
+// Delivery pointed to by the foreign key.
+func (o *WebhookAttempt) Delivery(mods ...qm.QueryMod) webhookDeliveryQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.DeliveryID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return WebhookDeliveries(queryMods...)
+}
+
+// LoadDelivery allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (webhookAttemptL) LoadDelivery(ctx context.Context, e boil.ContextExecutor, singular bool, maybeWebhookAttempt interface{}, mods queries.Applicator) error {
+	var slice []*WebhookAttempt
+	var object *WebhookAttempt
+
+	if singular {
+		var ok bool
+		object, ok = maybeWebhookAttempt.(*WebhookAttempt)
+		if !ok {
+			object = new(WebhookAttempt)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeWebhookAttempt)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeWebhookAttempt))
+			}
+		}
+	} else {
+		s, ok := maybeWebhookAttempt.(*[]*WebhookAttempt)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeWebhookAttempt)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeWebhookAttempt))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &webhookAttemptR{}
+		}
+		args = append(args, object.DeliveryID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &webhookAttemptR{}
+			}
+
+			for _, a := range args {
+				if a == obj.DeliveryID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.DeliveryID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`webhook_deliveries`),
+		qm.WhereIn(`webhook_deliveries.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load WebhookDelivery")
+	}
+
+	var resultSlice []*WebhookDelivery
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice WebhookDelivery")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for webhook_deliveries")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for webhook_deliveries")
+	}
+
+	if len(webhookAttemptAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Delivery = foreign
+		if foreign.R == nil {
+			foreign.R = &webhookDeliveryR{}
+		}
+		foreign.R.DeliveryWebhookAttempts = append(foreign.R.DeliveryWebhookAttempts, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.DeliveryID == foreign.ID {
+				local.R.Delivery = foreign
+				if foreign.R == nil {
+					foreign.R = &webhookDeliveryR{}
+				}
+				foreign.R.DeliveryWebhookAttempts = append(foreign.R.DeliveryWebhookAttempts, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetDelivery of the webhookAttempt to the related item.
+// Sets o.R.Delivery to related.
+// Adds o to related.R.DeliveryWebhookAttempts.
+func (o *WebhookAttempt) SetDelivery(ctx context.Context, exec boil.ContextExecutor, insert bool, related *WebhookDelivery) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"webhook_attempts\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"delivery_id"}),
+		strmangle.WhereClause("\"", "\"", 2, webhookAttemptPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.DeliveryID = related.ID
+	if o.R == nil {
+		o.R = &webhookAttemptR{
+			Delivery: related,
+		}
+	} else {
+		o.R.Delivery = related
+	}
+
+	if related.R == nil {
+		related.R = &webhookDeliveryR{
+			DeliveryWebhookAttempts: WebhookAttemptSlice{o},
+		}
+	} else {
+		related.R.DeliveryWebhookAttempts = append(related.R.DeliveryWebhookAttempts, o)
+	}
+
+	return nil
+}
+
+// WebhookAttempts retrieves all the records using an executor.
+func WebhookAttempts(mods ...qm.QueryMod) webhookAttemptQuery {
+	mods = append(mods, qm.From("\"webhook_attempts\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"webhook_attempts\".*"})
+	}
+
+	return webhookAttemptQuery{q}
+}
+
+// FindWebhookAttempt retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindWebhookAttempt(ctx context.Context, exec boil.ContextExecutor, iD int64, selectCols ...string) (*WebhookAttempt, error) {
+	webhookAttemptObj := &WebhookAttempt{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"webhook_attempts\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, webhookAttemptObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from webhook_attempts")
+	}
+
+	if err = webhookAttemptObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return webhookAttemptObj, err
+	}
+
+	return webhookAttemptObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *WebhookAttempt) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no webhook_attempts provided for insertion")
+	}
+
+	var err error
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(webhookAttemptColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	webhookAttemptInsertCacheMut.RLock()
+	cache, cached := webhookAttemptInsertCache[key]
+	webhookAttemptInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			webhookAttemptAllColumns,
+			webhookAttemptColumnsWithDefault,
+			webhookAttemptColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"webhook_attempts\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"webhook_attempts\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into webhook_attempts")
+	}
+
+	if !cached {
+		webhookAttemptInsertCacheMut.Lock()
+		webhookAttemptInsertCache[key] = cache
+		webhookAttemptInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the WebhookAttempt.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *WebhookAttempt) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	webhookAttemptUpdateCacheMut.RLock()
+	cache, cached := webhookAttemptUpdateCache[key]
+	webhookAttemptUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			webhookAttemptAllColumns,
+			webhookAttemptPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update webhook_attempts, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"webhook_attempts\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, webhookAttemptPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, append(wl, webhookAttemptPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update webhook_attempts row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for webhook_attempts")
+	}
+
+	if !cached {
+		webhookAttemptUpdateCacheMut.Lock()
+		webhookAttemptUpdateCache[key] = cache
+		webhookAttemptUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q webhookAttemptQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for webhook_attempts")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for webhook_attempts")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o WebhookAttemptSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), webhookAttemptPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"webhook_attempts\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, webhookAttemptPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in webhookAttempt slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all webhookAttempt")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *WebhookAttempt) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no webhook_attempts provided for upsert")
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(webhookAttemptColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	webhookAttemptUpsertCacheMut.RLock()
+	cache, cached := webhookAttemptUpsertCache[key]
+	webhookAttemptUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			webhookAttemptAllColumns,
+			webhookAttemptColumnsWithDefault,
+			webhookAttemptColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			webhookAttemptAllColumns,
+			webhookAttemptPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert webhook_attempts, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(webhookAttemptPrimaryKeyColumns))
+			copy(conflict, webhookAttemptPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"webhook_attempts\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(webhookAttemptType, webhookAttemptMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert webhook_attempts")
+	}
+
+	if !cached {
+		webhookAttemptUpsertCacheMut.Lock()
+		webhookAttemptUpsertCache[key] = cache
+		webhookAttemptUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single WebhookAttempt record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *WebhookAttempt) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no WebhookAttempt provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), webhookAttemptPrimaryKeyMapping)
+	sql := "DELETE FROM \"webhook_attempts\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from webhook_attempts")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for webhook_attempts")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q webhookAttemptQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no webhookAttemptQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from webhook_attempts")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for webhook_attempts")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o WebhookAttemptSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(webhookAttemptBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), webhookAttemptPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"webhook_attempts\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, webhookAttemptPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from webhookAttempt slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for webhook_attempts")
+	}
+
+	if len(webhookAttemptAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *WebhookAttempt) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindWebhookAttempt(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *WebhookAttemptSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := WebhookAttemptSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), webhookAttemptPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"webhook_attempts\".* FROM \"webhook_attempts\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, webhookAttemptPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in WebhookAttemptSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// WebhookAttemptExists checks if the WebhookAttempt row exists.
+func WebhookAttemptExists(ctx context.Context, exec boil.ContextExecutor, iD int64) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"webhook_attempts\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if webhook_attempts exists")
+	}
+
+	return exists, nil
+}
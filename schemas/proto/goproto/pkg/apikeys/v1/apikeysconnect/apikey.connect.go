@@ -0,0 +1,153 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: apikeys/v1/apikey.proto
+
+package apikeysconnect
+
+import (
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/apikeys/v1"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// ApiKeyServiceName is the fully-qualified name of the ApiKeyService service.
+	ApiKeyServiceName = "apikeys.v1.ApiKeyService"
+)
+
+// ApiKeyServiceClient is a client for the apikeys.v1.ApiKeyService service.
+type ApiKeyServiceClient interface {
+	CreateApiKey(context.Context, *connect_go.Request[v1.CreateApiKeyRequest]) (*connect_go.Response[v1.CreateApiKeyResponse], error)
+	ListApiKeys(context.Context, *connect_go.Request[v1.ListApiKeysRequest]) (*connect_go.Response[v1.ListApiKeysResponse], error)
+	RotateApiKey(context.Context, *connect_go.Request[v1.RotateApiKeyRequest]) (*connect_go.Response[v1.RotateApiKeyResponse], error)
+	RevokeApiKey(context.Context, *connect_go.Request[v1.RevokeApiKeyRequest]) (*connect_go.Response[v1.RevokeApiKeyResponse], error)
+}
+
+// NewApiKeyServiceClient constructs a client for the apikeys.v1.ApiKeyService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewApiKeyServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) ApiKeyServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &apiKeyServiceClient{
+		createApiKey: connect_go.NewClient[v1.CreateApiKeyRequest, v1.CreateApiKeyResponse](
+			httpClient,
+			baseURL+"/apikeys.v1.ApiKeyService/CreateApiKey",
+			opts...,
+		),
+		listApiKeys: connect_go.NewClient[v1.ListApiKeysRequest, v1.ListApiKeysResponse](
+			httpClient,
+			baseURL+"/apikeys.v1.ApiKeyService/ListApiKeys",
+			opts...,
+		),
+		rotateApiKey: connect_go.NewClient[v1.RotateApiKeyRequest, v1.RotateApiKeyResponse](
+			httpClient,
+			baseURL+"/apikeys.v1.ApiKeyService/RotateApiKey",
+			opts...,
+		),
+		revokeApiKey: connect_go.NewClient[v1.RevokeApiKeyRequest, v1.RevokeApiKeyResponse](
+			httpClient,
+			baseURL+"/apikeys.v1.ApiKeyService/RevokeApiKey",
+			opts...,
+		),
+	}
+}
+
+// apiKeyServiceClient implements ApiKeyServiceClient.
+type apiKeyServiceClient struct {
+	createApiKey *connect_go.Client[v1.CreateApiKeyRequest, v1.CreateApiKeyResponse]
+	listApiKeys  *connect_go.Client[v1.ListApiKeysRequest, v1.ListApiKeysResponse]
+	rotateApiKey *connect_go.Client[v1.RotateApiKeyRequest, v1.RotateApiKeyResponse]
+	revokeApiKey *connect_go.Client[v1.RevokeApiKeyRequest, v1.RevokeApiKeyResponse]
+}
+
+// CreateApiKey calls apikeys.v1.ApiKeyService.CreateApiKey.
+func (c *apiKeyServiceClient) CreateApiKey(ctx context.Context, req *connect_go.Request[v1.CreateApiKeyRequest]) (*connect_go.Response[v1.CreateApiKeyResponse], error) {
+	return c.createApiKey.CallUnary(ctx, req)
+}
+
+// ListApiKeys calls apikeys.v1.ApiKeyService.ListApiKeys.
+func (c *apiKeyServiceClient) ListApiKeys(ctx context.Context, req *connect_go.Request[v1.ListApiKeysRequest]) (*connect_go.Response[v1.ListApiKeysResponse], error) {
+	return c.listApiKeys.CallUnary(ctx, req)
+}
+
+// RotateApiKey calls apikeys.v1.ApiKeyService.RotateApiKey.
+func (c *apiKeyServiceClient) RotateApiKey(ctx context.Context, req *connect_go.Request[v1.RotateApiKeyRequest]) (*connect_go.Response[v1.RotateApiKeyResponse], error) {
+	return c.rotateApiKey.CallUnary(ctx, req)
+}
+
+// RevokeApiKey calls apikeys.v1.ApiKeyService.RevokeApiKey.
+func (c *apiKeyServiceClient) RevokeApiKey(ctx context.Context, req *connect_go.Request[v1.RevokeApiKeyRequest]) (*connect_go.Response[v1.RevokeApiKeyResponse], error) {
+	return c.revokeApiKey.CallUnary(ctx, req)
+}
+
+// ApiKeyServiceHandler is an implementation of the apikeys.v1.ApiKeyService service.
+type ApiKeyServiceHandler interface {
+	CreateApiKey(context.Context, *connect_go.Request[v1.CreateApiKeyRequest]) (*connect_go.Response[v1.CreateApiKeyResponse], error)
+	ListApiKeys(context.Context, *connect_go.Request[v1.ListApiKeysRequest]) (*connect_go.Response[v1.ListApiKeysResponse], error)
+	RotateApiKey(context.Context, *connect_go.Request[v1.RotateApiKeyRequest]) (*connect_go.Response[v1.RotateApiKeyResponse], error)
+	RevokeApiKey(context.Context, *connect_go.Request[v1.RevokeApiKeyRequest]) (*connect_go.Response[v1.RevokeApiKeyResponse], error)
+}
+
+// NewApiKeyServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewApiKeyServiceHandler(svc ApiKeyServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("/apikeys.v1.ApiKeyService/CreateApiKey", connect_go.NewUnaryHandler(
+		"/apikeys.v1.ApiKeyService/CreateApiKey",
+		svc.CreateApiKey,
+		opts...,
+	))
+	mux.Handle("/apikeys.v1.ApiKeyService/ListApiKeys", connect_go.NewUnaryHandler(
+		"/apikeys.v1.ApiKeyService/ListApiKeys",
+		svc.ListApiKeys,
+		opts...,
+	))
+	mux.Handle("/apikeys.v1.ApiKeyService/RotateApiKey", connect_go.NewUnaryHandler(
+		"/apikeys.v1.ApiKeyService/RotateApiKey",
+		svc.RotateApiKey,
+		opts...,
+	))
+	mux.Handle("/apikeys.v1.ApiKeyService/RevokeApiKey", connect_go.NewUnaryHandler(
+		"/apikeys.v1.ApiKeyService/RevokeApiKey",
+		svc.RevokeApiKey,
+		opts...,
+	))
+	return "/apikeys.v1.ApiKeyService/", mux
+}
+
+// UnimplementedApiKeyServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedApiKeyServiceHandler struct{}
+
+func (UnimplementedApiKeyServiceHandler) CreateApiKey(context.Context, *connect_go.Request[v1.CreateApiKeyRequest]) (*connect_go.Response[v1.CreateApiKeyResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("apikeys.v1.ApiKeyService.CreateApiKey is not implemented"))
+}
+
+func (UnimplementedApiKeyServiceHandler) ListApiKeys(context.Context, *connect_go.Request[v1.ListApiKeysRequest]) (*connect_go.Response[v1.ListApiKeysResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("apikeys.v1.ApiKeyService.ListApiKeys is not implemented"))
+}
+
+func (UnimplementedApiKeyServiceHandler) RotateApiKey(context.Context, *connect_go.Request[v1.RotateApiKeyRequest]) (*connect_go.Response[v1.RotateApiKeyResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("apikeys.v1.ApiKeyService.RotateApiKey is not implemented"))
+}
+
+func (UnimplementedApiKeyServiceHandler) RevokeApiKey(context.Context, *connect_go.Request[v1.RevokeApiKeyRequest]) (*connect_go.Response[v1.RevokeApiKeyResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("apikeys.v1.ApiKeyService.RevokeApiKey is not implemented"))
+}
@@ -0,0 +1,288 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: options/v1/options.proto
+
+package options
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Rule grants callers access to a method.
+type Rule int32
+
+const (
+	Rule_RULE_UNSPECIFIED Rule = 0
+	// Anyone, authenticated or not.
+	Rule_RULE_PUBLIC Rule = 1
+	// Any authenticated caller.
+	Rule_RULE_AUTHENTICATED Rule = 2
+	// Callers acting on their own user only.
+	Rule_RULE_SELF Rule = 3
+	// Admins of an organization that every user acted on is a member of.
+	Rule_RULE_ORG_ADMIN Rule = 4
+	// Global admins.
+	Rule_RULE_ADMIN Rule = 5
+)
+
+// Enum value maps for Rule.
+var (
+	Rule_name = map[int32]string{
+		0: "RULE_UNSPECIFIED",
+		1: "RULE_PUBLIC",
+		2: "RULE_AUTHENTICATED",
+		3: "RULE_SELF",
+		4: "RULE_ORG_ADMIN",
+		5: "RULE_ADMIN",
+	}
+	Rule_value = map[string]int32{
+		"RULE_UNSPECIFIED":   0,
+		"RULE_PUBLIC":        1,
+		"RULE_AUTHENTICATED": 2,
+		"RULE_SELF":          3,
+		"RULE_ORG_ADMIN":     4,
+		"RULE_ADMIN":         5,
+	}
+)
+
+func (x Rule) Enum() *Rule {
+	p := new(Rule)
+	*p = x
+	return p
+}
+
+func (x Rule) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Rule) Descriptor() protoreflect.EnumDescriptor {
+	return file_options_v1_options_proto_enumTypes[0].Descriptor()
+}
+
+func (Rule) Type() protoreflect.EnumType {
+	return &file_options_v1_options_proto_enumTypes[0]
+}
+
+func (x Rule) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Rule.Descriptor instead.
+func (Rule) EnumDescriptor() ([]byte, []int) {
+	return file_options_v1_options_proto_rawDescGZIP(), []int{0}
+}
+
+// Access declares who may call a method. Callers are allowed if any of the rules allows them.
+type Access struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allow []Rule `protobuf:"varint,1,rep,packed,name=allow,proto3,enum=options.v1.Rule" json:"allow,omitempty"`
+	// subject is the path of the request field holding the IDs of the users acted on, eg: `user.id`, for the SELF and
+	// ORG_ADMIN rules.
+	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+}
+
+func (x *Access) Reset() {
+	*x = Access{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_options_v1_options_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Access) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Access) ProtoMessage() {}
+
+func (x *Access) ProtoReflect() protoreflect.Message {
+	mi := &file_options_v1_options_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Access.ProtoReflect.Descriptor instead.
+func (*Access) Descriptor() ([]byte, []int) {
+	return file_options_v1_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Access) GetAllow() []Rule {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *Access) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+var file_options_v1_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50000,
+		Name:          "options.v1.pii",
+		Tag:           "varint,50000,opt,name=pii",
+		Filename:      "options/v1/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Access)(nil),
+		Field:         50001,
+		Name:          "options.v1.access",
+		Tag:           "bytes,50001,opt,name=access",
+		Filename:      "options/v1/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// pii marks a field as holding personally identifiable information, which is redacted from logs and traces.
+	//
+	// optional bool pii = 50000;
+	E_Pii = &file_options_v1_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// access is enforced by the backend's authorization interceptor. Methods without it are denied to everyone.
+	//
+	// optional options.v1.Access access = 50001;
+	E_Access = &file_options_v1_options_proto_extTypes[1]
+)
+
+var File_options_v1_options_proto protoreflect.FileDescriptor
+
+var file_options_v1_options_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4a, 0x0a, 0x06, 0x41, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0e, 0x32, 0x10, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x2a, 0x78, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x10,
+	0x52, 0x55, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49,
+	0x43, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x41, 0x55, 0x54, 0x48,
+	0x45, 0x4e, 0x54, 0x49, 0x43, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x52,
+	0x55, 0x4c, 0x45, 0x5f, 0x53, 0x45, 0x4c, 0x46, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x55,
+	0x4c, 0x45, 0x5f, 0x4f, 0x52, 0x47, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x10, 0x04, 0x12, 0x0e,
+	0x0a, 0x0a, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x10, 0x05, 0x3a, 0x31,
+	0x0a, 0x03, 0x70, 0x69, 0x69, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd0, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x70, 0x69,
+	0x69, 0x3a, 0x4c, 0x0a, 0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1e, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x42,
+	0x4d, 0x5a, 0x4b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6d,
+	0x61, 0x6e, 0x64, 0x65, 0x6c, 0x31, 0x30, 0x32, 0x37, 0x2f, 0x70, 0x65, 0x72, 0x73, 0x70, 0x65,
+	0x78, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x67, 0x6f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_options_v1_options_proto_rawDescOnce sync.Once
+	file_options_v1_options_proto_rawDescData = file_options_v1_options_proto_rawDesc
+)
+
+func file_options_v1_options_proto_rawDescGZIP() []byte {
+	file_options_v1_options_proto_rawDescOnce.Do(func() {
+		file_options_v1_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_options_v1_options_proto_rawDescData)
+	})
+	return file_options_v1_options_proto_rawDescData
+}
+
+var file_options_v1_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_options_v1_options_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_options_v1_options_proto_goTypes = []interface{}{
+	(Rule)(0),                          // 0: options.v1.Rule
+	(*Access)(nil),                     // 1: options.v1.Access
+	(*descriptorpb.FieldOptions)(nil),  // 2: google.protobuf.FieldOptions
+	(*descriptorpb.MethodOptions)(nil), // 3: google.protobuf.MethodOptions
+}
+var file_options_v1_options_proto_depIdxs = []int32{
+	0, // 0: options.v1.Access.allow:type_name -> options.v1.Rule
+	2, // 1: options.v1.pii:extendee -> google.protobuf.FieldOptions
+	3, // 2: options.v1.access:extendee -> google.protobuf.MethodOptions
+	1, // 3: options.v1.access:type_name -> options.v1.Access
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	3, // [3:4] is the sub-list for extension type_name
+	1, // [1:3] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_options_v1_options_proto_init() }
+func file_options_v1_options_proto_init() {
+	if File_options_v1_options_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_options_v1_options_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Access); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_options_v1_options_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_options_v1_options_proto_goTypes,
+		DependencyIndexes: file_options_v1_options_proto_depIdxs,
+		EnumInfos:         file_options_v1_options_proto_enumTypes,
+		MessageInfos:      file_options_v1_options_proto_msgTypes,
+		ExtensionInfos:    file_options_v1_options_proto_extTypes,
+	}.Build()
+	File_options_v1_options_proto = out.File
+	file_options_v1_options_proto_rawDesc = nil
+	file_options_v1_options_proto_goTypes = nil
+	file_options_v1_options_proto_depIdxs = nil
+}
@@ -28,6 +28,11 @@ type UserServiceClient interface {
 	RetrieveUser(ctx context.Context, in *RetrieveUserRequest, opts ...grpc.CallOption) (*RetrieveUserResponse, error)
 	RetrieveUsers(ctx context.Context, in *RetrieveUsersRequest, opts ...grpc.CallOption) (*RetrieveUsersResponse, error)
 	RetrieveUsersPage(ctx context.Context, in *RetrieveUsersPageRequest, opts ...grpc.CallOption) (*RetrieveUsersPageResponse, error)
+	// WatchUsers streams the changes to users as they are committed, with a heartbeat whenever none have been sent for a
+	// while. Watchers resume after a disconnect by passing the cursor of the last message they received, and must
+	// expect to receive a change more than once. Only admins may watch every user, and organization admins the members
+	// of their organization.
+	WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (UserService_WatchUsersClient, error)
 }
 
 type userServiceClient struct {
@@ -92,6 +97,38 @@ func (c *userServiceClient) RetrieveUsersPage(ctx context.Context, in *RetrieveU
 	return out, nil
 }
 
+func (c *userServiceClient) WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (UserService_WatchUsersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[0], "/users.v1.UserService/WatchUsers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userServiceWatchUsersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UserService_WatchUsersClient interface {
+	Recv() (*WatchUsersResponse, error)
+	grpc.ClientStream
+}
+
+type userServiceWatchUsersClient struct {
+	grpc.ClientStream
+}
+
+func (x *userServiceWatchUsersClient) Recv() (*WatchUsersResponse, error) {
+	m := new(WatchUsersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -102,6 +139,11 @@ type UserServiceServer interface {
 	RetrieveUser(context.Context, *RetrieveUserRequest) (*RetrieveUserResponse, error)
 	RetrieveUsers(context.Context, *RetrieveUsersRequest) (*RetrieveUsersResponse, error)
 	RetrieveUsersPage(context.Context, *RetrieveUsersPageRequest) (*RetrieveUsersPageResponse, error)
+	// WatchUsers streams the changes to users as they are committed, with a heartbeat whenever none have been sent for a
+	// while. Watchers resume after a disconnect by passing the cursor of the last message they received, and must
+	// expect to receive a change more than once. Only admins may watch every user, and organization admins the members
+	// of their organization.
+	WatchUsers(*WatchUsersRequest, UserService_WatchUsersServer) error
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -127,6 +169,9 @@ func (UnimplementedUserServiceServer) RetrieveUsers(context.Context, *RetrieveUs
 func (UnimplementedUserServiceServer) RetrieveUsersPage(context.Context, *RetrieveUsersPageRequest) (*RetrieveUsersPageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetrieveUsersPage not implemented")
 }
+func (UnimplementedUserServiceServer) WatchUsers(*WatchUsersRequest, UserService_WatchUsersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -248,6 +293,27 @@ func _UserService_RetrieveUsersPage_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_WatchUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).WatchUsers(m, &userServiceWatchUsersServer{stream})
+}
+
+type UserService_WatchUsersServer interface {
+	Send(*WatchUsersResponse) error
+	grpc.ServerStream
+}
+
+type userServiceWatchUsersServer struct {
+	grpc.ServerStream
+}
+
+func (x *userServiceWatchUsersServer) Send(m *WatchUsersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -280,6 +346,12 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _UserService_RetrieveUsersPage_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUsers",
+			Handler:       _UserService_WatchUsers_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "users/v1/user.proto",
 }
@@ -34,6 +34,11 @@ type UserServiceClient interface {
 	RetrieveUser(context.Context, *connect_go.Request[v1.RetrieveUserRequest]) (*connect_go.Response[v1.RetrieveUserResponse], error)
 	RetrieveUsers(context.Context, *connect_go.Request[v1.RetrieveUsersRequest]) (*connect_go.Response[v1.RetrieveUsersResponse], error)
 	RetrieveUsersPage(context.Context, *connect_go.Request[v1.RetrieveUsersPageRequest]) (*connect_go.Response[v1.RetrieveUsersPageResponse], error)
+	// WatchUsers streams the changes to users as they are committed, with a heartbeat whenever none have been sent for a
+	// while. Watchers resume after a disconnect by passing the cursor of the last message they received, and must
+	// expect to receive a change more than once. Only admins may watch every user, and organization admins the members
+	// of their organization.
+	WatchUsers(context.Context, *connect_go.Request[v1.WatchUsersRequest]) (*connect_go.ServerStreamForClient[v1.WatchUsersResponse], error)
 }
 
 // NewUserServiceClient constructs a client for the users.v1.UserService service. By default, it
@@ -76,6 +81,11 @@ func NewUserServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts
 			baseURL+"/users.v1.UserService/RetrieveUsersPage",
 			opts...,
 		),
+		watchUsers: connect_go.NewClient[v1.WatchUsersRequest, v1.WatchUsersResponse](
+			httpClient,
+			baseURL+"/users.v1.UserService/WatchUsers",
+			opts...,
+		),
 	}
 }
 
@@ -87,6 +97,7 @@ type userServiceClient struct {
 	retrieveUser      *connect_go.Client[v1.RetrieveUserRequest, v1.RetrieveUserResponse]
 	retrieveUsers     *connect_go.Client[v1.RetrieveUsersRequest, v1.RetrieveUsersResponse]
 	retrieveUsersPage *connect_go.Client[v1.RetrieveUsersPageRequest, v1.RetrieveUsersPageResponse]
+	watchUsers        *connect_go.Client[v1.WatchUsersRequest, v1.WatchUsersResponse]
 }
 
 // DeleteUser calls users.v1.UserService.DeleteUser.
@@ -119,6 +130,11 @@ func (c *userServiceClient) RetrieveUsersPage(ctx context.Context, req *connect_
 	return c.retrieveUsersPage.CallUnary(ctx, req)
 }
 
+// WatchUsers calls users.v1.UserService.WatchUsers.
+func (c *userServiceClient) WatchUsers(ctx context.Context, req *connect_go.Request[v1.WatchUsersRequest]) (*connect_go.ServerStreamForClient[v1.WatchUsersResponse], error) {
+	return c.watchUsers.CallServerStream(ctx, req)
+}
+
 // UserServiceHandler is an implementation of the users.v1.UserService service.
 type UserServiceHandler interface {
 	DeleteUser(context.Context, *connect_go.Request[v1.DeleteUserRequest]) (*connect_go.Response[v1.DeleteUserResponse], error)
@@ -127,6 +143,11 @@ type UserServiceHandler interface {
 	RetrieveUser(context.Context, *connect_go.Request[v1.RetrieveUserRequest]) (*connect_go.Response[v1.RetrieveUserResponse], error)
 	RetrieveUsers(context.Context, *connect_go.Request[v1.RetrieveUsersRequest]) (*connect_go.Response[v1.RetrieveUsersResponse], error)
 	RetrieveUsersPage(context.Context, *connect_go.Request[v1.RetrieveUsersPageRequest]) (*connect_go.Response[v1.RetrieveUsersPageResponse], error)
+	// WatchUsers streams the changes to users as they are committed, with a heartbeat whenever none have been sent for a
+	// while. Watchers resume after a disconnect by passing the cursor of the last message they received, and must
+	// expect to receive a change more than once. Only admins may watch every user, and organization admins the members
+	// of their organization.
+	WatchUsers(context.Context, *connect_go.Request[v1.WatchUsersRequest], *connect_go.ServerStream[v1.WatchUsersResponse]) error
 }
 
 // NewUserServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -166,6 +187,11 @@ func NewUserServiceHandler(svc UserServiceHandler, opts ...connect_go.HandlerOpt
 		svc.RetrieveUsersPage,
 		opts...,
 	))
+	mux.Handle("/users.v1.UserService/WatchUsers", connect_go.NewServerStreamHandler(
+		"/users.v1.UserService/WatchUsers",
+		svc.WatchUsers,
+		opts...,
+	))
 	return "/users.v1.UserService/", mux
 }
 
@@ -195,3 +221,7 @@ func (UnimplementedUserServiceHandler) RetrieveUsers(context.Context, *connect_g
 func (UnimplementedUserServiceHandler) RetrieveUsersPage(context.Context, *connect_go.Request[v1.RetrieveUsersPageRequest]) (*connect_go.Response[v1.RetrieveUsersPageResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("users.v1.UserService.RetrieveUsersPage is not implemented"))
 }
+
+func (UnimplementedUserServiceHandler) WatchUsers(context.Context, *connect_go.Request[v1.WatchUsersRequest], *connect_go.ServerStream[v1.WatchUsersResponse]) error {
+	return connect_go.NewError(connect_go.CodeUnimplemented, errors.New("users.v1.UserService.WatchUsers is not implemented"))
+}
@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: webhooks/v1/webhooks.proto
+
+package webhooksconnect
+
+import (
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/webhooks/v1"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// WebhookServiceName is the fully-qualified name of the WebhookService service.
+	WebhookServiceName = "webhooks.v1.WebhookService"
+)
+
+// WebhookServiceClient is a client for the webhooks.v1.WebhookService service.
+type WebhookServiceClient interface {
+	CreateEndpoint(context.Context, *connect_go.Request[v1.CreateEndpointRequest]) (*connect_go.Response[v1.CreateEndpointResponse], error)
+	ListEndpoints(context.Context, *connect_go.Request[v1.ListEndpointsRequest]) (*connect_go.Response[v1.ListEndpointsResponse], error)
+	EnableEndpoint(context.Context, *connect_go.Request[v1.EnableEndpointRequest]) (*connect_go.Response[v1.EnableEndpointResponse], error)
+	DeleteEndpoint(context.Context, *connect_go.Request[v1.DeleteEndpointRequest]) (*connect_go.Response[v1.DeleteEndpointResponse], error)
+	ListDeliveries(context.Context, *connect_go.Request[v1.ListDeliveriesRequest]) (*connect_go.Response[v1.ListDeliveriesResponse], error)
+	ReplayDelivery(context.Context, *connect_go.Request[v1.ReplayDeliveryRequest]) (*connect_go.Response[v1.ReplayDeliveryResponse], error)
+}
+
+// NewWebhookServiceClient constructs a client for the webhooks.v1.WebhookService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewWebhookServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) WebhookServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &webhookServiceClient{
+		createEndpoint: connect_go.NewClient[v1.CreateEndpointRequest, v1.CreateEndpointResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/CreateEndpoint",
+			opts...,
+		),
+		listEndpoints: connect_go.NewClient[v1.ListEndpointsRequest, v1.ListEndpointsResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/ListEndpoints",
+			opts...,
+		),
+		enableEndpoint: connect_go.NewClient[v1.EnableEndpointRequest, v1.EnableEndpointResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/EnableEndpoint",
+			opts...,
+		),
+		deleteEndpoint: connect_go.NewClient[v1.DeleteEndpointRequest, v1.DeleteEndpointResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/DeleteEndpoint",
+			opts...,
+		),
+		listDeliveries: connect_go.NewClient[v1.ListDeliveriesRequest, v1.ListDeliveriesResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/ListDeliveries",
+			opts...,
+		),
+		replayDelivery: connect_go.NewClient[v1.ReplayDeliveryRequest, v1.ReplayDeliveryResponse](
+			httpClient,
+			baseURL+"/webhooks.v1.WebhookService/ReplayDelivery",
+			opts...,
+		),
+	}
+}
+
+// webhookServiceClient implements WebhookServiceClient.
+type webhookServiceClient struct {
+	createEndpoint *connect_go.Client[v1.CreateEndpointRequest, v1.CreateEndpointResponse]
+	listEndpoints  *connect_go.Client[v1.ListEndpointsRequest, v1.ListEndpointsResponse]
+	enableEndpoint *connect_go.Client[v1.EnableEndpointRequest, v1.EnableEndpointResponse]
+	deleteEndpoint *connect_go.Client[v1.DeleteEndpointRequest, v1.DeleteEndpointResponse]
+	listDeliveries *connect_go.Client[v1.ListDeliveriesRequest, v1.ListDeliveriesResponse]
+	replayDelivery *connect_go.Client[v1.ReplayDeliveryRequest, v1.ReplayDeliveryResponse]
+}
+
+// CreateEndpoint calls webhooks.v1.WebhookService.CreateEndpoint.
+func (c *webhookServiceClient) CreateEndpoint(ctx context.Context, req *connect_go.Request[v1.CreateEndpointRequest]) (*connect_go.Response[v1.CreateEndpointResponse], error) {
+	return c.createEndpoint.CallUnary(ctx, req)
+}
+
+// ListEndpoints calls webhooks.v1.WebhookService.ListEndpoints.
+func (c *webhookServiceClient) ListEndpoints(ctx context.Context, req *connect_go.Request[v1.ListEndpointsRequest]) (*connect_go.Response[v1.ListEndpointsResponse], error) {
+	return c.listEndpoints.CallUnary(ctx, req)
+}
+
+// EnableEndpoint calls webhooks.v1.WebhookService.EnableEndpoint.
+func (c *webhookServiceClient) EnableEndpoint(ctx context.Context, req *connect_go.Request[v1.EnableEndpointRequest]) (*connect_go.Response[v1.EnableEndpointResponse], error) {
+	return c.enableEndpoint.CallUnary(ctx, req)
+}
+
+// DeleteEndpoint calls webhooks.v1.WebhookService.DeleteEndpoint.
+func (c *webhookServiceClient) DeleteEndpoint(ctx context.Context, req *connect_go.Request[v1.DeleteEndpointRequest]) (*connect_go.Response[v1.DeleteEndpointResponse], error) {
+	return c.deleteEndpoint.CallUnary(ctx, req)
+}
+
+// ListDeliveries calls webhooks.v1.WebhookService.ListDeliveries.
+func (c *webhookServiceClient) ListDeliveries(ctx context.Context, req *connect_go.Request[v1.ListDeliveriesRequest]) (*connect_go.Response[v1.ListDeliveriesResponse], error) {
+	return c.listDeliveries.CallUnary(ctx, req)
+}
+
+// ReplayDelivery calls webhooks.v1.WebhookService.ReplayDelivery.
+func (c *webhookServiceClient) ReplayDelivery(ctx context.Context, req *connect_go.Request[v1.ReplayDeliveryRequest]) (*connect_go.Response[v1.ReplayDeliveryResponse], error) {
+	return c.replayDelivery.CallUnary(ctx, req)
+}
+
+// WebhookServiceHandler is an implementation of the webhooks.v1.WebhookService service.
+type WebhookServiceHandler interface {
+	CreateEndpoint(context.Context, *connect_go.Request[v1.CreateEndpointRequest]) (*connect_go.Response[v1.CreateEndpointResponse], error)
+	ListEndpoints(context.Context, *connect_go.Request[v1.ListEndpointsRequest]) (*connect_go.Response[v1.ListEndpointsResponse], error)
+	EnableEndpoint(context.Context, *connect_go.Request[v1.EnableEndpointRequest]) (*connect_go.Response[v1.EnableEndpointResponse], error)
+	DeleteEndpoint(context.Context, *connect_go.Request[v1.DeleteEndpointRequest]) (*connect_go.Response[v1.DeleteEndpointResponse], error)
+	ListDeliveries(context.Context, *connect_go.Request[v1.ListDeliveriesRequest]) (*connect_go.Response[v1.ListDeliveriesResponse], error)
+	ReplayDelivery(context.Context, *connect_go.Request[v1.ReplayDeliveryRequest]) (*connect_go.Response[v1.ReplayDeliveryResponse], error)
+}
+
+// NewWebhookServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewWebhookServiceHandler(svc WebhookServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks.v1.WebhookService/CreateEndpoint", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/CreateEndpoint",
+		svc.CreateEndpoint,
+		opts...,
+	))
+	mux.Handle("/webhooks.v1.WebhookService/ListEndpoints", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/ListEndpoints",
+		svc.ListEndpoints,
+		opts...,
+	))
+	mux.Handle("/webhooks.v1.WebhookService/EnableEndpoint", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/EnableEndpoint",
+		svc.EnableEndpoint,
+		opts...,
+	))
+	mux.Handle("/webhooks.v1.WebhookService/DeleteEndpoint", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/DeleteEndpoint",
+		svc.DeleteEndpoint,
+		opts...,
+	))
+	mux.Handle("/webhooks.v1.WebhookService/ListDeliveries", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/ListDeliveries",
+		svc.ListDeliveries,
+		opts...,
+	))
+	mux.Handle("/webhooks.v1.WebhookService/ReplayDelivery", connect_go.NewUnaryHandler(
+		"/webhooks.v1.WebhookService/ReplayDelivery",
+		svc.ReplayDelivery,
+		opts...,
+	))
+	return "/webhooks.v1.WebhookService/", mux
+}
+
+// UnimplementedWebhookServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedWebhookServiceHandler struct{}
+
+func (UnimplementedWebhookServiceHandler) CreateEndpoint(context.Context, *connect_go.Request[v1.CreateEndpointRequest]) (*connect_go.Response[v1.CreateEndpointResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.CreateEndpoint is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ListEndpoints(context.Context, *connect_go.Request[v1.ListEndpointsRequest]) (*connect_go.Response[v1.ListEndpointsResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.ListEndpoints is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) EnableEndpoint(context.Context, *connect_go.Request[v1.EnableEndpointRequest]) (*connect_go.Response[v1.EnableEndpointResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.EnableEndpoint is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) DeleteEndpoint(context.Context, *connect_go.Request[v1.DeleteEndpointRequest]) (*connect_go.Response[v1.DeleteEndpointResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.DeleteEndpoint is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ListDeliveries(context.Context, *connect_go.Request[v1.ListDeliveriesRequest]) (*connect_go.Response[v1.ListDeliveriesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.ListDeliveries is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ReplayDelivery(context.Context, *connect_go.Request[v1.ReplayDeliveryRequest]) (*connect_go.Response[v1.ReplayDeliveryResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("webhooks.v1.WebhookService.ReplayDelivery is not implemented"))
+}
@@ -0,0 +1,55 @@
+// @generated by protoc-gen-connect-web v0.5.0 with parameter "target=ts"
+// @generated from file apikeys/v1/apikey.proto (package apikeys.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import { CreateApiKeyRequest, CreateApiKeyResponse, ListApiKeysRequest, ListApiKeysResponse, RevokeApiKeyRequest, RevokeApiKeyResponse, RotateApiKeyRequest, RotateApiKeyResponse } from "./apikey_pb.js";
+import { MethodKind } from "@bufbuild/protobuf";
+
+/**
+ * ApiKeyService manages the API keys of the caller, which machine clients present as `Authorization: Bearer pk_...`.
+ *
+ * @generated from service apikeys.v1.ApiKeyService
+ */
+export const ApiKeyService = {
+  typeName: "apikeys.v1.ApiKeyService",
+  methods: {
+    /**
+     * @generated from rpc apikeys.v1.ApiKeyService.CreateApiKey
+     */
+    createApiKey: {
+      name: "CreateApiKey",
+      I: CreateApiKeyRequest,
+      O: CreateApiKeyResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc apikeys.v1.ApiKeyService.ListApiKeys
+     */
+    listApiKeys: {
+      name: "ListApiKeys",
+      I: ListApiKeysRequest,
+      O: ListApiKeysResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc apikeys.v1.ApiKeyService.RotateApiKey
+     */
+    rotateApiKey: {
+      name: "RotateApiKey",
+      I: RotateApiKeyRequest,
+      O: RotateApiKeyResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc apikeys.v1.ApiKeyService.RevokeApiKey
+     */
+    revokeApiKey: {
+      name: "RevokeApiKey",
+      I: RevokeApiKeyRequest,
+      O: RevokeApiKeyResponse,
+      kind: MethodKind.Unary,
+    },
+  }
+} as const;
+
@@ -0,0 +1,419 @@
+// @generated by protoc-gen-es v1.0.0 with parameter "target=ts"
+// @generated from file apikeys/v1/apikey.proto (package apikeys.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import type { BinaryReadOptions, FieldList, JsonReadOptions, JsonValue, PartialMessage, PlainMessage } from "@bufbuild/protobuf";
+import { Message, proto3, protoInt64, Timestamp } from "@bufbuild/protobuf";
+
+/**
+ * @generated from message apikeys.v1.CreateApiKeyRequest
+ */
+export class CreateApiKeyRequest extends Message<CreateApiKeyRequest> {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name = "";
+
+  /**
+   * @generated from field: repeated string scopes = 2;
+   */
+  scopes: string[] = [];
+
+  /**
+   * @generated from field: int64 organization_id = 3;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp expires_at = 4;
+   */
+  expiresAt?: Timestamp;
+
+  constructor(data?: PartialMessage<CreateApiKeyRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.CreateApiKeyRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "name", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 2, name: "scopes", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+    { no: 3, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 4, name: "expires_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): CreateApiKeyRequest {
+    return new CreateApiKeyRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): CreateApiKeyRequest {
+    return new CreateApiKeyRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): CreateApiKeyRequest {
+    return new CreateApiKeyRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: CreateApiKeyRequest | PlainMessage<CreateApiKeyRequest> | undefined, b: CreateApiKeyRequest | PlainMessage<CreateApiKeyRequest> | undefined): boolean {
+    return proto3.util.equals(CreateApiKeyRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.CreateApiKeyResponse
+ */
+export class CreateApiKeyResponse extends Message<CreateApiKeyResponse> {
+  /**
+   * @generated from field: apikeys.v1.ApiKey api_key = 1;
+   */
+  apiKey?: ApiKey;
+
+  /**
+   * @generated from field: string secret = 2;
+   */
+  secret = "";
+
+  constructor(data?: PartialMessage<CreateApiKeyResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.CreateApiKeyResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "api_key", kind: "message", T: ApiKey },
+    { no: 2, name: "secret", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): CreateApiKeyResponse {
+    return new CreateApiKeyResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): CreateApiKeyResponse {
+    return new CreateApiKeyResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): CreateApiKeyResponse {
+    return new CreateApiKeyResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: CreateApiKeyResponse | PlainMessage<CreateApiKeyResponse> | undefined, b: CreateApiKeyResponse | PlainMessage<CreateApiKeyResponse> | undefined): boolean {
+    return proto3.util.equals(CreateApiKeyResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.ListApiKeysRequest
+ */
+export class ListApiKeysRequest extends Message<ListApiKeysRequest> {
+  constructor(data?: PartialMessage<ListApiKeysRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.ListApiKeysRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListApiKeysRequest {
+    return new ListApiKeysRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListApiKeysRequest {
+    return new ListApiKeysRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListApiKeysRequest {
+    return new ListApiKeysRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListApiKeysRequest | PlainMessage<ListApiKeysRequest> | undefined, b: ListApiKeysRequest | PlainMessage<ListApiKeysRequest> | undefined): boolean {
+    return proto3.util.equals(ListApiKeysRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.ListApiKeysResponse
+ */
+export class ListApiKeysResponse extends Message<ListApiKeysResponse> {
+  /**
+   * @generated from field: repeated apikeys.v1.ApiKey api_keys = 1;
+   */
+  apiKeys: ApiKey[] = [];
+
+  constructor(data?: PartialMessage<ListApiKeysResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.ListApiKeysResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "api_keys", kind: "message", T: ApiKey, repeated: true },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListApiKeysResponse {
+    return new ListApiKeysResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListApiKeysResponse {
+    return new ListApiKeysResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListApiKeysResponse {
+    return new ListApiKeysResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListApiKeysResponse | PlainMessage<ListApiKeysResponse> | undefined, b: ListApiKeysResponse | PlainMessage<ListApiKeysResponse> | undefined): boolean {
+    return proto3.util.equals(ListApiKeysResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.RotateApiKeyRequest
+ */
+export class RotateApiKeyRequest extends Message<RotateApiKeyRequest> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  constructor(data?: PartialMessage<RotateApiKeyRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.RotateApiKeyRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): RotateApiKeyRequest {
+    return new RotateApiKeyRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): RotateApiKeyRequest {
+    return new RotateApiKeyRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): RotateApiKeyRequest {
+    return new RotateApiKeyRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: RotateApiKeyRequest | PlainMessage<RotateApiKeyRequest> | undefined, b: RotateApiKeyRequest | PlainMessage<RotateApiKeyRequest> | undefined): boolean {
+    return proto3.util.equals(RotateApiKeyRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.RotateApiKeyResponse
+ */
+export class RotateApiKeyResponse extends Message<RotateApiKeyResponse> {
+  /**
+   * @generated from field: apikeys.v1.ApiKey api_key = 1;
+   */
+  apiKey?: ApiKey;
+
+  /**
+   * @generated from field: string secret = 2;
+   */
+  secret = "";
+
+  constructor(data?: PartialMessage<RotateApiKeyResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.RotateApiKeyResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "api_key", kind: "message", T: ApiKey },
+    { no: 2, name: "secret", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): RotateApiKeyResponse {
+    return new RotateApiKeyResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): RotateApiKeyResponse {
+    return new RotateApiKeyResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): RotateApiKeyResponse {
+    return new RotateApiKeyResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: RotateApiKeyResponse | PlainMessage<RotateApiKeyResponse> | undefined, b: RotateApiKeyResponse | PlainMessage<RotateApiKeyResponse> | undefined): boolean {
+    return proto3.util.equals(RotateApiKeyResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.RevokeApiKeyRequest
+ */
+export class RevokeApiKeyRequest extends Message<RevokeApiKeyRequest> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  constructor(data?: PartialMessage<RevokeApiKeyRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.RevokeApiKeyRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): RevokeApiKeyRequest {
+    return new RevokeApiKeyRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): RevokeApiKeyRequest {
+    return new RevokeApiKeyRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): RevokeApiKeyRequest {
+    return new RevokeApiKeyRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: RevokeApiKeyRequest | PlainMessage<RevokeApiKeyRequest> | undefined, b: RevokeApiKeyRequest | PlainMessage<RevokeApiKeyRequest> | undefined): boolean {
+    return proto3.util.equals(RevokeApiKeyRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.RevokeApiKeyResponse
+ */
+export class RevokeApiKeyResponse extends Message<RevokeApiKeyResponse> {
+  /**
+   * @generated from field: apikeys.v1.ApiKey api_key = 1;
+   */
+  apiKey?: ApiKey;
+
+  constructor(data?: PartialMessage<RevokeApiKeyResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.RevokeApiKeyResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "api_key", kind: "message", T: ApiKey },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): RevokeApiKeyResponse {
+    return new RevokeApiKeyResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): RevokeApiKeyResponse {
+    return new RevokeApiKeyResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): RevokeApiKeyResponse {
+    return new RevokeApiKeyResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: RevokeApiKeyResponse | PlainMessage<RevokeApiKeyResponse> | undefined, b: RevokeApiKeyResponse | PlainMessage<RevokeApiKeyResponse> | undefined): boolean {
+    return proto3.util.equals(RevokeApiKeyResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message apikeys.v1.ApiKey
+ */
+export class ApiKey extends Message<ApiKey> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  /**
+   * @generated from field: string prefix = 2;
+   */
+  prefix = "";
+
+  /**
+   * @generated from field: string name = 3;
+   */
+  name = "";
+
+  /**
+   * @generated from field: repeated string scopes = 4;
+   */
+  scopes: string[] = [];
+
+  /**
+   * @generated from field: int64 user_id = 5;
+   */
+  userId = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 organization_id = 6;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp expires_at = 7;
+   */
+  expiresAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp last_used_at = 8;
+   */
+  lastUsedAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp revoked_at = 9;
+   */
+  revokedAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 10;
+   */
+  createdAt?: Timestamp;
+
+  constructor(data?: PartialMessage<ApiKey>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "apikeys.v1.ApiKey";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "prefix", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 3, name: "name", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 4, name: "scopes", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+    { no: 5, name: "user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 6, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 7, name: "expires_at", kind: "message", T: Timestamp },
+    { no: 8, name: "last_used_at", kind: "message", T: Timestamp },
+    { no: 9, name: "revoked_at", kind: "message", T: Timestamp },
+    { no: 10, name: "created_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ApiKey {
+    return new ApiKey().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ApiKey {
+    return new ApiKey().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ApiKey {
+    return new ApiKey().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ApiKey | PlainMessage<ApiKey> | undefined, b: ApiKey | PlainMessage<ApiKey> | undefined): boolean {
+    return proto3.util.equals(ApiKey, a, b);
+  }
+}
+
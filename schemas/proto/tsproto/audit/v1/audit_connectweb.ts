@@ -0,0 +1,28 @@
+// @generated by protoc-gen-connect-web v0.5.0 with parameter "target=ts"
+// @generated from file audit/v1/audit.proto (package audit.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import { ListEventsRequest, ListEventsResponse } from "./audit_pb.js";
+import { MethodKind } from "@bufbuild/protobuf";
+
+/**
+ * AuditService reads the audit log, which records every mutation with the caller that made it.
+ *
+ * @generated from service audit.v1.AuditService
+ */
+export const AuditService = {
+  typeName: "audit.v1.AuditService",
+  methods: {
+    /**
+     * @generated from rpc audit.v1.AuditService.ListEvents
+     */
+    listEvents: {
+      name: "ListEvents",
+      I: ListEventsRequest,
+      O: ListEventsResponse,
+      kind: MethodKind.Unary,
+    },
+  }
+} as const;
+
@@ -0,0 +1,227 @@
+// @generated by protoc-gen-es v1.0.0 with parameter "target=ts"
+// @generated from file audit/v1/audit.proto (package audit.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import type { BinaryReadOptions, FieldList, JsonReadOptions, JsonValue, PartialMessage, PlainMessage } from "@bufbuild/protobuf";
+import { Message, proto3, protoInt64, Struct, Timestamp } from "@bufbuild/protobuf";
+
+/**
+ * @generated from message audit.v1.ListEventsRequest
+ */
+export class ListEventsRequest extends Message<ListEventsRequest> {
+  /**
+   * @generated from field: int64 first = 1;
+   */
+  first = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 after = 2;
+   */
+  after = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 actor_user_id = 3;
+   */
+  actorUserId = protoInt64.zero;
+
+  /**
+   * @generated from field: string entity_type = 4;
+   */
+  entityType = "";
+
+  /**
+   * @generated from field: string entity_id = 5;
+   */
+  entityId = "";
+
+  /**
+   * @generated from field: google.protobuf.Timestamp since = 6;
+   */
+  since?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp until = 7;
+   */
+  until?: Timestamp;
+
+  constructor(data?: PartialMessage<ListEventsRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "audit.v1.ListEventsRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "first", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "after", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "actor_user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 4, name: "entity_type", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 5, name: "entity_id", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 6, name: "since", kind: "message", T: Timestamp },
+    { no: 7, name: "until", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListEventsRequest {
+    return new ListEventsRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListEventsRequest {
+    return new ListEventsRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListEventsRequest {
+    return new ListEventsRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListEventsRequest | PlainMessage<ListEventsRequest> | undefined, b: ListEventsRequest | PlainMessage<ListEventsRequest> | undefined): boolean {
+    return proto3.util.equals(ListEventsRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message audit.v1.ListEventsResponse
+ */
+export class ListEventsResponse extends Message<ListEventsResponse> {
+  /**
+   * @generated from field: repeated audit.v1.Event events = 1;
+   */
+  events: Event[] = [];
+
+  /**
+   * @generated from field: int64 end_cursor = 2;
+   */
+  endCursor = protoInt64.zero;
+
+  /**
+   * @generated from field: bool has_next_page = 3;
+   */
+  hasNextPage = false;
+
+  constructor(data?: PartialMessage<ListEventsResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "audit.v1.ListEventsResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "events", kind: "message", T: Event, repeated: true },
+    { no: 2, name: "end_cursor", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "has_next_page", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListEventsResponse {
+    return new ListEventsResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListEventsResponse {
+    return new ListEventsResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListEventsResponse {
+    return new ListEventsResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListEventsResponse | PlainMessage<ListEventsResponse> | undefined, b: ListEventsResponse | PlainMessage<ListEventsResponse> | undefined): boolean {
+    return proto3.util.equals(ListEventsResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message audit.v1.Event
+ */
+export class Event extends Message<Event> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 actor_user_id = 2;
+   */
+  actorUserId = protoInt64.zero;
+
+  /**
+   * @generated from field: string actor_subject = 3;
+   */
+  actorSubject = "";
+
+  /**
+   * @generated from field: int64 impersonator_user_id = 4;
+   */
+  impersonatorUserId = protoInt64.zero;
+
+  /**
+   * @generated from field: string impersonator_subject = 5;
+   */
+  impersonatorSubject = "";
+
+  /**
+   * @generated from field: string procedure = 6;
+   */
+  procedure = "";
+
+  /**
+   * @generated from field: string entity_type = 7;
+   */
+  entityType = "";
+
+  /**
+   * @generated from field: string entity_id = 8;
+   */
+  entityId = "";
+
+  /**
+   * @generated from field: google.protobuf.Struct diff = 9;
+   */
+  diff?: Struct;
+
+  /**
+   * @generated from field: string request_id = 10;
+   */
+  requestId = "";
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 11;
+   */
+  createdAt?: Timestamp;
+
+  constructor(data?: PartialMessage<Event>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "audit.v1.Event";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "actor_user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "actor_subject", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 4, name: "impersonator_user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 5, name: "impersonator_subject", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 6, name: "procedure", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 7, name: "entity_type", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 8, name: "entity_id", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 9, name: "diff", kind: "message", T: Struct },
+    { no: 10, name: "request_id", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 11, name: "created_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Event {
+    return new Event().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Event {
+    return new Event().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Event {
+    return new Event().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Event | PlainMessage<Event> | undefined, b: Event | PlainMessage<Event> | undefined): boolean {
+    return proto3.util.equals(Event, a, b);
+  }
+}
+
@@ -0,0 +1,234 @@
+// @generated by protoc-gen-es v1.0.0 with parameter "target=ts"
+// @generated from file events/v1/events.proto (package events.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import type { BinaryReadOptions, FieldList, JsonReadOptions, JsonValue, PartialMessage, PlainMessage } from "@bufbuild/protobuf";
+import { Message, proto3, protoInt64 } from "@bufbuild/protobuf";
+import { User } from "../../users/v1/user_pb.js";
+
+/**
+ * UserRegistered is published once a user has registered.
+ *
+ * @generated from message events.v1.UserRegistered
+ */
+export class UserRegistered extends Message<UserRegistered> {
+  /**
+   * @generated from field: users.v1.User user = 1;
+   */
+  user?: User;
+
+  constructor(data?: PartialMessage<UserRegistered>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "events.v1.UserRegistered";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "user", kind: "message", T: User },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): UserRegistered {
+    return new UserRegistered().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): UserRegistered {
+    return new UserRegistered().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): UserRegistered {
+    return new UserRegistered().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: UserRegistered | PlainMessage<UserRegistered> | undefined, b: UserRegistered | PlainMessage<UserRegistered> | undefined): boolean {
+    return proto3.util.equals(UserRegistered, a, b);
+  }
+}
+
+/**
+ * UserModified is published once a user has been modified, with the user as it now stands.
+ *
+ * @generated from message events.v1.UserModified
+ */
+export class UserModified extends Message<UserModified> {
+  /**
+   * @generated from field: users.v1.User user = 1;
+   */
+  user?: User;
+
+  constructor(data?: PartialMessage<UserModified>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "events.v1.UserModified";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "user", kind: "message", T: User },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): UserModified {
+    return new UserModified().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): UserModified {
+    return new UserModified().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): UserModified {
+    return new UserModified().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: UserModified | PlainMessage<UserModified> | undefined, b: UserModified | PlainMessage<UserModified> | undefined): boolean {
+    return proto3.util.equals(UserModified, a, b);
+  }
+}
+
+/**
+ * UserDeleted is published once a user has been deleted.
+ *
+ * @generated from message events.v1.UserDeleted
+ */
+export class UserDeleted extends Message<UserDeleted> {
+  /**
+   * @generated from field: int64 user_id = 1;
+   */
+  userId = protoInt64.zero;
+
+  constructor(data?: PartialMessage<UserDeleted>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "events.v1.UserDeleted";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): UserDeleted {
+    return new UserDeleted().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): UserDeleted {
+    return new UserDeleted().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): UserDeleted {
+    return new UserDeleted().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: UserDeleted | PlainMessage<UserDeleted> | undefined, b: UserDeleted | PlainMessage<UserDeleted> | undefined): boolean {
+    return proto3.util.equals(UserDeleted, a, b);
+  }
+}
+
+/**
+ * OrganizationMemberAdded is published once a user has joined an organization.
+ *
+ * @generated from message events.v1.OrganizationMemberAdded
+ */
+export class OrganizationMemberAdded extends Message<OrganizationMemberAdded> {
+  /**
+   * @generated from field: int64 organization_id = 1;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 user_id = 2;
+   */
+  userId = protoInt64.zero;
+
+  /**
+   * @generated from field: string role = 3;
+   */
+  role = "";
+
+  constructor(data?: PartialMessage<OrganizationMemberAdded>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "events.v1.OrganizationMemberAdded";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "role", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): OrganizationMemberAdded {
+    return new OrganizationMemberAdded().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): OrganizationMemberAdded {
+    return new OrganizationMemberAdded().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): OrganizationMemberAdded {
+    return new OrganizationMemberAdded().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: OrganizationMemberAdded | PlainMessage<OrganizationMemberAdded> | undefined, b: OrganizationMemberAdded | PlainMessage<OrganizationMemberAdded> | undefined): boolean {
+    return proto3.util.equals(OrganizationMemberAdded, a, b);
+  }
+}
+
+/**
+ * OrganizationMemberRoleChanged is published once the role of an organization member has changed.
+ *
+ * @generated from message events.v1.OrganizationMemberRoleChanged
+ */
+export class OrganizationMemberRoleChanged extends Message<OrganizationMemberRoleChanged> {
+  /**
+   * @generated from field: int64 organization_id = 1;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 user_id = 2;
+   */
+  userId = protoInt64.zero;
+
+  /**
+   * @generated from field: string previous_role = 3;
+   */
+  previousRole = "";
+
+  /**
+   * @generated from field: string role = 4;
+   */
+  role = "";
+
+  constructor(data?: PartialMessage<OrganizationMemberRoleChanged>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "events.v1.OrganizationMemberRoleChanged";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "previous_role", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 4, name: "role", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): OrganizationMemberRoleChanged {
+    return new OrganizationMemberRoleChanged().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): OrganizationMemberRoleChanged {
+    return new OrganizationMemberRoleChanged().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): OrganizationMemberRoleChanged {
+    return new OrganizationMemberRoleChanged().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: OrganizationMemberRoleChanged | PlainMessage<OrganizationMemberRoleChanged> | undefined, b: OrganizationMemberRoleChanged | PlainMessage<OrganizationMemberRoleChanged> | undefined): boolean {
+    return proto3.util.equals(OrganizationMemberRoleChanged, a, b);
+  }
+}
+
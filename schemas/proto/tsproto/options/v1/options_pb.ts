@@ -0,0 +1,112 @@
+// @generated by protoc-gen-es v1.0.0 with parameter "target=ts"
+// @generated from file options/v1/options.proto (package options.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import type { BinaryReadOptions, FieldList, JsonReadOptions, JsonValue, PartialMessage, PlainMessage } from "@bufbuild/protobuf";
+import { Message, proto3 } from "@bufbuild/protobuf";
+
+/**
+ * Rule grants callers access to a method.
+ *
+ * @generated from enum options.v1.Rule
+ */
+export enum Rule {
+  /**
+   * @generated from enum value: RULE_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Anyone, authenticated or not.
+   *
+   * @generated from enum value: RULE_PUBLIC = 1;
+   */
+  PUBLIC = 1,
+
+  /**
+   * Any authenticated caller.
+   *
+   * @generated from enum value: RULE_AUTHENTICATED = 2;
+   */
+  AUTHENTICATED = 2,
+
+  /**
+   * Callers acting on their own user only.
+   *
+   * @generated from enum value: RULE_SELF = 3;
+   */
+  SELF = 3,
+
+  /**
+   * Admins of an organization that every user acted on is a member of.
+   *
+   * @generated from enum value: RULE_ORG_ADMIN = 4;
+   */
+  ORG_ADMIN = 4,
+
+  /**
+   * Global admins.
+   *
+   * @generated from enum value: RULE_ADMIN = 5;
+   */
+  ADMIN = 5,
+}
+// Retrieve enum metadata with: proto3.getEnumType(Rule)
+proto3.util.setEnumType(Rule, "options.v1.Rule", [
+  { no: 0, name: "RULE_UNSPECIFIED" },
+  { no: 1, name: "RULE_PUBLIC" },
+  { no: 2, name: "RULE_AUTHENTICATED" },
+  { no: 3, name: "RULE_SELF" },
+  { no: 4, name: "RULE_ORG_ADMIN" },
+  { no: 5, name: "RULE_ADMIN" },
+]);
+
+/**
+ * Access declares who may call a method. Callers are allowed if any of the rules allows them.
+ *
+ * @generated from message options.v1.Access
+ */
+export class Access extends Message<Access> {
+  /**
+   * @generated from field: repeated options.v1.Rule allow = 1;
+   */
+  allow: Rule[] = [];
+
+  /**
+   * subject is the path of the request field holding the IDs of the users acted on, eg: `user.id`, for the SELF and
+   * ORG_ADMIN rules.
+   *
+   * @generated from field: string subject = 2;
+   */
+  subject = "";
+
+  constructor(data?: PartialMessage<Access>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "options.v1.Access";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "allow", kind: "enum", T: proto3.getEnumType(Rule), repeated: true },
+    { no: 2, name: "subject", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Access {
+    return new Access().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Access {
+    return new Access().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Access {
+    return new Access().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Access | PlainMessage<Access> | undefined, b: Access | PlainMessage<Access> | undefined): boolean {
+    return proto3.util.equals(Access, a, b);
+  }
+}
+
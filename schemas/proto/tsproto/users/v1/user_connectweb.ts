@@ -3,7 +3,7 @@
 /* eslint-disable */
 // @ts-nocheck
 
-import { DeleteUserRequest, DeleteUserResponse, ModifyUserRequest, ModifyUserResponse, RegisterUserRequest, RegisterUserResponse, RetrieveUserRequest, RetrieveUserResponse, RetrieveUsersPageRequest, RetrieveUsersPageResponse, RetrieveUsersRequest, RetrieveUsersResponse } from "./user_pb.js";
+import { DeleteUserRequest, DeleteUserResponse, ModifyUserRequest, ModifyUserResponse, RegisterUserRequest, RegisterUserResponse, RetrieveUserRequest, RetrieveUserResponse, RetrieveUsersPageRequest, RetrieveUsersPageResponse, RetrieveUsersRequest, RetrieveUsersResponse, WatchUsersRequest, WatchUsersResponse } from "./user_pb.js";
 import { MethodKind } from "@bufbuild/protobuf";
 
 /**
@@ -66,6 +66,20 @@ export const UserService = {
       O: RetrieveUsersPageResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * WatchUsers streams the changes to users as they are committed, with a heartbeat whenever none have been sent for a
+     * while. Watchers resume after a disconnect by passing the cursor of the last message they received, and must
+     * expect to receive a change more than once. Only admins may watch every user, and organization admins the members
+     * of their organization.
+     *
+     * @generated from rpc users.v1.UserService.WatchUsers
+     */
+    watchUsers: {
+      name: "WatchUsers",
+      I: WatchUsersRequest,
+      O: WatchUsersResponse,
+      kind: MethodKind.ServerStreaming,
+    },
   }
 } as const;
 
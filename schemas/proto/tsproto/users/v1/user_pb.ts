@@ -32,6 +32,40 @@ proto3.util.setEnumType(Direction, "users.v1.Direction", [
   { no: 2, name: "DIRECTION_BACKWARD" },
 ]);
 
+/**
+ * Operation is what happened to a user in a UserChange.
+ *
+ * @generated from enum users.v1.Operation
+ */
+export enum Operation {
+  /**
+   * @generated from enum value: OPERATION_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: OPERATION_CREATED = 1;
+   */
+  CREATED = 1,
+
+  /**
+   * @generated from enum value: OPERATION_UPDATED = 2;
+   */
+  UPDATED = 2,
+
+  /**
+   * @generated from enum value: OPERATION_DELETED = 3;
+   */
+  DELETED = 3,
+}
+// Retrieve enum metadata with: proto3.getEnumType(Operation)
+proto3.util.setEnumType(Operation, "users.v1.Operation", [
+  { no: 0, name: "OPERATION_UNSPECIFIED" },
+  { no: 1, name: "OPERATION_CREATED" },
+  { no: 2, name: "OPERATION_UPDATED" },
+  { no: 3, name: "OPERATION_DELETED" },
+]);
+
 /**
  * @generated from message users.v1.DeleteUserRequest
  */
@@ -634,3 +668,200 @@ export class User extends Message<User> {
   }
 }
 
+/**
+ * @generated from message users.v1.WatchUsersRequest
+ */
+export class WatchUsersRequest extends Message<WatchUsersRequest> {
+  /**
+   * @generated from field: int64 organization_id = 1;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 after = 2;
+   */
+  after = protoInt64.zero;
+
+  constructor(data?: PartialMessage<WatchUsersRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "users.v1.WatchUsersRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "after", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): WatchUsersRequest {
+    return new WatchUsersRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): WatchUsersRequest {
+    return new WatchUsersRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): WatchUsersRequest {
+    return new WatchUsersRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: WatchUsersRequest | PlainMessage<WatchUsersRequest> | undefined, b: WatchUsersRequest | PlainMessage<WatchUsersRequest> | undefined): boolean {
+    return proto3.util.equals(WatchUsersRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message users.v1.WatchUsersResponse
+ */
+export class WatchUsersResponse extends Message<WatchUsersResponse> {
+  /**
+   * @generated from oneof users.v1.WatchUsersResponse.event
+   */
+  event: {
+    /**
+     * @generated from field: users.v1.UserChange change = 1;
+     */
+    value: UserChange;
+    case: "change";
+  } | {
+    /**
+     * @generated from field: users.v1.Heartbeat heartbeat = 2;
+     */
+    value: Heartbeat;
+    case: "heartbeat";
+  } | { case: undefined; value?: undefined } = { case: undefined };
+
+  constructor(data?: PartialMessage<WatchUsersResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "users.v1.WatchUsersResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "change", kind: "message", T: UserChange, oneof: "event" },
+    { no: 2, name: "heartbeat", kind: "message", T: Heartbeat, oneof: "event" },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): WatchUsersResponse {
+    return new WatchUsersResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): WatchUsersResponse {
+    return new WatchUsersResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): WatchUsersResponse {
+    return new WatchUsersResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: WatchUsersResponse | PlainMessage<WatchUsersResponse> | undefined, b: WatchUsersResponse | PlainMessage<WatchUsersResponse> | undefined): boolean {
+    return proto3.util.equals(WatchUsersResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message users.v1.UserChange
+ */
+export class UserChange extends Message<UserChange> {
+  /**
+   * @generated from field: int64 cursor = 1;
+   */
+  cursor = protoInt64.zero;
+
+  /**
+   * @generated from field: users.v1.Operation operation = 2;
+   */
+  operation = Operation.UNSPECIFIED;
+
+  /**
+   * @generated from field: int64 user_id = 3;
+   */
+  userId = protoInt64.zero;
+
+  /**
+   * @generated from field: users.v1.User user = 4;
+   */
+  user?: User;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp changed_at = 5;
+   */
+  changedAt?: Timestamp;
+
+  constructor(data?: PartialMessage<UserChange>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "users.v1.UserChange";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "cursor", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "operation", kind: "enum", T: proto3.getEnumType(Operation) },
+    { no: 3, name: "user_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 4, name: "user", kind: "message", T: User },
+    { no: 5, name: "changed_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): UserChange {
+    return new UserChange().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): UserChange {
+    return new UserChange().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): UserChange {
+    return new UserChange().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: UserChange | PlainMessage<UserChange> | undefined, b: UserChange | PlainMessage<UserChange> | undefined): boolean {
+    return proto3.util.equals(UserChange, a, b);
+  }
+}
+
+/**
+ * @generated from message users.v1.Heartbeat
+ */
+export class Heartbeat extends Message<Heartbeat> {
+  /**
+   * @generated from field: int64 cursor = 1;
+   */
+  cursor = protoInt64.zero;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp sent_at = 2;
+   */
+  sentAt?: Timestamp;
+
+  constructor(data?: PartialMessage<Heartbeat>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "users.v1.Heartbeat";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "cursor", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "sent_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Heartbeat {
+    return new Heartbeat().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Heartbeat {
+    return new Heartbeat().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Heartbeat {
+    return new Heartbeat().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Heartbeat | PlainMessage<Heartbeat> | undefined, b: Heartbeat | PlainMessage<Heartbeat> | undefined): boolean {
+    return proto3.util.equals(Heartbeat, a, b);
+  }
+}
+
@@ -0,0 +1,75 @@
+// @generated by protoc-gen-connect-web v0.5.0 with parameter "target=ts"
+// @generated from file webhooks/v1/webhooks.proto (package webhooks.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import { CreateEndpointRequest, CreateEndpointResponse, DeleteEndpointRequest, DeleteEndpointResponse, EnableEndpointRequest, EnableEndpointResponse, ListDeliveriesRequest, ListDeliveriesResponse, ListEndpointsRequest, ListEndpointsResponse, ReplayDeliveryRequest, ReplayDeliveryResponse } from "./webhooks_pb.js";
+import { MethodKind } from "@bufbuild/protobuf";
+
+/**
+ * WebhookService manages the endpoints organizations receive domain events on, see `events.v1`. Each delivery is a
+ * POST of the event as JSON, signed with the endpoint's secret in the `X-Perspex-Signature` header, as
+ * `t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">`. Only organization admins may manage its endpoints.
+ *
+ * @generated from service webhooks.v1.WebhookService
+ */
+export const WebhookService = {
+  typeName: "webhooks.v1.WebhookService",
+  methods: {
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.CreateEndpoint
+     */
+    createEndpoint: {
+      name: "CreateEndpoint",
+      I: CreateEndpointRequest,
+      O: CreateEndpointResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.ListEndpoints
+     */
+    listEndpoints: {
+      name: "ListEndpoints",
+      I: ListEndpointsRequest,
+      O: ListEndpointsResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.EnableEndpoint
+     */
+    enableEndpoint: {
+      name: "EnableEndpoint",
+      I: EnableEndpointRequest,
+      O: EnableEndpointResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.DeleteEndpoint
+     */
+    deleteEndpoint: {
+      name: "DeleteEndpoint",
+      I: DeleteEndpointRequest,
+      O: DeleteEndpointResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.ListDeliveries
+     */
+    listDeliveries: {
+      name: "ListDeliveries",
+      I: ListDeliveriesRequest,
+      O: ListDeliveriesResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc webhooks.v1.WebhookService.ReplayDelivery
+     */
+    replayDelivery: {
+      name: "ReplayDelivery",
+      I: ReplayDeliveryRequest,
+      O: ReplayDeliveryResponse,
+      kind: MethodKind.Unary,
+    },
+  }
+} as const;
+
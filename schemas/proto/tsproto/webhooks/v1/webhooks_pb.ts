@@ -0,0 +1,765 @@
+// @generated by protoc-gen-es v1.0.0 with parameter "target=ts"
+// @generated from file webhooks/v1/webhooks.proto (package webhooks.v1, syntax proto3)
+/* eslint-disable */
+// @ts-nocheck
+
+import type { BinaryReadOptions, FieldList, JsonReadOptions, JsonValue, PartialMessage, PlainMessage } from "@bufbuild/protobuf";
+import { Duration, Message, proto3, protoInt64, Timestamp } from "@bufbuild/protobuf";
+
+/**
+ * DeliveryStatus is the outcome of a delivery.
+ *
+ * @generated from enum webhooks.v1.DeliveryStatus
+ */
+export enum DeliveryStatus {
+  /**
+   * @generated from enum value: DELIVERY_STATUS_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Awaiting its next attempt.
+   *
+   * @generated from enum value: DELIVERY_STATUS_PENDING = 1;
+   */
+  PENDING = 1,
+
+  /**
+   * Acknowledged by the endpoint with a 2xx status.
+   *
+   * @generated from enum value: DELIVERY_STATUS_DELIVERED = 2;
+   */
+  DELIVERED = 2,
+
+  /**
+   * Given up on after its last attempt failed.
+   *
+   * @generated from enum value: DELIVERY_STATUS_FAILED = 3;
+   */
+  FAILED = 3,
+}
+// Retrieve enum metadata with: proto3.getEnumType(DeliveryStatus)
+proto3.util.setEnumType(DeliveryStatus, "webhooks.v1.DeliveryStatus", [
+  { no: 0, name: "DELIVERY_STATUS_UNSPECIFIED" },
+  { no: 1, name: "DELIVERY_STATUS_PENDING" },
+  { no: 2, name: "DELIVERY_STATUS_DELIVERED" },
+  { no: 3, name: "DELIVERY_STATUS_FAILED" },
+]);
+
+/**
+ * @generated from message webhooks.v1.CreateEndpointRequest
+ */
+export class CreateEndpointRequest extends Message<CreateEndpointRequest> {
+  /**
+   * @generated from field: int64 organization_id = 1;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: string url = 2;
+   */
+  url = "";
+
+  /**
+   * @generated from field: repeated string event_types = 3;
+   */
+  eventTypes: string[] = [];
+
+  /**
+   * @generated from field: string description = 4;
+   */
+  description = "";
+
+  constructor(data?: PartialMessage<CreateEndpointRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.CreateEndpointRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "url", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 3, name: "event_types", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+    { no: 4, name: "description", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): CreateEndpointRequest {
+    return new CreateEndpointRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): CreateEndpointRequest {
+    return new CreateEndpointRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): CreateEndpointRequest {
+    return new CreateEndpointRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: CreateEndpointRequest | PlainMessage<CreateEndpointRequest> | undefined, b: CreateEndpointRequest | PlainMessage<CreateEndpointRequest> | undefined): boolean {
+    return proto3.util.equals(CreateEndpointRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.CreateEndpointResponse
+ */
+export class CreateEndpointResponse extends Message<CreateEndpointResponse> {
+  /**
+   * @generated from field: webhooks.v1.Endpoint endpoint = 1;
+   */
+  endpoint?: Endpoint;
+
+  /**
+   * @generated from field: string secret = 2;
+   */
+  secret = "";
+
+  constructor(data?: PartialMessage<CreateEndpointResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.CreateEndpointResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "endpoint", kind: "message", T: Endpoint },
+    { no: 2, name: "secret", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): CreateEndpointResponse {
+    return new CreateEndpointResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): CreateEndpointResponse {
+    return new CreateEndpointResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): CreateEndpointResponse {
+    return new CreateEndpointResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: CreateEndpointResponse | PlainMessage<CreateEndpointResponse> | undefined, b: CreateEndpointResponse | PlainMessage<CreateEndpointResponse> | undefined): boolean {
+    return proto3.util.equals(CreateEndpointResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ListEndpointsRequest
+ */
+export class ListEndpointsRequest extends Message<ListEndpointsRequest> {
+  /**
+   * @generated from field: int64 organization_id = 1;
+   */
+  organizationId = protoInt64.zero;
+
+  constructor(data?: PartialMessage<ListEndpointsRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ListEndpointsRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListEndpointsRequest {
+    return new ListEndpointsRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListEndpointsRequest {
+    return new ListEndpointsRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListEndpointsRequest {
+    return new ListEndpointsRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListEndpointsRequest | PlainMessage<ListEndpointsRequest> | undefined, b: ListEndpointsRequest | PlainMessage<ListEndpointsRequest> | undefined): boolean {
+    return proto3.util.equals(ListEndpointsRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ListEndpointsResponse
+ */
+export class ListEndpointsResponse extends Message<ListEndpointsResponse> {
+  /**
+   * @generated from field: repeated webhooks.v1.Endpoint endpoints = 1;
+   */
+  endpoints: Endpoint[] = [];
+
+  constructor(data?: PartialMessage<ListEndpointsResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ListEndpointsResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "endpoints", kind: "message", T: Endpoint, repeated: true },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListEndpointsResponse {
+    return new ListEndpointsResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListEndpointsResponse {
+    return new ListEndpointsResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListEndpointsResponse {
+    return new ListEndpointsResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListEndpointsResponse | PlainMessage<ListEndpointsResponse> | undefined, b: ListEndpointsResponse | PlainMessage<ListEndpointsResponse> | undefined): boolean {
+    return proto3.util.equals(ListEndpointsResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.EnableEndpointRequest
+ */
+export class EnableEndpointRequest extends Message<EnableEndpointRequest> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  constructor(data?: PartialMessage<EnableEndpointRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.EnableEndpointRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): EnableEndpointRequest {
+    return new EnableEndpointRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): EnableEndpointRequest {
+    return new EnableEndpointRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): EnableEndpointRequest {
+    return new EnableEndpointRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: EnableEndpointRequest | PlainMessage<EnableEndpointRequest> | undefined, b: EnableEndpointRequest | PlainMessage<EnableEndpointRequest> | undefined): boolean {
+    return proto3.util.equals(EnableEndpointRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.EnableEndpointResponse
+ */
+export class EnableEndpointResponse extends Message<EnableEndpointResponse> {
+  /**
+   * @generated from field: webhooks.v1.Endpoint endpoint = 1;
+   */
+  endpoint?: Endpoint;
+
+  constructor(data?: PartialMessage<EnableEndpointResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.EnableEndpointResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "endpoint", kind: "message", T: Endpoint },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): EnableEndpointResponse {
+    return new EnableEndpointResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): EnableEndpointResponse {
+    return new EnableEndpointResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): EnableEndpointResponse {
+    return new EnableEndpointResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: EnableEndpointResponse | PlainMessage<EnableEndpointResponse> | undefined, b: EnableEndpointResponse | PlainMessage<EnableEndpointResponse> | undefined): boolean {
+    return proto3.util.equals(EnableEndpointResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.DeleteEndpointRequest
+ */
+export class DeleteEndpointRequest extends Message<DeleteEndpointRequest> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  constructor(data?: PartialMessage<DeleteEndpointRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.DeleteEndpointRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): DeleteEndpointRequest {
+    return new DeleteEndpointRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): DeleteEndpointRequest {
+    return new DeleteEndpointRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): DeleteEndpointRequest {
+    return new DeleteEndpointRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: DeleteEndpointRequest | PlainMessage<DeleteEndpointRequest> | undefined, b: DeleteEndpointRequest | PlainMessage<DeleteEndpointRequest> | undefined): boolean {
+    return proto3.util.equals(DeleteEndpointRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.DeleteEndpointResponse
+ */
+export class DeleteEndpointResponse extends Message<DeleteEndpointResponse> {
+  constructor(data?: PartialMessage<DeleteEndpointResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.DeleteEndpointResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): DeleteEndpointResponse {
+    return new DeleteEndpointResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): DeleteEndpointResponse {
+    return new DeleteEndpointResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): DeleteEndpointResponse {
+    return new DeleteEndpointResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: DeleteEndpointResponse | PlainMessage<DeleteEndpointResponse> | undefined, b: DeleteEndpointResponse | PlainMessage<DeleteEndpointResponse> | undefined): boolean {
+    return proto3.util.equals(DeleteEndpointResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ListDeliveriesRequest
+ */
+export class ListDeliveriesRequest extends Message<ListDeliveriesRequest> {
+  /**
+   * @generated from field: int64 endpoint_id = 1;
+   */
+  endpointId = protoInt64.zero;
+
+  /**
+   * @generated from field: int32 first = 2;
+   */
+  first = 0;
+
+  /**
+   * @generated from field: int64 after = 3;
+   */
+  after = protoInt64.zero;
+
+  constructor(data?: PartialMessage<ListDeliveriesRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ListDeliveriesRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "endpoint_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "first", kind: "scalar", T: 5 /* ScalarType.INT32 */ },
+    { no: 3, name: "after", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListDeliveriesRequest {
+    return new ListDeliveriesRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListDeliveriesRequest {
+    return new ListDeliveriesRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListDeliveriesRequest {
+    return new ListDeliveriesRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListDeliveriesRequest | PlainMessage<ListDeliveriesRequest> | undefined, b: ListDeliveriesRequest | PlainMessage<ListDeliveriesRequest> | undefined): boolean {
+    return proto3.util.equals(ListDeliveriesRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ListDeliveriesResponse
+ */
+export class ListDeliveriesResponse extends Message<ListDeliveriesResponse> {
+  /**
+   * @generated from field: repeated webhooks.v1.Delivery deliveries = 1;
+   */
+  deliveries: Delivery[] = [];
+
+  /**
+   * @generated from field: int64 end_cursor = 2;
+   */
+  endCursor = protoInt64.zero;
+
+  /**
+   * @generated from field: bool has_next_page = 3;
+   */
+  hasNextPage = false;
+
+  constructor(data?: PartialMessage<ListDeliveriesResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ListDeliveriesResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "deliveries", kind: "message", T: Delivery, repeated: true },
+    { no: 2, name: "end_cursor", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "has_next_page", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ListDeliveriesResponse {
+    return new ListDeliveriesResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ListDeliveriesResponse {
+    return new ListDeliveriesResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ListDeliveriesResponse {
+    return new ListDeliveriesResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ListDeliveriesResponse | PlainMessage<ListDeliveriesResponse> | undefined, b: ListDeliveriesResponse | PlainMessage<ListDeliveriesResponse> | undefined): boolean {
+    return proto3.util.equals(ListDeliveriesResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ReplayDeliveryRequest
+ */
+export class ReplayDeliveryRequest extends Message<ReplayDeliveryRequest> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  constructor(data?: PartialMessage<ReplayDeliveryRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ReplayDeliveryRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ReplayDeliveryRequest {
+    return new ReplayDeliveryRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ReplayDeliveryRequest {
+    return new ReplayDeliveryRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ReplayDeliveryRequest {
+    return new ReplayDeliveryRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ReplayDeliveryRequest | PlainMessage<ReplayDeliveryRequest> | undefined, b: ReplayDeliveryRequest | PlainMessage<ReplayDeliveryRequest> | undefined): boolean {
+    return proto3.util.equals(ReplayDeliveryRequest, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.ReplayDeliveryResponse
+ */
+export class ReplayDeliveryResponse extends Message<ReplayDeliveryResponse> {
+  /**
+   * @generated from field: webhooks.v1.Delivery delivery = 1;
+   */
+  delivery?: Delivery;
+
+  constructor(data?: PartialMessage<ReplayDeliveryResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.ReplayDeliveryResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "delivery", kind: "message", T: Delivery },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): ReplayDeliveryResponse {
+    return new ReplayDeliveryResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): ReplayDeliveryResponse {
+    return new ReplayDeliveryResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): ReplayDeliveryResponse {
+    return new ReplayDeliveryResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: ReplayDeliveryResponse | PlainMessage<ReplayDeliveryResponse> | undefined, b: ReplayDeliveryResponse | PlainMessage<ReplayDeliveryResponse> | undefined): boolean {
+    return proto3.util.equals(ReplayDeliveryResponse, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.Endpoint
+ */
+export class Endpoint extends Message<Endpoint> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 organization_id = 2;
+   */
+  organizationId = protoInt64.zero;
+
+  /**
+   * @generated from field: string url = 3;
+   */
+  url = "";
+
+  /**
+   * @generated from field: repeated string event_types = 4;
+   */
+  eventTypes: string[] = [];
+
+  /**
+   * @generated from field: string description = 5;
+   */
+  description = "";
+
+  /**
+   * @generated from field: int32 failures = 6;
+   */
+  failures = 0;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp disabled_at = 7;
+   */
+  disabledAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 8;
+   */
+  createdAt?: Timestamp;
+
+  constructor(data?: PartialMessage<Endpoint>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.Endpoint";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "organization_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "url", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 4, name: "event_types", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+    { no: 5, name: "description", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 6, name: "failures", kind: "scalar", T: 5 /* ScalarType.INT32 */ },
+    { no: 7, name: "disabled_at", kind: "message", T: Timestamp },
+    { no: 8, name: "created_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Endpoint {
+    return new Endpoint().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Endpoint {
+    return new Endpoint().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Endpoint {
+    return new Endpoint().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Endpoint | PlainMessage<Endpoint> | undefined, b: Endpoint | PlainMessage<Endpoint> | undefined): boolean {
+    return proto3.util.equals(Endpoint, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.Delivery
+ */
+export class Delivery extends Message<Delivery> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 endpoint_id = 2;
+   */
+  endpointId = protoInt64.zero;
+
+  /**
+   * @generated from field: int64 event_id = 3;
+   */
+  eventId = protoInt64.zero;
+
+  /**
+   * @generated from field: string event_type = 4;
+   */
+  eventType = "";
+
+  /**
+   * @generated from field: int64 replay_of = 5;
+   */
+  replayOf = protoInt64.zero;
+
+  /**
+   * @generated from field: webhooks.v1.DeliveryStatus status = 6;
+   */
+  status = DeliveryStatus.UNSPECIFIED;
+
+  /**
+   * @generated from field: repeated webhooks.v1.Attempt attempts = 7;
+   */
+  attempts: Attempt[] = [];
+
+  /**
+   * @generated from field: google.protobuf.Timestamp next_attempt_at = 8;
+   */
+  nextAttemptAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp delivered_at = 9;
+   */
+  deliveredAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 10;
+   */
+  createdAt?: Timestamp;
+
+  constructor(data?: PartialMessage<Delivery>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.Delivery";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "endpoint_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 3, name: "event_id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 4, name: "event_type", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 5, name: "replay_of", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 6, name: "status", kind: "enum", T: proto3.getEnumType(DeliveryStatus) },
+    { no: 7, name: "attempts", kind: "message", T: Attempt, repeated: true },
+    { no: 8, name: "next_attempt_at", kind: "message", T: Timestamp },
+    { no: 9, name: "delivered_at", kind: "message", T: Timestamp },
+    { no: 10, name: "created_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Delivery {
+    return new Delivery().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Delivery {
+    return new Delivery().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Delivery {
+    return new Delivery().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Delivery | PlainMessage<Delivery> | undefined, b: Delivery | PlainMessage<Delivery> | undefined): boolean {
+    return proto3.util.equals(Delivery, a, b);
+  }
+}
+
+/**
+ * @generated from message webhooks.v1.Attempt
+ */
+export class Attempt extends Message<Attempt> {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id = protoInt64.zero;
+
+  /**
+   * @generated from field: int32 status_code = 2;
+   */
+  statusCode = 0;
+
+  /**
+   * @generated from field: string error = 3;
+   */
+  error = "";
+
+  /**
+   * @generated from field: google.protobuf.Duration duration = 4;
+   */
+  duration?: Duration;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp attempted_at = 5;
+   */
+  attemptedAt?: Timestamp;
+
+  constructor(data?: PartialMessage<Attempt>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime = proto3;
+  static readonly typeName = "webhooks.v1.Attempt";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "id", kind: "scalar", T: 3 /* ScalarType.INT64 */ },
+    { no: 2, name: "status_code", kind: "scalar", T: 5 /* ScalarType.INT32 */ },
+    { no: 3, name: "error", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 4, name: "duration", kind: "message", T: Duration },
+    { no: 5, name: "attempted_at", kind: "message", T: Timestamp },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Attempt {
+    return new Attempt().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): Attempt {
+    return new Attempt().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(jsonString: string, options?: Partial<JsonReadOptions>): Attempt {
+    return new Attempt().fromJsonString(jsonString, options);
+  }
+
+  static equals(a: Attempt | PlainMessage<Attempt> | undefined, b: Attempt | PlainMessage<Attempt> | undefined): boolean {
+    return proto3.util.equals(Attempt, a, b);
+  }
+}
+
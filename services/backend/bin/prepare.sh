@@ -15,6 +15,10 @@ build_boil() {
   echo "Done."
 }
 
+check_drift() {
+  ../../bin/go run ./cmd/backend drift
+}
+
 build_gql() {
   cd ../../schemas/graphql
   
@@ -100,6 +104,10 @@ main() {
         build_boil && exit 0;
         shift
       ;;
+      -cd|--check-drift)
+        check_drift && exit 0;
+        shift
+      ;;
       -bg|--build-gql)
         build_gql && exit 0;
         shift
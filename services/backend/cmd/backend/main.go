@@ -1,10 +1,11 @@
 package main
 
-import "github.com/jmandel1027/perspex/services/backend/pkg/server"
+import (
+	"os"
 
-func main() {
-
-	go server.Serve()
+	"github.com/jmandel1027/perspex/services/backend/pkg/cli"
+)
 
-	select {}
+func main() {
+	os.Exit(cli.Run(os.Args[1:]))
 }
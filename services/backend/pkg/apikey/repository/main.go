@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+)
+
+// uniqueViolation is the Postgres error code raised when a unique index rejects a write.
+const uniqueViolation = "23505"
+
+// Error strings shared by every IAPIKeyRepository implementation.
+var (
+	ErrKeyNotFound = errors.New("api key not found")
+	ErrPrefixTaken = errors.New("api key prefix is already taken")
+)
+
+// APIKey is a row of the `api_keys` table. Only a hash of the secret is kept, the Prefix identifies the key.
+type APIKey struct {
+	ID             int64      `json:"id"`
+	Prefix         string     `json:"prefix"`
+	SecretHash     []byte     `json:"-"`
+	Name           string     `json:"name"`
+	Scopes         []string   `json:"scopes"`
+	UserID         int64      `json:"user_id"`
+	OrganizationID int64      `json:"organization_id"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the key may authenticate requests at now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// IAPIKeyRepository is the interface for API keys
+type IAPIKeyRepository interface {
+	CreateKey(ctx context.Context, record *APIKey) (*APIKey, error)
+	FindKeyByID(ctx context.Context, id int64) (*APIKey, error)
+	FindKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	FindKeysByUser(ctx context.Context, userID int64) ([]*APIKey, error)
+	RotateKey(ctx context.Context, id int64, prefix string, hash []byte) (*APIKey, error)
+	RevokeKey(ctx context.Context, id int64) (*APIKey, error)
+	TouchKey(ctx context.Context, id int64) error
+}
+
+// APIKeyRepository --
+type APIKeyRepository struct {
+	dbs   *postgres.DB
+	log   *otelzap.Logger
+	clock clock.Clock
+	audit audit.Recorder
+}
+
+// NewAPIKeyRepository Creates a new API key repo instance
+func NewAPIKeyRepository(dbs *postgres.DB, log *otelzap.Logger, clk clock.Clock, rec audit.Recorder) *APIKeyRepository {
+	return &APIKeyRepository{dbs: dbs, log: log, clock: clk, audit: rec}
+}
+
+// CreateKey stores a new key
+func (repo *APIKeyRepository) CreateKey(ctx context.Context, record *APIKey) (res *APIKey, err error) {
+	now := repo.clock.Now()
+
+	m := &models.APIKey{
+		Prefix:         record.Prefix,
+		SecretHash:     record.SecretHash,
+		Name:           record.Name,
+		Scopes:         record.Scopes,
+		UserID:         record.UserID,
+		OrganizationID: null.NewInt64(record.OrganizationID, record.OrganizationID != 0),
+		ExpiresAt:      null.TimeFromPtr(record.ExpiresAt),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		err := m.Insert(boil.SkipTimestamps(ctx), tx, boil.Infer())
+		if isUniqueViolation(err) {
+			return fmt.Errorf("Couldn't create api key: %w", ErrPrefixTaken)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "create api key", err)
+		}
+
+		res = key(m)
+		return repo.record(ctx, tx, nil, res)
+	})
+
+	return
+}
+
+// FindKeyByID finds a key by id
+func (repo *APIKeyRepository) FindKeyByID(ctx context.Context, id int64) (*APIKey, error) {
+	return repo.one(ctx, "find api key", models.APIKeyWhere.ID.EQ(id))
+}
+
+// FindKeyByPrefix finds the key a bearer token belongs to
+func (repo *APIKeyRepository) FindKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	return repo.one(ctx, "find api key", models.APIKeyWhere.Prefix.EQ(prefix))
+}
+
+// FindKeysByUser finds the keys owned by a user, oldest first
+func (repo *APIKeyRepository) FindKeysByUser(ctx context.Context, userID int64) (res []*APIKey, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		keys, err := models.APIKeys(models.APIKeyWhere.UserID.EQ(userID), qm.OrderBy(models.APIKeyColumns.ID)).All(ctx, tx)
+		if err != nil {
+			return repo.failed(ctx, "list api keys", err)
+		}
+
+		for _, m := range keys {
+			res = append(res, key(m))
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// RotateKey replaces the prefix and secret of a key
+func (repo *APIKeyRepository) RotateKey(ctx context.Context, id int64, prefix string, hash []byte) (*APIKey, error) {
+	return repo.mutate(ctx, "rotate api key", id, func(m *models.APIKey) (boil.Columns, bool) {
+		if m.RevokedAt.Valid {
+			return boil.Columns{}, false
+		}
+
+		m.Prefix = prefix
+		m.SecretHash = hash
+
+		return boil.Whitelist(models.APIKeyColumns.Prefix, models.APIKeyColumns.SecretHash, models.APIKeyColumns.UpdatedAt), true
+	})
+}
+
+// RevokeKey revokes a key, keeping it for the record
+func (repo *APIKeyRepository) RevokeKey(ctx context.Context, id int64) (*APIKey, error) {
+	return repo.mutate(ctx, "revoke api key", id, func(m *models.APIKey) (boil.Columns, bool) {
+		if !m.RevokedAt.Valid {
+			m.RevokedAt = null.TimeFrom(repo.clock.Now())
+		}
+
+		return boil.Whitelist(models.APIKeyColumns.RevokedAt, models.APIKeyColumns.UpdatedAt), true
+	})
+}
+
+// TouchKey records that a key was just used
+func (repo *APIKeyRepository) TouchKey(ctx context.Context, id int64) error {
+	return repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		_, err := models.APIKeys(models.APIKeyWhere.ID.EQ(id)).UpdateAll(ctx, tx, models.M{
+			models.APIKeyColumns.LastUsedAt: repo.clock.Now(),
+		})
+
+		return repo.failed(ctx, "touch api key", err)
+	})
+}
+
+// one finds a single key, or returns ErrKeyNotFound.
+func (repo *APIKeyRepository) one(ctx context.Context, action string, mods ...qm.QueryMod) (res *APIKey, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		m, err := models.APIKeys(mods...).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't %s: %w", action, ErrKeyNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, action, err)
+		}
+
+		res = key(m)
+		return nil
+	})
+
+	return
+}
+
+// mutate applies change to the key with the id, saving the columns it returns and recording the change. It returns
+// ErrKeyNotFound when there is no such key, or change refuses it.
+func (repo *APIKeyRepository) mutate(ctx context.Context, action string, id int64, change func(*models.APIKey) (boil.Columns, bool)) (res *APIKey, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		m, err := models.APIKeys(models.APIKeyWhere.ID.EQ(id), qm.For("UPDATE")).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't %s: %w", action, ErrKeyNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, action, err)
+		}
+
+		before := key(m)
+
+		columns, ok := change(m)
+		if !ok {
+			return fmt.Errorf("Couldn't %s: %w", action, ErrKeyNotFound)
+		}
+
+		m.UpdatedAt = repo.clock.Now()
+
+		_, err = m.Update(boil.SkipTimestamps(ctx), tx, columns)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("Couldn't %s: %w", action, ErrPrefixTaken)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, action, err)
+		}
+
+		res = key(m)
+		return repo.record(ctx, tx, before, res)
+	})
+
+	return
+}
+
+// record records the audit event of a key changing from before to after in tx, the transaction that changed it.
+func (repo *APIKeyRepository) record(ctx context.Context, tx *postgres.Tx, before *APIKey, after *APIKey) error {
+	e, err := audit.NewEvent(ctx, audit.EntityAPIKey, after.ID, before, after)
+	if err == nil {
+		err = repo.audit.Record(ctx, tx, e)
+	}
+
+	return repo.failed(ctx, "audit api key", err)
+}
+
+// failed logs and wraps an unexpected error.
+func (repo *APIKeyRepository) failed(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	warning := fmt.Sprintf("Couldn't %s: %s", action, err)
+	repo.log.Ctx(ctx).Error(warning)
+
+	return fmt.Errorf("Couldn't %s: %w", action, err)
+}
+
+// key converts the model of a key.
+func key(m *models.APIKey) *APIKey {
+	return &APIKey{
+		ID:             m.ID,
+		Prefix:         m.Prefix,
+		SecretHash:     m.SecretHash,
+		Name:           m.Name,
+		Scopes:         m.Scopes,
+		UserID:         m.UserID,
+		OrganizationID: m.OrganizationID.Int64,
+		ExpiresAt:      m.ExpiresAt.Ptr(),
+		LastUsedAt:     m.LastUsedAt.Ptr(),
+		RevokedAt:      m.RevokedAt.Ptr(),
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// isUniqueViolation reports whether err was caused by a unique index rejecting a write.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+)
+
+// APIKeyRepository is an in-memory repository.IAPIKeyRepository, for running without a database.
+type APIKeyRepository struct {
+	mu    sync.RWMutex
+	clock clock.Clock
+	audit audit.Recorder
+	seq   int64
+	keys  map[int64]repository.APIKey
+}
+
+var _ repository.IAPIKeyRepository = (*APIKeyRepository)(nil)
+
+// NewAPIKeyRepository Creates a new, empty in-memory API key repo instance
+func NewAPIKeyRepository(clk clock.Clock, rec audit.Recorder) *APIKeyRepository {
+	return &APIKeyRepository{
+		clock: clk,
+		audit: rec,
+		keys:  make(map[int64]repository.APIKey),
+	}
+}
+
+// CreateKey stores a new key
+func (repo *APIKeyRepository) CreateKey(ctx context.Context, record *repository.APIKey) (*repository.APIKey, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.prefixTaken(record.Prefix, 0) {
+		return nil, fmt.Errorf("Couldn't create api key: %w", repository.ErrPrefixTaken)
+	}
+
+	repo.seq++
+
+	now := repo.clock.Now()
+	k := *record
+	k.ID = repo.seq
+	k.Scopes = append([]string{}, record.Scopes...)
+	k.CreatedAt = now
+	k.UpdatedAt = now
+
+	if err := repo.record(ctx, nil, &k); err != nil {
+		return nil, err
+	}
+
+	repo.keys[k.ID] = k
+
+	return &k, nil
+}
+
+// FindKeyByID finds a key by id
+func (repo *APIKeyRepository) FindKeyByID(ctx context.Context, id int64) (*repository.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	k, ok := repo.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't find api key: %w", repository.ErrKeyNotFound)
+	}
+
+	return &k, nil
+}
+
+// FindKeyByPrefix finds the key a bearer token belongs to
+func (repo *APIKeyRepository) FindKeyByPrefix(ctx context.Context, prefix string) (*repository.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, k := range repo.keys {
+		if k.Prefix == prefix {
+			return &k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Couldn't find api key: %w", repository.ErrKeyNotFound)
+}
+
+// FindKeysByUser finds the keys owned by a user, oldest first
+func (repo *APIKeyRepository) FindKeysByUser(ctx context.Context, userID int64) ([]*repository.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var res []*repository.APIKey
+
+	for _, k := range repo.keys {
+		if k.UserID == userID {
+			k := k
+			res = append(res, &k)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+// RotateKey replaces the prefix and secret of a key
+func (repo *APIKeyRepository) RotateKey(ctx context.Context, id int64, prefix string, hash []byte) (*repository.APIKey, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	k, ok := repo.keys[id]
+	if !ok || k.RevokedAt != nil {
+		return nil, fmt.Errorf("Couldn't rotate api key: %w", repository.ErrKeyNotFound)
+	}
+
+	if repo.prefixTaken(prefix, id) {
+		return nil, fmt.Errorf("Couldn't rotate api key: %w", repository.ErrPrefixTaken)
+	}
+
+	before := k
+	k.Prefix = prefix
+	k.SecretHash = hash
+	k.UpdatedAt = repo.clock.Now()
+
+	if err := repo.record(ctx, &before, &k); err != nil {
+		return nil, err
+	}
+
+	repo.keys[id] = k
+
+	return &k, nil
+}
+
+// RevokeKey revokes a key, keeping it for the record
+func (repo *APIKeyRepository) RevokeKey(ctx context.Context, id int64) (*repository.APIKey, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	k, ok := repo.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't revoke api key: %w", repository.ErrKeyNotFound)
+	}
+
+	before := k
+	now := repo.clock.Now()
+	if k.RevokedAt == nil {
+		k.RevokedAt = &now
+	}
+
+	k.UpdatedAt = now
+
+	if err := repo.record(ctx, &before, &k); err != nil {
+		return nil, err
+	}
+
+	repo.keys[id] = k
+
+	return &k, nil
+}
+
+// TouchKey records that a key was just used
+func (repo *APIKeyRepository) TouchKey(ctx context.Context, id int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if k, ok := repo.keys[id]; ok {
+		now := repo.clock.Now()
+		k.LastUsedAt = &now
+		repo.keys[id] = k
+	}
+
+	return nil
+}
+
+// record records the audit event of a key changing from before to after.
+func (repo *APIKeyRepository) record(ctx context.Context, before *repository.APIKey, after *repository.APIKey) error {
+	e, err := audit.NewEvent(ctx, audit.EntityAPIKey, after.ID, before, after)
+	if err == nil {
+		err = repo.audit.Record(ctx, nil, e)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Couldn't audit api key: %w", err)
+	}
+
+	return nil
+}
+
+// prefixTaken reports whether a key other than id uses prefix. Callers must hold mu.
+func (repo *APIKeyRepository) prefixTaken(prefix string, id int64) bool {
+	for _, k := range repo.keys {
+		if k.Prefix == prefix && k.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
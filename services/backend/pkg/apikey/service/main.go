@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	connect "github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apikeys "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/apikeys/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/apikeys/v1/apikeysconnect"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
+)
+
+// Token is the prefix of every API key, telling it apart from other bearer tokens such as JWTs. A key reads
+// `pk_<prefix>_<secret>`, where only `pk_<prefix>` is stored in the clear.
+const Token = "pk_"
+
+const (
+	prefixBytes = 6
+	secretBytes = 32
+
+	// attempts bounds the retries of a key whose random prefix collided with an existing one.
+	attempts = 3
+
+	// touchEvery throttles the writes recording when a key was last used.
+	touchEvery = time.Minute
+)
+
+// ApiKeyService structs
+type ApiKeyService struct {
+	repo    repository.IAPIKeyRepository
+	members orgRepository.IMembershipRepository
+	clock   clock.Clock
+	log     *otelzap.Logger
+	apikeysconnect.UnimplementedApiKeyServiceHandler
+}
+
+// NewApiKeyService for connecting to the repository
+func NewApiKeyService(repo repository.IAPIKeyRepository, members orgRepository.IMembershipRepository, clk clock.Clock, log *otelzap.Logger) *ApiKeyService {
+	return &ApiKeyService{
+		repo:    repo,
+		members: members,
+		clock:   clk,
+		log:     log,
+	}
+}
+
+// Register returns the registry.Service for mounting the service behind the supplied interceptors, typically the
+// transaction interceptors of its backing database.
+func Register(svc apikeysconnect.ApiKeyServiceHandler, interceptors ...connect.Interceptor) registry.Service {
+	return &registry.Registration{
+		ServiceName: apikeysconnect.ApiKeyServiceName,
+		HandlerFunc: func(opts ...connect.HandlerOption) (string, http.Handler) {
+			return apikeysconnect.NewApiKeyServiceHandler(svc, opts...)
+		},
+		ServiceInterceptors: interceptors,
+		GatewayFunc:         apikeys.RegisterApiKeyServiceHandler,
+	}
+}
+
+// CreateApiKey creates a key owned by the caller, returning its secret
+func (svc *ApiKeyService) CreateApiKey(ctx context.Context, rec *connect.Request[apikeys.CreateApiKeyRequest]) (*connect.Response[apikeys.CreateApiKeyResponse], error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(rec.Msg.Name) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("name is required"))
+	}
+
+	if err := validateScopes(id, rec.Msg.Scopes); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	k := &repository.APIKey{
+		Name:           rec.Msg.Name,
+		Scopes:         rec.Msg.Scopes,
+		UserID:         id.UserID,
+		OrganizationID: rec.Msg.OrganizationId,
+	}
+
+	if rec.Msg.ExpiresAt != nil {
+		expires := rec.Msg.ExpiresAt.AsTime()
+		if !expires.After(svc.clock.Now()) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("expires_at must be in the future"))
+		}
+
+		k.ExpiresAt = &expires
+	}
+
+	if k.OrganizationID != 0 && !id.Admin {
+		role, err := svc.members.Role(ctx, k.OrganizationID, id.UserID)
+		if err != nil {
+			svc.log.Ctx(ctx).Error("Error checking organization role: ", zap.Error(err))
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+
+		if role != orgRepository.RoleAdmin {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("only organization admins may create its keys"))
+		}
+	}
+
+	var record *repository.APIKey
+	var secret string
+
+	for attempt := 1; ; attempt++ {
+		k.Prefix, secret, k.SecretHash = generate()
+
+		record, err = svc.repo.CreateKey(ctx, k)
+		if attempt == attempts || !errors.Is(err, repository.ErrPrefixTaken) {
+			break
+		}
+	}
+
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error creating api key: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Created api key", zap.String("prefix", record.Prefix), zap.Int64("user_id", record.UserID))
+
+	return connect.NewResponse(&apikeys.CreateApiKeyResponse{
+		ApiKey: toProto(record),
+		Secret: secret,
+	}), nil
+}
+
+// ListApiKeys lists the keys owned by the caller
+func (svc *ApiKeyService) ListApiKeys(ctx context.Context, rec *connect.Request[apikeys.ListApiKeysRequest]) (*connect.Response[apikeys.ListApiKeysResponse], error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := svc.repo.FindKeysByUser(ctx, id.UserID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error listing api keys: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	keys := make([]*apikeys.ApiKey, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, toProto(record))
+	}
+
+	return connect.NewResponse(&apikeys.ListApiKeysResponse{ApiKeys: keys}), nil
+}
+
+// RotateApiKey replaces the secret of a key, returning the new one
+func (svc *ApiKeyService) RotateApiKey(ctx context.Context, rec *connect.Request[apikeys.RotateApiKeyRequest]) (*connect.Response[apikeys.RotateApiKeyResponse], error) {
+	k, err := svc.owned(ctx, rec.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.RevokedAt != nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("api key is revoked"))
+	}
+
+	var record *repository.APIKey
+	var secret string
+
+	for attempt := 1; ; attempt++ {
+		var prefix string
+		var hash []byte
+
+		prefix, secret, hash = generate()
+
+		record, err = svc.repo.RotateKey(ctx, k.ID, prefix, hash)
+		if attempt == attempts || !errors.Is(err, repository.ErrPrefixTaken) {
+			break
+		}
+	}
+
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error rotating api key: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Rotated api key", zap.String("previous_prefix", k.Prefix), zap.String("prefix", record.Prefix))
+
+	return connect.NewResponse(&apikeys.RotateApiKeyResponse{
+		ApiKey: toProto(record),
+		Secret: secret,
+	}), nil
+}
+
+// RevokeApiKey revokes a key for good
+func (svc *ApiKeyService) RevokeApiKey(ctx context.Context, rec *connect.Request[apikeys.RevokeApiKeyRequest]) (*connect.Response[apikeys.RevokeApiKeyResponse], error) {
+	k, err := svc.owned(ctx, rec.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := svc.repo.RevokeKey(ctx, k.ID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error revoking api key: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Revoked api key", zap.String("prefix", record.Prefix))
+
+	return connect.NewResponse(&apikeys.RevokeApiKeyResponse{ApiKey: toProto(record)}), nil
+}
+
+// owned finds a key the caller may manage, hiding the keys of others as not found.
+func (svc *ApiKeyService) owned(ctx context.Context, keyID int64) (*repository.APIKey, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := svc.repo.FindKeyByID(ctx, keyID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrKeyNotFound) {
+			svc.log.Ctx(ctx).Error("Error retrieving api key: ", zap.Error(err))
+		}
+
+		return nil, toConnectError(err)
+	}
+
+	if k.UserID != id.UserID && !id.Admin {
+		return nil, toConnectError(repository.ErrKeyNotFound)
+	}
+
+	return k, nil
+}
+
+// Authenticate returns the auth.Authenticator for `Authorization: Bearer pk_...` headers, identifying the caller as
+// the owner of the key, restricted to its scopes. Requests without an API key are left to other authenticators.
+func Authenticate(repo repository.IAPIKeyRepository, clk clock.Clock, log *otelzap.Logger) auth.Authenticator {
+	return func(ctx context.Context, req *auth.Request) (*auth.Identity, error) {
+		token, ok := auth.BearerToken(req.Header)
+		if !ok || !strings.HasPrefix(token, Token) {
+			return nil, auth.ErrNoCredentials
+		}
+
+		prefix, secret, ok := parse(token)
+		if !ok {
+			return nil, auth.Errorf("malformed api key")
+		}
+
+		k, err := repo.FindKeyByPrefix(ctx, prefix)
+		if errors.Is(err, repository.ErrKeyNotFound) {
+			return nil, auth.Errorf("invalid api key")
+		}
+
+		if err != nil {
+			log.Ctx(ctx).Error("Error authenticating api key: ", zap.Error(err))
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+
+		if subtle.ConstantTimeCompare(hash(secret), k.SecretHash) != 1 {
+			return nil, auth.Errorf("invalid api key")
+		}
+
+		now := clk.Now()
+		if !k.Active(now) {
+			return nil, auth.Errorf("api key is revoked or expired")
+		}
+
+		if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= touchEvery {
+			if err := repo.TouchKey(ctx, k.ID); err != nil {
+				log.Ctx(ctx).Warn("Error recording api key use: ", zap.Error(err))
+			}
+		}
+
+		return &auth.Identity{
+			UserID:  k.UserID,
+			Subject: "api_key:" + k.Prefix,
+			Scopes:  k.Scopes,
+		}, nil
+	}
+}
+
+// caller returns the identity of the caller, who must be a user.
+func caller(ctx context.Context) (*auth.Identity, error) {
+	id, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, auth.Errorf("api keys require an authenticated caller")
+	}
+
+	if id.UserID == 0 {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("api keys belong to users"))
+	}
+
+	return id, nil
+}
+
+// validateScopes checks that every scope names a known service or procedure, which the caller's own credentials reach,
+// so a key can't outgrow the key that created it.
+func validateScopes(id *auth.Identity, scopes []string) error {
+	if len(id.Scopes) > 0 && len(scopes) == 0 {
+		return fmt.Errorf("scopes are required, the caller's credentials are scoped")
+	}
+
+	for _, scope := range scopes {
+		name := protoreflect.FullName(strings.Replace(strings.TrimPrefix(scope, "/"), "/", ".", 1))
+
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+		if err != nil {
+			return fmt.Errorf("scope %q is not a known service or procedure", scope)
+		}
+
+		switch d.(type) {
+		case protoreflect.ServiceDescriptor, protoreflect.MethodDescriptor:
+		default:
+			return fmt.Errorf("scope %q is not a service or procedure", scope)
+		}
+
+		if !id.InScope(scope) {
+			return fmt.Errorf("scope %q exceeds the scopes of the caller", scope)
+		}
+	}
+
+	return nil
+}
+
+// generate returns a random prefix and secret, with the hash of the secret.
+func generate() (prefix string, secret string, digest []byte) {
+	prefix = Token + random(prefixBytes)
+	secret = random(secretBytes)
+
+	return prefix, prefix + "_" + secret, hash(secret)
+}
+
+// parse splits a `pk_<prefix>_<secret>` token.
+func parse(token string) (prefix string, secret string, ok bool) {
+	rest := strings.TrimPrefix(token, Token)
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 2*prefixBytes || len(secret) != 2*secretBytes {
+		return "", "", false
+	}
+
+	return Token + id, secret, true
+}
+
+func random(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+func toProto(k *repository.APIKey) *apikeys.ApiKey {
+	res := &apikeys.ApiKey{
+		Id:             k.ID,
+		Prefix:         k.Prefix,
+		Name:           k.Name,
+		Scopes:         k.Scopes,
+		UserId:         k.UserID,
+		OrganizationId: k.OrganizationID,
+		CreatedAt:      timestamppb.New(k.CreatedAt),
+	}
+
+	if k.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*k.ExpiresAt)
+	}
+
+	if k.LastUsedAt != nil {
+		res.LastUsedAt = timestamppb.New(*k.LastUsedAt)
+	}
+
+	if k.RevokedAt != nil {
+		res.RevokedAt = timestamppb.New(*k.RevokedAt)
+	}
+
+	return res
+}
+
+// toConnectError maps repository errors onto connect error codes.
+func toConnectError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, repository.ErrKeyNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, repository.ErrPrefixTaken):
+		return connect.NewError(connect.CodeAborted, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
@@ -0,0 +1,326 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apikeys "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/apikeys/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository/memory"
+	"github.com/jmandel1027/perspex/services/backend/pkg/apikey/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	orgMemory "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository/memory"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+)
+
+const owner = 7
+
+// ticker is a clock that only moves when told to.
+type ticker struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ticker) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ticker) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// keys is an in-memory repository failing the first collisions key creations as if their prefix was taken, and
+// counting the uses it records.
+type keys struct {
+	*memory.APIKeyRepository
+
+	mu         sync.Mutex
+	collisions int
+	prefixes   []string
+	touches    int
+}
+
+func (repo *keys) CreateKey(ctx context.Context, record *repository.APIKey) (*repository.APIKey, error) {
+	repo.mu.Lock()
+	repo.prefixes = append(repo.prefixes, record.Prefix)
+	collide := len(repo.prefixes) <= repo.collisions
+	repo.mu.Unlock()
+
+	if collide {
+		return nil, repository.ErrPrefixTaken
+	}
+
+	return repo.APIKeyRepository.CreateKey(ctx, record)
+}
+
+func (repo *keys) TouchKey(ctx context.Context, id int64) error {
+	repo.mu.Lock()
+	repo.touches++
+	repo.mu.Unlock()
+
+	return repo.APIKeyRepository.TouchKey(ctx, id)
+}
+
+type harness struct {
+	clock        *ticker
+	repo         *keys
+	svc          *service.ApiKeyService
+	authenticate auth.Authenticator
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	clk := &ticker{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo := &keys{APIKeyRepository: memory.NewAPIKeyRepository(clk, audit.Nop())}
+	log := otelzap.New(zap.NewNop())
+
+	return &harness{
+		clock:        clk,
+		repo:         repo,
+		svc:          service.NewApiKeyService(repo, orgMemory.NewMembershipRepository(audit.Nop(), outbox.Nop()), clk, log),
+		authenticate: service.Authenticate(repo, clk, log),
+	}
+}
+
+// create creates a key as its owner, whose credentials are restricted to scoped, if any.
+func (h *harness) create(t *testing.T, msg *apikeys.CreateApiKeyRequest, scoped ...string) (*apikeys.CreateApiKeyResponse, error) {
+	t.Helper()
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: owner, Scopes: scoped})
+
+	res, err := h.svc.CreateApiKey(ctx, connect.NewRequest(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Msg, nil
+}
+
+func (h *harness) secret(t *testing.T, msg *apikeys.CreateApiKeyRequest) string {
+	t.Helper()
+
+	res, err := h.create(t, msg)
+	if err != nil {
+		t.Fatalf("CreateApiKey: %v", err)
+	}
+
+	return res.Secret
+}
+
+func (h *harness) present(header string) (*auth.Identity, error) {
+	req := &auth.Request{Header: http.Header{}}
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+
+	return h.authenticate(context.Background(), req)
+}
+
+func code(err error) connect.Code {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code()
+	}
+
+	return 0
+}
+
+func TestAuthenticate(t *testing.T) {
+	h := newHarness(t)
+
+	valid := h.secret(t, &apikeys.CreateApiKeyRequest{Name: "valid", Scopes: []string{"apikeys.v1.ApiKeyService"}})
+	revoked := h.secret(t, &apikeys.CreateApiKeyRequest{Name: "revoked"})
+	expired := h.secret(t, &apikeys.CreateApiKeyRequest{Name: "expired", ExpiresAt: timestamppb.New(h.clock.Now().Add(time.Hour))})
+
+	created, err := h.repo.FindKeysByUser(context.Background(), owner)
+	if err != nil || len(created) != 3 {
+		t.Fatalf("expected the keys created, got %v (%v)", created, err)
+	}
+
+	if _, err := h.repo.RevokeKey(context.Background(), created[1].ID); err != nil {
+		t.Fatalf("RevokeKey: %v", err)
+	}
+
+	h.clock.Add(2 * time.Hour)
+
+	prefix, _, _ := strings.Cut(strings.TrimPrefix(valid, service.Token), "_")
+	wrong := service.Token + prefix + "_" + strings.Repeat("0", 64)
+	unknown := service.Token + strings.Repeat("0", 12) + "_" + strings.Repeat("0", 64)
+
+	cases := map[string]struct {
+		header string
+		code   connect.Code
+		none   bool
+	}{
+		"Valid":          {header: "Bearer " + valid},
+		"NoHeader":       {none: true},
+		"OtherScheme":    {header: "Basic " + valid, none: true},
+		"OtherToken":     {header: "Bearer eyJhbGciOiJSUzI1NiJ9.e30.c2ln", none: true},
+		"Malformed":      {header: "Bearer " + service.Token + "short", code: connect.CodeUnauthenticated},
+		"MissingSecret":  {header: "Bearer " + service.Token + prefix, code: connect.CodeUnauthenticated},
+		"TruncatedKey":   {header: "Bearer " + valid[:len(valid)-1], code: connect.CodeUnauthenticated},
+		"WrongSecret":    {header: "Bearer " + wrong, code: connect.CodeUnauthenticated},
+		"UnknownPrefix":  {header: "Bearer " + unknown, code: connect.CodeUnauthenticated},
+		"Revoked":        {header: "Bearer " + revoked, code: connect.CodeUnauthenticated},
+		"Expired":        {header: "Bearer " + expired, code: connect.CodeUnauthenticated},
+		"CaseOfTheToken": {header: "bearer " + valid},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			id, err := h.present(c.header)
+
+			switch {
+			case c.none:
+				if !errors.Is(err, auth.ErrNoCredentials) {
+					t.Fatalf("expected ErrNoCredentials, got %v", err)
+				}
+			case c.code != 0:
+				if code(err) != c.code {
+					t.Fatalf("expected %s, got %v", c.code, err)
+				}
+			case err != nil:
+				t.Fatalf("expected to authenticate, got %v", err)
+			case id.UserID != owner || id.Subject != "api_key:"+created[0].Prefix || len(id.Scopes) != 1 || id.Admin:
+				t.Fatalf("expected the owner restricted to the key's scopes, got %+v", id)
+			}
+		})
+	}
+}
+
+func TestAuthenticateThrottlesLastUsed(t *testing.T) {
+	h := newHarness(t)
+
+	token := h.secret(t, &apikeys.CreateApiKeyRequest{Name: "job"})
+
+	for _, step := range []struct {
+		after   time.Duration
+		touches int
+	}{
+		{after: 0, touches: 1},
+		{after: 30 * time.Second, touches: 1},
+		{after: 29 * time.Second, touches: 1},
+		{after: time.Second, touches: 2},
+		{after: time.Second, touches: 2},
+		{after: time.Hour, touches: 3},
+	} {
+		h.clock.Add(step.after)
+
+		if _, err := h.present("Bearer " + token); err != nil {
+			t.Fatalf("authenticate: %v", err)
+		}
+
+		if h.repo.touches != step.touches {
+			t.Fatalf("after %s: expected %d recorded uses, got %d", step.after, step.touches, h.repo.touches)
+		}
+	}
+}
+
+func TestCreateApiKeyScopes(t *testing.T) {
+	cases := map[string]struct {
+		caller []string
+		scopes []string
+		ok     bool
+	}{
+		"Unscoped":             {ok: true},
+		"UnscopedNarrows":      {scopes: []string{"apikeys.v1.ApiKeyService/ListApiKeys"}, ok: true},
+		"Service":              {caller: []string{"apikeys.v1.ApiKeyService"}, scopes: []string{"apikeys.v1.ApiKeyService"}, ok: true},
+		"ProcedureOfService":   {caller: []string{"apikeys.v1.ApiKeyService"}, scopes: []string{"/apikeys.v1.ApiKeyService/ListApiKeys"}, ok: true},
+		"SameProcedure":        {caller: []string{"apikeys.v1.ApiKeyService/ListApiKeys"}, scopes: []string{"apikeys.v1.ApiKeyService/ListApiKeys"}, ok: true},
+		"ServiceOfProcedure":   {caller: []string{"apikeys.v1.ApiKeyService/ListApiKeys"}, scopes: []string{"apikeys.v1.ApiKeyService"}},
+		"OtherProcedure":       {caller: []string{"apikeys.v1.ApiKeyService/ListApiKeys"}, scopes: []string{"apikeys.v1.ApiKeyService/CreateApiKey"}},
+		"ScopedCallerUnscoped": {caller: []string{"apikeys.v1.ApiKeyService"}},
+		"UnknownService":       {scopes: []string{"nope.v1.NopeService"}},
+		"NotAService":          {scopes: []string{"apikeys.v1.ApiKey"}},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+
+			res, err := h.create(t, &apikeys.CreateApiKeyRequest{Name: "job", Scopes: c.scopes}, c.caller...)
+
+			switch {
+			case !c.ok && code(err) != connect.CodeInvalidArgument:
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			case c.ok && err != nil:
+				t.Fatalf("expected the key to be created, got %v", err)
+			case c.ok && len(res.ApiKey.Scopes) != len(c.scopes):
+				t.Fatalf("expected the scopes %v, got %v", c.scopes, res.ApiKey.Scopes)
+			}
+		})
+	}
+}
+
+func TestCreateApiKeyRetriesTakenPrefixes(t *testing.T) {
+	cases := map[string]struct {
+		collisions int
+		attempts   int
+		code       connect.Code
+	}{
+		"FirstAttempt": {attempts: 1},
+		"LastAttempt":  {collisions: 2, attempts: 3},
+		"Exhausted":    {collisions: 5, attempts: 3, code: connect.CodeAborted},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.repo.collisions = c.collisions
+
+			res, err := h.create(t, &apikeys.CreateApiKeyRequest{Name: "job"})
+			if code(err) != c.code {
+				t.Fatalf("expected %s, got %v", c.code, err)
+			}
+
+			if attempts := len(h.repo.prefixes); attempts != c.attempts {
+				t.Fatalf("expected %d attempts, got %d", c.attempts, attempts)
+			}
+
+			seen := map[string]bool{}
+			for _, prefix := range h.repo.prefixes {
+				if seen[prefix] {
+					t.Fatalf("expected a new prefix every attempt, got %v", h.repo.prefixes)
+				}
+
+				seen[prefix] = true
+			}
+
+			if err != nil {
+				return
+			}
+
+			if res.ApiKey.Prefix != h.repo.prefixes[len(h.repo.prefixes)-1] {
+				t.Fatalf("expected the key to have the last prefix tried, got %s", res.ApiKey.Prefix)
+			}
+
+			if _, err := h.present("Bearer " + res.Secret); err != nil {
+				t.Fatalf("expected the returned secret to authenticate, got %v", err)
+			}
+		})
+	}
+}
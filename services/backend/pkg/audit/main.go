@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// Types of the entities events are recorded for
+const (
+	EntityUser               = "user"
+	EntityOrganizationMember = "organization_member"
+	EntityAPIKey             = "api_key"
+)
+
+// Event is a row of the append-only `audit_events` table, recording who mutated an entity and how.
+type Event struct {
+	ID                  int64
+	ActorUserID         int64
+	ActorSubject        string
+	ImpersonatorUserID  int64
+	ImpersonatorSubject string
+	Procedure           string
+	EntityType          string
+	EntityID            string
+	Diff                Diff
+	RequestID           string
+	CreatedAt           time.Time
+}
+
+// Change is the value of a field before and after a mutation, nil when it didn't exist.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Diff holds the changed fields of an entity by their JSON names.
+type Diff map[string]Change
+
+// Recorder records events in the transaction of the mutation they describe, so both are committed or neither is.
+// Storage without transactions passes a nil tx.
+type Recorder interface {
+	Record(ctx context.Context, tx *postgres.Tx, event *Event) error
+}
+
+type nop struct{}
+
+// Nop returns a Recorder that discards events.
+func Nop() Recorder {
+	return nop{}
+}
+
+// Record implements Recorder.
+func (nop) Record(context.Context, *postgres.Tx, *Event) error {
+	return nil
+}
+
+// NewEvent returns the event of the caller of ctx mutating an entity from before to after, which are nil when it is
+// created or deleted. Both are compared by their JSON encoding, so fields excluded from it are not recorded.
+func NewEvent(ctx context.Context, entityType string, entityID any, before any, after any) (*Event, error) {
+	diff, err := Compare(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Event{
+		Procedure:  ProcedureFromContext(ctx),
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Diff:       diff,
+	}
+
+	e.RequestID = access.FromContext(ctx)
+
+	if id, ok := auth.IdentityFromContext(ctx); ok {
+		e.ActorUserID = id.UserID
+		e.ActorSubject = id.Subject
+
+		if id.Impersonator != nil {
+			e.ImpersonatorUserID = id.Impersonator.UserID
+			e.ImpersonatorSubject = id.Impersonator.Subject
+		}
+	}
+
+	return e, nil
+}
+
+// Compare returns the fields that differ between before and after, either of which may be nil.
+func Compare(before any, after any) (Diff, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := Diff{}
+	for field, value := range b {
+		if other, ok := a[field]; !ok || !reflect.DeepEqual(value, other) {
+			diff[field] = Change{Before: value, After: a[field]}
+		}
+	}
+
+	for field, value := range a {
+		if _, ok := b[field]; !ok {
+			diff[field] = Change{After: value}
+		}
+	}
+
+	return diff, nil
+}
+
+// fields decodes the JSON encoding of v into its fields.
+func fields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audited entity: %w", err)
+	}
+
+	var res map[string]any
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("decoding audited entity: %w", err)
+	}
+
+	return res, nil
+}
+
+type key int
+
+const procedureKey key = iota
+
+// NewContext returns a copy of ctx carrying the procedure being called, eg: `/users.v1.UserService/ModifyUser`.
+func NewContext(ctx context.Context, procedure string) context.Context {
+	return context.WithValue(ctx, procedureKey, procedure)
+}
+
+// ProcedureFromContext returns the procedure carried by ctx, if any.
+func ProcedureFromContext(ctx context.Context) string {
+	procedure, _ := ctx.Value(procedureKey).(string)
+	return procedure
+}
+
+// Interceptor attaches the procedure being called to the context of RPCs, for the events they record.
+type Interceptor struct{}
+
+// NewInterceptor returns an Interceptor.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		return next(NewContext(ctx, req.Spec().Procedure), req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(NewContext(ctx, conn.Spec().Procedure), conn)
+	}
+}
+
+// Filter selects a page of events, newest first. After is an exclusive cursor, ignored when zero, and so are the other
+// fields when unset.
+type Filter struct {
+	ActorUserID int64
+	EntityType  string
+	EntityID    string
+	Since       time.Time
+	Until       time.Time
+	After       int64
+	Limit       int
+}
+
+// Matches reports whether e is selected by the filter, ignoring its cursor and limit.
+func (f Filter) Matches(e *Event) bool {
+	switch {
+	case f.ActorUserID != 0 && e.ActorUserID != f.ActorUserID && e.ImpersonatorUserID != f.ActorUserID:
+		return false
+	case f.EntityType != "" && e.EntityType != f.EntityType:
+		return false
+	case f.EntityID != "" && e.EntityID != f.EntityID:
+		return false
+	case !f.Since.IsZero() && e.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.CreatedAt.Before(f.Until):
+		return false
+	}
+
+	return true
+}
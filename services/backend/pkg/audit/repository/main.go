@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+)
+
+// IEventRepository is the interface for audit events, which are only ever appended.
+type IEventRepository interface {
+	audit.Recorder
+	FindEvents(ctx context.Context, filter audit.Filter) ([]*audit.Event, error)
+}
+
+// EventRepository --
+type EventRepository struct {
+	dbs   *postgres.DB
+	log   *otelzap.Logger
+	clock clock.Clock
+}
+
+var _ IEventRepository = (*EventRepository)(nil)
+
+// NewEventRepository Creates a new audit event repo instance
+func NewEventRepository(dbs *postgres.DB, log *otelzap.Logger, clk clock.Clock) *EventRepository {
+	return &EventRepository{dbs: dbs, log: log, clock: clk}
+}
+
+// Record appends the event in tx, the transaction of the mutation it describes, or in one of its own if tx is nil.
+// Zero IDs are stored as NULL, as no user has them.
+func (repo *EventRepository) Record(ctx context.Context, tx *postgres.Tx, e *audit.Event) error {
+	if tx == nil {
+		return repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+			return repo.Record(ctx, tx, e)
+		})
+	}
+
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return repo.failed(ctx, "record audit event", err)
+	}
+
+	e.CreatedAt = repo.clock.Now()
+
+	m := &models.AuditEvent{
+		ActorUserID:         null.NewInt64(e.ActorUserID, e.ActorUserID != 0),
+		ActorSubject:        e.ActorSubject,
+		ImpersonatorUserID:  null.NewInt64(e.ImpersonatorUserID, e.ImpersonatorUserID != 0),
+		ImpersonatorSubject: e.ImpersonatorSubject,
+		Procedure:           e.Procedure,
+		EntityType:          e.EntityType,
+		EntityID:            e.EntityID,
+		Diff:                diff,
+		RequestID:           e.RequestID,
+		CreatedAt:           e.CreatedAt,
+	}
+
+	if err := m.Insert(ctx, tx, boil.Infer()); err != nil {
+		return repo.failed(ctx, "record audit event", err)
+	}
+
+	e.ID = m.ID
+	return nil
+}
+
+// FindEvents finds a page of events, newest first
+func (repo *EventRepository) FindEvents(ctx context.Context, filter audit.Filter) (res []*audit.Event, err error) {
+	mods := []qm.QueryMod{qm.OrderBy(models.AuditEventColumns.ID + " DESC"), qm.Limit(filter.Limit)}
+
+	if filter.ActorUserID != 0 {
+		mods = append(mods, qm.Expr(
+			models.AuditEventWhere.ActorUserID.EQ(null.Int64From(filter.ActorUserID)),
+			qm.Or2(models.AuditEventWhere.ImpersonatorUserID.EQ(null.Int64From(filter.ActorUserID))),
+		))
+	}
+
+	if filter.EntityType != "" {
+		mods = append(mods, models.AuditEventWhere.EntityType.EQ(filter.EntityType))
+	}
+
+	if filter.EntityID != "" {
+		mods = append(mods, models.AuditEventWhere.EntityID.EQ(filter.EntityID))
+	}
+
+	if !filter.Since.IsZero() {
+		mods = append(mods, models.AuditEventWhere.CreatedAt.GTE(filter.Since))
+	}
+
+	if !filter.Until.IsZero() {
+		mods = append(mods, models.AuditEventWhere.CreatedAt.LT(filter.Until))
+	}
+
+	if filter.After > 0 {
+		mods = append(mods, models.AuditEventWhere.ID.LT(filter.After))
+	}
+
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		events, err := models.AuditEvents(mods...).All(ctx, tx)
+		if err != nil {
+			return repo.failed(ctx, "list audit events", err)
+		}
+
+		for _, m := range events {
+			e := &audit.Event{
+				ID:                  m.ID,
+				ActorUserID:         m.ActorUserID.Int64,
+				ActorSubject:        m.ActorSubject,
+				ImpersonatorUserID:  m.ImpersonatorUserID.Int64,
+				ImpersonatorSubject: m.ImpersonatorSubject,
+				Procedure:           m.Procedure,
+				EntityType:          m.EntityType,
+				EntityID:            m.EntityID,
+				RequestID:           m.RequestID,
+				CreatedAt:           m.CreatedAt,
+			}
+
+			if err := m.Diff.Unmarshal(&e.Diff); err != nil {
+				return repo.failed(ctx, "list audit events", err)
+			}
+
+			res = append(res, e)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// failed logs and wraps an unexpected error.
+func (repo *EventRepository) failed(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	warning := fmt.Sprintf("Couldn't %s: %s", action, err)
+	repo.log.Ctx(ctx).Error(warning)
+
+	return fmt.Errorf("Couldn't %s: %w", action, err)
+}
@@ -0,0 +1,279 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/harness"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	userRepository "github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+// ticker is a clock that only moves when told to.
+type ticker struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ticker) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ticker) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+var errPublish = errors.New("outbox unavailable")
+
+// outage is an outbox writer failing while down, which fails the mutations writing to it after they are audited.
+type outage struct {
+	mu   sync.Mutex
+	down bool
+}
+
+func (o *outage) Write(context.Context, *postgres.Tx, *outbox.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.down {
+		return errPublish
+	}
+
+	return nil
+}
+
+func (o *outage) set(down bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.down = down
+}
+
+// fixture is an audit repository over a fresh database, recording the mutations of a user repository.
+type fixture struct {
+	clock  *ticker
+	dbs    *postgres.DB
+	events *repository.EventRepository
+	users  *userRepository.UserRepository
+	outbox *outage
+}
+
+func setup(t *testing.T) *fixture {
+	t.Helper()
+
+	pg := harness.Require(t)
+
+	cfg, err := config.Defaults()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	cfg.WriterPG = pg.Database(t)
+	cfg.ReaderPG = cfg.WriterPG
+
+	dbs, err := postgres.Open(&cfg)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	t.Cleanup(dbs.Close)
+
+	log := otelzap.New(zap.NewNop())
+	clk := &ticker{now: time.Unix(1672531200, 0).UTC()}
+	events := repository.NewEventRepository(dbs, log, clk)
+	out := &outage{}
+
+	return &fixture{
+		clock:  clk,
+		dbs:    dbs,
+		events: events,
+		users:  userRepository.NewUserRepository(&cfg, dbs, log, cache.NewMemory(clk), clk, events, out),
+		outbox: out,
+	}
+}
+
+func (f *fixture) find(t *testing.T, filter audit.Filter) []*audit.Event {
+	t.Helper()
+
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	events, err := f.events.FindEvents(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("FindEvents: %v", err)
+	}
+
+	return events
+}
+
+func TestRecordWithMutation(t *testing.T) {
+	f := setup(t)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: 7, Subject: "user:7"})
+	ctx = audit.NewContext(ctx, "/users.v1.UserService/RegisterUser")
+
+	u, err := f.users.CreateUser(ctx, &models.User{Email: "audited@perspex.us", FirstName: "Audited", LastName: "User"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	entity := audit.Filter{EntityType: audit.EntityUser, EntityID: fmt.Sprint(u.ID)}
+
+	events := f.find(t, entity)
+	if len(events) != 1 {
+		t.Fatalf("expected the registration to be recorded, got %d events", len(events))
+	}
+
+	if e := events[0]; e.ActorUserID != 7 || e.ActorSubject != "user:7" || e.Procedure != "/users.v1.UserService/RegisterUser" ||
+		e.Diff["email"].Before != nil || e.Diff["email"].After != "audited@perspex.us" {
+		t.Fatalf("expected the caller and the user created, got %+v", e)
+	}
+
+	f.outbox.set(true)
+
+	if _, err := f.users.CreateUser(ctx, &models.User{Email: "rolled-back@perspex.us"}); !errors.Is(err, errPublish) {
+		t.Fatalf("expected the registration to fail with the outbox, got %v", err)
+	}
+
+	if events := f.find(t, audit.Filter{EntityType: audit.EntityUser}); len(events) != 1 {
+		t.Fatalf("expected the event of the failed registration to be rolled back with it, got %d events", len(events))
+	}
+
+	if n, err := f.users.CountUsers(ctx); err != nil || n != 1 {
+		t.Fatalf("expected the failed registration to be rolled back, got %d users (%v)", n, err)
+	}
+
+	f.outbox.set(false)
+
+	if _, err := f.users.DeleteUser(ctx, u.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	events = f.find(t, entity)
+	if len(events) != 2 || events[0].Diff["email"].Before != "audited@perspex.us" || events[0].Diff["email"].After != nil {
+		t.Fatalf("expected the deletion to be recorded, got %+v", events)
+	}
+}
+
+func TestRecordJoinsTransaction(t *testing.T) {
+	f := setup(t)
+
+	ctx := context.Background()
+	errRollback := errors.New("rolled back")
+
+	err := f.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		if err := f.events.Record(ctx, tx, &audit.Event{EntityType: audit.EntityUser, EntityID: "1", Diff: audit.Diff{}}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected the transaction to be rolled back, got %v", err)
+	}
+
+	if err := f.events.Record(ctx, nil, &audit.Event{EntityType: audit.EntityUser, EntityID: "2", Diff: audit.Diff{}}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	if events := f.find(t, audit.Filter{}); len(events) != 1 || events[0].EntityID != "2" {
+		t.Fatalf("expected only the event recorded in its own transaction, got %+v", events)
+	}
+}
+
+func TestFindEvents(t *testing.T) {
+	f := setup(t)
+
+	start := f.clock.Now()
+
+	// One event a minute: the first at start, the last 3 minutes later.
+	recorded := []*audit.Event{
+		{ActorUserID: 1, EntityType: audit.EntityUser, EntityID: "1"},
+		{ActorUserID: 2, ImpersonatorUserID: 1, EntityType: audit.EntityUser, EntityID: "2"},
+		{ActorUserID: 2, EntityType: audit.EntityAPIKey, EntityID: "5"},
+		{ActorUserID: 3, EntityType: audit.EntityOrganizationMember, EntityID: "9:3"},
+	}
+
+	for _, e := range recorded {
+		e.Diff = audit.Diff{"name": {After: "changed"}}
+
+		if err := f.events.Record(context.Background(), nil, e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+
+		f.clock.Add(time.Minute)
+	}
+
+	ids := func(n ...int) []int64 {
+		res := make([]int64, len(n))
+		for i, idx := range n {
+			res[i] = recorded[idx].ID
+		}
+
+		return res
+	}
+
+	cases := map[string]struct {
+		filter audit.Filter
+		want   []int64
+	}{
+		"All":          {want: ids(3, 2, 1, 0)},
+		"Actor":        {filter: audit.Filter{ActorUserID: 2}, want: ids(2, 1)},
+		"Impersonator": {filter: audit.Filter{ActorUserID: 1}, want: ids(1, 0)},
+		"EntityType":   {filter: audit.Filter{EntityType: audit.EntityUser}, want: ids(1, 0)},
+		"Entity":       {filter: audit.Filter{EntityType: audit.EntityUser, EntityID: "2"}, want: ids(1)},
+		"Since":        {filter: audit.Filter{Since: start.Add(time.Minute)}, want: ids(3, 2, 1)},
+		"Until":        {filter: audit.Filter{Until: start.Add(2 * time.Minute)}, want: ids(1, 0)},
+		"Window":       {filter: audit.Filter{Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)}, want: ids(2, 1)},
+		"FirstPage":    {filter: audit.Filter{Limit: 2}, want: ids(3, 2)},
+		"NextPage":     {filter: audit.Filter{After: recorded[2].ID, Limit: 2}, want: ids(1, 0)},
+		"LastPage":     {filter: audit.Filter{After: recorded[0].ID, Limit: 2}},
+		"FilteredPage": {filter: audit.Filter{ActorUserID: 2, After: recorded[2].ID}, want: ids(1)},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			events := f.find(t, c.filter)
+
+			got := make([]int64, len(events))
+			for i, e := range events {
+				got[i] = e.ID
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(c.want) {
+				t.Fatalf("expected events %v, got %v", c.want, got)
+			}
+		})
+	}
+
+	if e := f.find(t, audit.Filter{Limit: 1})[0]; e.Diff["name"].After != "changed" || !e.CreatedAt.Equal(start.Add(3*time.Minute)) {
+		t.Fatalf("expected the event as recorded, got %+v", e)
+	}
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+)
+
+// EventRepository is an in-memory repository.IEventRepository, for running without a database. Events are recorded
+// as soon as the mutation they describe is made, there being no transaction to join.
+type EventRepository struct {
+	mu     sync.RWMutex
+	clock  clock.Clock
+	events []audit.Event
+}
+
+var _ repository.IEventRepository = (*EventRepository)(nil)
+
+// NewEventRepository Creates a new, empty in-memory audit event repo instance
+func NewEventRepository(clk clock.Clock) *EventRepository {
+	return &EventRepository{clock: clk}
+}
+
+// Record appends the event
+func (repo *EventRepository) Record(ctx context.Context, tx *postgres.Tx, e *audit.Event) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e.ID = int64(len(repo.events)) + 1
+	e.CreatedAt = repo.clock.Now()
+
+	repo.events = append(repo.events, *e)
+
+	return nil
+}
+
+// FindEvents finds a page of events, newest first
+func (repo *EventRepository) FindEvents(ctx context.Context, filter audit.Filter) ([]*audit.Event, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var res []*audit.Event
+
+	for i := len(repo.events) - 1; i >= 0 && len(res) < filter.Limit; i-- {
+		e := repo.events[i]
+		if (filter.After == 0 || e.ID < filter.After) && filter.Matches(&e) {
+			res = append(res, &e)
+		}
+	}
+
+	return res, nil
+}
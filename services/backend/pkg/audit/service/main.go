@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	connect "github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/audit/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/audit/v1/auditconnect"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
+)
+
+// maxPageSize bounds the events returned by a single page.
+const maxPageSize = 500
+
+// AuditService structs
+type AuditService struct {
+	repo repository.IEventRepository
+	log  *otelzap.Logger
+	auditconnect.UnimplementedAuditServiceHandler
+}
+
+// NewAuditService for connecting to the repository
+func NewAuditService(repo repository.IEventRepository, log *otelzap.Logger) *AuditService {
+	return &AuditService{repo: repo, log: log}
+}
+
+// Register returns the registry.Service for mounting the service behind the supplied interceptors, typically the
+// transaction interceptors of its backing database.
+func Register(svc auditconnect.AuditServiceHandler, interceptors ...connect.Interceptor) registry.Service {
+	return &registry.Registration{
+		ServiceName: auditconnect.AuditServiceName,
+		HandlerFunc: func(opts ...connect.HandlerOption) (string, http.Handler) {
+			return auditconnect.NewAuditServiceHandler(svc, opts...)
+		},
+		ServiceInterceptors: interceptors,
+		GatewayFunc:         auditv1.RegisterAuditServiceHandler,
+	}
+}
+
+// ListEvents fetches a page of events, newest first
+func (svc *AuditService) ListEvents(ctx context.Context, rec *connect.Request[auditv1.ListEventsRequest]) (*connect.Response[auditv1.ListEventsResponse], error) {
+	size := rec.Msg.First
+	if size <= 0 || size > maxPageSize {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("page size must be between 1 and %d", maxPageSize))
+	}
+
+	if rec.Msg.EntityId != "" && rec.Msg.EntityType == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("entity_id requires entity_type"))
+	}
+
+	filter := audit.Filter{
+		ActorUserID: rec.Msg.ActorUserId,
+		EntityType:  rec.Msg.EntityType,
+		EntityID:    rec.Msg.EntityId,
+		After:       rec.Msg.After,
+		// Fetch one extra event to learn whether another page follows.
+		Limit: int(size) + 1,
+	}
+
+	if rec.Msg.Since != nil {
+		filter.Since = rec.Msg.Since.AsTime()
+	}
+
+	if rec.Msg.Until != nil {
+		filter.Until = rec.Msg.Until.AsTime()
+	}
+
+	records, err := svc.repo.FindEvents(ctx, filter)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error retrieving audit events: ", zap.Error(err))
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	more := len(records) > int(size)
+	if more {
+		records = records[:size]
+	}
+
+	page := &auditv1.ListEventsResponse{
+		Events:      make([]*auditv1.Event, 0, len(records)),
+		HasNextPage: more,
+	}
+
+	for _, record := range records {
+		e, err := toProto(record)
+		if err != nil {
+			svc.log.Ctx(ctx).Error("Error encoding audit event: ", zap.Error(err))
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+
+		page.Events = append(page.Events, e)
+	}
+
+	if len(records) > 0 {
+		page.EndCursor = records[len(records)-1].ID
+	}
+
+	return connect.NewResponse(page), nil
+}
+
+func toProto(e *audit.Event) (*auditv1.Event, error) {
+	// Struct only holds JSON values, so the diff goes through its JSON encoding.
+	b, err := json.Marshal(e.Diff)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &structpb.Struct{}
+	if err := diff.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+
+	return &auditv1.Event{
+		Id:                  e.ID,
+		ActorUserId:         e.ActorUserID,
+		ActorSubject:        e.ActorSubject,
+		ImpersonatorUserId:  e.ImpersonatorUserID,
+		ImpersonatorSubject: e.ImpersonatorSubject,
+		Procedure:           e.Procedure,
+		EntityType:          e.EntityType,
+		EntityId:            e.EntityID,
+		Diff:                diff,
+		RequestId:           e.RequestID,
+		CreatedAt:           timestamppb.New(e.CreatedAt),
+	}, nil
+}
@@ -0,0 +1,593 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	options "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/options/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// Authorization modes, see config.AuthzConfig.
+const (
+	ModeEnforce = "enforce"
+	ModeAudit   = "audit"
+	ModeOff     = "off"
+)
+
+// Rule grants callers access to a procedure, see the `options.v1.Rule` proto enum.
+type Rule string
+
+// Rules
+const (
+	RulePublic        Rule = "public"
+	RuleAuthenticated Rule = "authenticated"
+	RuleSelf          Rule = "self"
+	RuleOrgAdmin      Rule = "org_admin"
+	RuleAdmin         Rule = "admin"
+)
+
+// rules maps the proto enum to the rules, which are named after it without its prefix.
+var rules = map[options.Rule]Rule{
+	options.Rule_RULE_PUBLIC:        RulePublic,
+	options.Rule_RULE_AUTHENTICATED: RuleAuthenticated,
+	options.Rule_RULE_SELF:          RuleSelf,
+	options.Rule_RULE_ORG_ADMIN:     RuleOrgAdmin,
+	options.Rule_RULE_ADMIN:         RuleAdmin,
+}
+
+// Access declares who may call a procedure. Callers are allowed if any of the rules in Allow allows them. Subject is
+// the path of the request field holding the IDs of the users acted on, eg: `user.id`, for the self and org_admin
+// rules.
+type Access struct {
+	Allow   []Rule `yaml:"allow"`
+	Subject string `yaml:"subject"`
+}
+
+// File is the format of the policy file.
+type File struct {
+	Procedures map[string]Access `yaml:"procedures"`
+	// Impersonation overrides who may impersonate users, see Policy.Impersonate.
+	Impersonation *Access `yaml:"impersonation"`
+}
+
+// Memberships tells whether a user administers an organization another user is a member of, and whether they
+// administer every organization the other user administers.
+type Memberships interface {
+	Administers(ctx context.Context, adminID int64, userID int64) (bool, error)
+	Oversees(ctx context.Context, adminID int64, userID int64) (bool, error)
+}
+
+// Users finds the users that callers ask to impersonate, returning nil for those that do not exist.
+type Users interface {
+	FindUserById(ctx context.Context, id int64) (*models.User, error)
+}
+
+// Policy holds the access of every procedure. Procedures without one are denied to everyone.
+type Policy struct {
+	members Memberships
+	users   Users
+
+	mu            sync.RWMutex
+	procedures    map[string]rule
+	impersonation []Rule
+	admins        map[int64]bool
+}
+
+// rule is an Access with its subject resolved against the request message.
+type rule struct {
+	Access
+	subject []protoreflect.FieldDescriptor
+}
+
+// New returns the policy declared by the `(options.v1.access)` options of the services linked into the binary.
+func New(members Memberships, users Users) (*Policy, error) {
+	p := &Policy{
+		members:       members,
+		users:         users,
+		procedures:    map[string]rule{},
+		impersonation: []Rule{RuleAdmin},
+		admins:        map[int64]bool{},
+	}
+
+	var err error
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len() && err == nil; i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len() && err == nil; j++ {
+				err = p.declared(methods.Get(j))
+			}
+		}
+
+		return err == nil
+	})
+
+	return p, err
+}
+
+// Load returns the policy declared by the protos, overridden by the policy file of cfg, if any, with the global admins
+// of cfg.
+func Load(cfg config.AuthzConfig, members Memberships, users Users) (*Policy, error) {
+	p, err := New(members, users)
+	if err != nil {
+		return nil, err
+	}
+
+	p.SetAdmins(cfg.GetAdmins())
+
+	if cfg.PolicyFile == "" {
+		return p, nil
+	}
+
+	b, err := os.ReadFile(cfg.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.PolicyFile, err)
+	}
+
+	for procedure, a := range file.Procedures {
+		if err := p.Set(procedure, a); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.PolicyFile, err)
+		}
+	}
+
+	if file.Impersonation != nil {
+		if err := p.SetImpersonation(file.Impersonation.Allow); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.PolicyFile, err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Policy) declared(md protoreflect.MethodDescriptor) error {
+	opt, ok := proto.GetExtension(md.Options(), options.E_Access).(*options.Access)
+	if !ok || opt == nil {
+		return nil
+	}
+
+	a := Access{Subject: opt.GetSubject()}
+	for _, r := range opt.GetAllow() {
+		a.Allow = append(a.Allow, rules[r])
+	}
+
+	return p.Set(procedure(md), a)
+}
+
+// Set sets the access of a procedure, eg: `/users.v1.UserService/ModifyUser`, checking it against the procedure's
+// request message.
+func (p *Policy) Set(procedure string, a Access) error {
+	md, err := method(procedure)
+	if err != nil {
+		return err
+	}
+
+	r := rule{Access: a}
+	if a.Subject != "" {
+		if r.subject, err = resolve(md.Input(), a.Subject); err != nil {
+			return fmt.Errorf("%s: subject: %w", procedure, err)
+		}
+	}
+
+	for _, allow := range a.Allow {
+		switch allow {
+		case RulePublic, RuleAuthenticated, RuleAdmin:
+		case RuleSelf, RuleOrgAdmin:
+			if a.Subject == "" {
+				return fmt.Errorf("%s: rule %q requires a subject", procedure, allow)
+			}
+		default:
+			return fmt.Errorf("%s: unknown rule %q", procedure, allow)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.procedures[procedure] = r
+
+	return nil
+}
+
+// SetImpersonation sets the rules allowing callers to impersonate users, global admins only by default. The subject of
+// the rules is the impersonated user, so org_admin lets organization admins impersonate their members.
+func (p *Policy) SetImpersonation(allow []Rule) error {
+	for _, r := range allow {
+		if r != RuleAdmin && r != RuleOrgAdmin {
+			return fmt.Errorf("impersonation: rule %q must be admin or org_admin", r)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.impersonation = append([]Rule{}, allow...)
+
+	return nil
+}
+
+// SetAdmins sets the users who are global admins.
+func (p *Policy) SetAdmins(ids []int64) {
+	admins := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		admins[id] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.admins = admins
+}
+
+// Admin reports whether the user userID is a global admin.
+func (p *Policy) Admin(userID int64) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.admins[userID]
+}
+
+// Authenticate wraps an authentication function for auth.New, making the identities of global admins admins.
+func (p *Policy) Authenticate(f func(context.Context, *auth.Request) (any, error)) func(context.Context, *auth.Request) (any, error) {
+	return func(ctx context.Context, req *auth.Request) (any, error) {
+		identity, err := f(ctx, req)
+
+		if id, ok := identity.(*auth.Identity); ok && id != nil && id.UserID != 0 && p.Admin(id.UserID) {
+			id.Admin = true
+		}
+
+		return identity, err
+	}
+}
+
+// Access returns the access of a procedure, if it has one.
+func (p *Policy) Access(procedure string) (Access, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	r, ok := p.procedures[procedure]
+
+	return r.Access, ok
+}
+
+// Decision is the outcome of authorizing a call, with the rule that allowed it or the reason it was denied.
+type Decision struct {
+	Allowed bool
+	Rule    Rule
+	Reason  string
+}
+
+// Authorize decides whether the caller identified by id, nil if unauthenticated, may call procedure with msg. Lookups
+// that fail deny the call.
+func (p *Policy) Authorize(ctx context.Context, procedure string, id *auth.Identity, msg proto.Message) Decision {
+	p.mu.RLock()
+	r, ok := p.procedures[procedure]
+	p.mu.RUnlock()
+
+	if !ok {
+		return Decision{Reason: "no access is declared for the procedure"}
+	}
+
+	if id != nil && !id.InScope(procedure) {
+		return Decision{Reason: "the procedure is outside the scopes of the credentials"}
+	}
+
+	subjects := r.subjects(msg)
+
+	reason := "no rule allows the caller"
+	for _, allow := range r.Allow {
+		switch allowed, err := p.allows(ctx, allow, id, subjects); {
+		case err != nil:
+			reason = fmt.Sprintf("rule %s: %s", allow, err)
+		case allowed:
+			return Decision{Allowed: true, Rule: allow}
+		}
+	}
+
+	return Decision{Reason: reason}
+}
+
+// Impersonate decides whether the caller identified by id may impersonate the user userID. Impersonators can't be
+// impersonated in turn, and the user must exist. Callers that are not global admins can't impersonate those who are,
+// nor the admins of organizations they don't administer themselves, as they would act with more privileges than
+// their own.
+func (p *Policy) Impersonate(ctx context.Context, id *auth.Identity, userID int64) Decision {
+	switch {
+	case id == nil:
+		return Decision{Reason: "impersonation requires an authenticated caller"}
+	case id.Impersonator != nil:
+		return Decision{Reason: "the caller is already impersonating a user"}
+	case userID <= 0:
+		return Decision{Reason: "the impersonated user is invalid"}
+	}
+
+	p.mu.RLock()
+	allow := p.impersonation
+	p.mu.RUnlock()
+
+	reason := "no rule allows the caller to impersonate"
+	for _, r := range allow {
+		switch allowed, err := p.allows(ctx, r, id, []int64{userID}); {
+		case err != nil:
+			reason = fmt.Sprintf("rule %s: %s", r, err)
+		case allowed:
+			if reason := p.impersonable(ctx, id, userID); reason != "" {
+				return Decision{Reason: reason}
+			}
+
+			return Decision{Allowed: true, Rule: r}
+		}
+	}
+
+	return Decision{Reason: reason}
+}
+
+// impersonable returns why the caller identified by id, allowed to impersonate by a rule, may not impersonate the
+// user userID anyway, if they may not.
+func (p *Policy) impersonable(ctx context.Context, id *auth.Identity, userID int64) string {
+	if p.users == nil {
+		return "the impersonated user can't be looked up"
+	}
+
+	switch user, err := p.users.FindUserById(ctx, userID); {
+	case err != nil:
+		return fmt.Sprintf("looking up the impersonated user: %s", err)
+	case user == nil:
+		return "the impersonated user does not exist"
+	}
+
+	if id.Admin {
+		return ""
+	}
+
+	if p.Admin(userID) {
+		return "the impersonated user is a global admin"
+	}
+
+	if p.members == nil {
+		return ""
+	}
+
+	switch ok, err := p.members.Oversees(ctx, id.UserID, userID); {
+	case err != nil:
+		return fmt.Sprintf("looking up the organizations of the impersonated user: %s", err)
+	case !ok:
+		return "the impersonated user administers organizations the caller does not"
+	}
+
+	return ""
+}
+
+func (p *Policy) allows(ctx context.Context, allow Rule, id *auth.Identity, subjects []int64) (bool, error) {
+	switch {
+	case allow == RulePublic:
+		return true, nil
+	case id == nil:
+		return false, nil
+	case allow == RuleAuthenticated:
+		return true, nil
+	case allow == RuleAdmin:
+		return id.Admin, nil
+	case id.UserID == 0 || len(subjects) == 0:
+		return false, nil
+	}
+
+	for _, subject := range subjects {
+		if subject == id.UserID {
+			continue
+		}
+
+		if allow != RuleOrgAdmin || p.members == nil {
+			return false, nil
+		}
+
+		ok, err := p.members.Administers(ctx, id.UserID, subject)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// subjects returns the IDs held by the subject field of msg, none if it is unset.
+func (r rule) subjects(msg proto.Message) []int64 {
+	if msg == nil || len(r.subject) == 0 {
+		return nil
+	}
+
+	m := msg.ProtoReflect()
+	for _, fd := range r.subject[:len(r.subject)-1] {
+		if !m.Has(fd) {
+			return nil
+		}
+
+		m = m.Get(fd).Message()
+	}
+
+	fd := r.subject[len(r.subject)-1]
+	if !m.Has(fd) {
+		return nil
+	}
+
+	if !fd.IsList() {
+		return []int64{m.Get(fd).Int()}
+	}
+
+	list := m.Get(fd).List()
+
+	ids := make([]int64, list.Len())
+	for i := range ids {
+		ids[i] = list.Get(i).Int()
+	}
+
+	return ids
+}
+
+// resolve finds the fields along a dot separated path, which must go through singular messages and end at an int64
+// field, or a list of them.
+func resolve(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	names := strings.Split(path, ".")
+	fields := make([]protoreflect.FieldDescriptor, 0, len(names))
+
+	for i, name := range names {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil, fmt.Errorf("%s has no field %q", md.FullName(), name)
+		}
+
+		fields = append(fields, fd)
+
+		if i == len(names)-1 {
+			if fd.Kind() != protoreflect.Int64Kind || fd.IsMap() {
+				return nil, fmt.Errorf("%s must be an int64 or a list of them", fd.FullName())
+			}
+
+			break
+		}
+
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, fmt.Errorf("%s must be a singular message", fd.FullName())
+		}
+
+		md = fd.Message()
+	}
+
+	return fields, nil
+}
+
+// method finds the descriptor of a procedure.
+func method(procedure string) (protoreflect.MethodDescriptor, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(procedure, "/"), "/", ".")
+
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown procedure %s", procedure)
+	}
+
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a procedure", procedure)
+	}
+
+	return md, nil
+}
+
+// procedure returns the Connect procedure of a method, eg: `/users.v1.UserService/ModifyUser`.
+func procedure(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+// log returns the audit logger of authorization decisions.
+func log() *otelzap.Logger {
+	return logger.Named("authz")
+}
+
+// Interceptor authorizes RPCs against a policy. It must come after authentication, so the caller's identity is known.
+// Streams are authorized before their first message is received, so rules needing a subject deny them.
+type Interceptor struct {
+	policy *Policy
+	mode   string
+}
+
+// NewInterceptor returns an Interceptor applying policy in mode, see config.AuthzConfig.
+func NewInterceptor(policy *Policy, mode string) *Interceptor {
+	return &Interceptor{policy: policy, mode: mode}
+}
+
+func (i *Interceptor) authorize(ctx context.Context, procedure string, msg proto.Message) error {
+	id, _ := auth.IdentityFromContext(ctx)
+	decision := i.policy.Authorize(ctx, procedure, id, msg)
+
+	fields := []zap.Field{
+		access.Field(ctx),
+		zap.String("procedure", procedure),
+		zap.Bool("allowed", decision.Allowed),
+		zap.String("mode", i.mode),
+	}
+
+	if id != nil {
+		fields = append(fields, zap.Int64("user_id", id.UserID), zap.String("subject", id.Subject))
+	}
+
+	if id != nil && id.Impersonator != nil {
+		fields = append(fields,
+			zap.Int64("impersonator_user_id", id.Impersonator.UserID),
+			zap.String("impersonator_subject", id.Impersonator.Subject),
+		)
+	}
+
+	if decision.Allowed {
+		fields = append(fields, zap.String("rule", string(decision.Rule)))
+	} else {
+		fields = append(fields, zap.String("reason", decision.Reason))
+	}
+
+	log().Ctx(ctx).Info("Authorization decision", fields...)
+
+	switch {
+	case decision.Allowed || i.mode != ModeEnforce:
+		return nil
+	case id == nil:
+		return auth.Errorf("authentication is required")
+	default:
+		return connect.NewError(connect.CodePermissionDenied, errors.New("permission denied"))
+	}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient || i.mode == ModeOff {
+			return next(ctx, req)
+		}
+
+		msg, _ := req.Any().(proto.Message)
+		if err := i.authorize(ctx, req.Spec().Procedure, msg); err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if i.mode == ModeOff {
+			return next(ctx, conn)
+		}
+
+		if err := i.authorize(ctx, conn.Spec().Procedure, nil); err != nil {
+			return err
+		}
+
+		return next(ctx, conn)
+	}
+}
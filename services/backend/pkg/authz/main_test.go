@@ -0,0 +1,314 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/authz"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	orgMemory "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository/memory"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	userMemory "github.com/jmandel1027/perspex/services/backend/pkg/user/repository/memory"
+)
+
+// fixture is a policy declared by the protos, where root is a global admin and admin administers the organization
+// that member and rival belong to.
+type fixture struct {
+	policy                     *authz.Policy
+	root, admin, member, rival int64
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	ctx := context.Background()
+	members := orgMemory.NewMembershipRepository(audit.Nop(), outbox.Nop())
+	repo := userMemory.NewUserRepository(clock.New(), audit.Nop(), outbox.Nop(), members)
+
+	create := func(email string) int64 {
+		u, err := repo.CreateUser(ctx, &models.User{Email: email})
+		if err != nil {
+			t.Fatalf("CreateUser %s: %v", email, err)
+		}
+
+		return u.ID
+	}
+
+	f := &fixture{
+		root:   create("root@perspex.us"),
+		admin:  create("admin@perspex.us"),
+		member: create("member@perspex.us"),
+		rival:  create("rival@perspex.us"),
+	}
+
+	for userID, role := range map[int64]string{f.admin: orgRepository.RoleAdmin, f.member: orgRepository.RoleMember, f.rival: orgRepository.RoleMember} {
+		if err := members.AddMember(ctx, 1, userID, role); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+
+	policy, err := authz.New(members, repo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	policy.SetAdmins([]int64{f.root})
+	f.policy = policy
+
+	return f
+}
+
+func TestAuthorize(t *testing.T) {
+	f := newFixture(t)
+
+	const (
+		modify   = "/users.v1.UserService/ModifyUser"
+		retrieve = "/users.v1.UserService/RetrieveUsers"
+		register = "/users.v1.UserService/RegisterUser"
+	)
+
+	modifying := func(id int64) proto.Message {
+		return &users.ModifyUserRequest{User: &users.User{Id: id}}
+	}
+
+	retrieving := func(ids ...int64) proto.Message {
+		return &users.RetrieveUsersRequest{Ids: ids}
+	}
+
+	cases := map[string]struct {
+		procedure string
+		id        *auth.Identity
+		msg       proto.Message
+		rule      authz.Rule
+	}{
+		"Self":               {procedure: modify, id: &auth.Identity{UserID: f.member}, msg: modifying(f.member), rule: authz.RuleSelf},
+		"OrgAdmin":           {procedure: modify, id: &auth.Identity{UserID: f.admin}, msg: modifying(f.member), rule: authz.RuleOrgAdmin},
+		"Admin":              {procedure: modify, id: &auth.Identity{UserID: f.root, Admin: true}, msg: modifying(f.member), rule: authz.RuleAdmin},
+		"OtherMember":        {procedure: modify, id: &auth.Identity{UserID: f.rival}, msg: modifying(f.member)},
+		"MemberOfOrgAdmin":   {procedure: modify, id: &auth.Identity{UserID: f.member}, msg: modifying(f.admin)},
+		"Unauthenticated":    {procedure: modify, msg: modifying(f.member)},
+		"UnsetParent":        {procedure: modify, id: &auth.Identity{UserID: f.member}, msg: &users.ModifyUserRequest{}},
+		"UnsetSubject":       {procedure: modify, id: &auth.Identity{UserID: f.member}, msg: modifying(0)},
+		"UnsetSubjectAdmin":  {procedure: modify, id: &auth.Identity{UserID: f.root, Admin: true}, msg: &users.ModifyUserRequest{}, rule: authz.RuleAdmin},
+		"RepeatedSelf":       {procedure: retrieve, id: &auth.Identity{UserID: f.member}, msg: retrieving(f.member, f.member), rule: authz.RuleSelf},
+		"RepeatedOthers":     {procedure: retrieve, id: &auth.Identity{UserID: f.member}, msg: retrieving(f.member, f.rival)},
+		"RepeatedOrgAdmin":   {procedure: retrieve, id: &auth.Identity{UserID: f.admin}, msg: retrieving(f.admin, f.member, f.rival), rule: authz.RuleOrgAdmin},
+		"RepeatedOutsideOrg": {procedure: retrieve, id: &auth.Identity{UserID: f.admin}, msg: retrieving(f.member, f.root)},
+		"RepeatedEmpty":      {procedure: retrieve, id: &auth.Identity{UserID: f.member}, msg: retrieving()},
+		"InServiceScope":     {procedure: modify, id: &auth.Identity{UserID: f.member, Scopes: []string{"users.v1.UserService"}}, msg: modifying(f.member), rule: authz.RuleSelf},
+		"InProcedureScope":   {procedure: modify, id: &auth.Identity{UserID: f.member, Scopes: []string{"/users.v1.UserService/ModifyUser"}}, msg: modifying(f.member), rule: authz.RuleSelf},
+		"OutOfScope":         {procedure: modify, id: &auth.Identity{UserID: f.member, Scopes: []string{"users.v1.UserService/RetrieveUser"}}, msg: modifying(f.member)},
+		"OutOfScopeAdmin":    {procedure: modify, id: &auth.Identity{UserID: f.root, Admin: true, Scopes: []string{"apikeys.v1.ApiKeyService"}}, msg: modifying(f.member)},
+		"Public":             {procedure: register, msg: &users.RegisterUserRequest{}, rule: authz.RulePublic},
+		"Undeclared":         {procedure: "/users.v1.UserService/PurgeUsers", id: &auth.Identity{UserID: f.root, Admin: true}},
+		"UndeclaredService":  {procedure: "/grpc.health.v1.Health/Check", id: &auth.Identity{UserID: f.root, Admin: true}},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			decision := f.policy.Authorize(context.Background(), c.procedure, c.id, c.msg)
+
+			if decision.Allowed != (c.rule != "") || decision.Rule != c.rule {
+				t.Fatalf("expected to be allowed by %q, got %+v", c.rule, decision)
+			}
+
+			if !decision.Allowed && decision.Reason == "" {
+				t.Fatalf("expected a reason for the denial, got %+v", decision)
+			}
+		})
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	f := newFixture(t)
+
+	identities := map[string]*auth.Identity{
+		"member": {UserID: f.member},
+		"rival":  {UserID: f.rival},
+	}
+
+	// Callers present their name as a bearer token, and are unauthenticated without one.
+	authenticate := func(_ context.Context, req *auth.Request) (any, error) {
+		token, ok := auth.BearerToken(req.Header)
+		if !ok {
+			return nil, nil
+		}
+
+		return identities[token], nil
+	}
+
+	cases := map[string]struct {
+		mode   string
+		caller string
+		id     int64
+		stream bool
+		code   connect.Code
+	}{
+		// The handlers are unimplemented, so calls that get through fail as such.
+		"EnforceAllowed":         {mode: authz.ModeEnforce, caller: "member", id: f.member, code: connect.CodeUnimplemented},
+		"EnforceDenied":          {mode: authz.ModeEnforce, caller: "rival", id: f.member, code: connect.CodePermissionDenied},
+		"EnforceUnauthenticated": {mode: authz.ModeEnforce, id: f.member, code: connect.CodeUnauthenticated},
+		"AuditDenied":            {mode: authz.ModeAudit, caller: "rival", id: f.member, code: connect.CodeUnimplemented},
+		"AuditUnauthenticated":   {mode: authz.ModeAudit, id: f.member, code: connect.CodeUnimplemented},
+		"OffDenied":              {mode: authz.ModeOff, caller: "rival", id: f.member, code: connect.CodeUnimplemented},
+		"EnforceStreamAllowed":   {mode: authz.ModeEnforce, caller: "member", stream: true, code: connect.CodeUnimplemented},
+		"EnforceStreamDenied":    {mode: authz.ModeEnforce, stream: true, code: connect.CodeUnauthenticated},
+		"AuditStreamDenied":      {mode: authz.ModeAudit, stream: true, code: connect.CodeUnimplemented},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			path, handler := usersconnect.NewUserServiceHandler(
+				usersconnect.UnimplementedUserServiceHandler{},
+				connect.WithInterceptors(auth.New(authenticate), authz.NewInterceptor(f.policy, c.mode)),
+			)
+
+			mux := http.NewServeMux()
+			mux.Handle(path, handler)
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			client := usersconnect.NewUserServiceClient(server.Client(), server.URL)
+
+			var err error
+			if c.stream {
+				req := connect.NewRequest(&users.WatchUsersRequest{})
+				if c.caller != "" {
+					req.Header().Set("Authorization", "Bearer "+c.caller)
+				}
+
+				var stream *connect.ServerStreamForClient[users.WatchUsersResponse]
+				if stream, err = client.WatchUsers(context.Background(), req); err == nil {
+					for stream.Receive() {
+					}
+
+					err = stream.Err()
+				}
+			} else {
+				req := connect.NewRequest(&users.ModifyUserRequest{User: &users.User{Id: c.id}})
+				if c.caller != "" {
+					req.Header().Set("Authorization", "Bearer "+c.caller)
+				}
+
+				_, err = client.ModifyUser(context.Background(), req)
+			}
+
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) || connectErr.Code() != c.code {
+				t.Fatalf("expected %s, got %v", c.code, err)
+			}
+		})
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	ctx := context.Background()
+	members := orgMemory.NewMembershipRepository(audit.Nop(), outbox.Nop())
+	users := userMemory.NewUserRepository(clock.New(), audit.Nop(), outbox.Nop(), members)
+
+	create := func(email string) int64 {
+		u, err := users.CreateUser(ctx, &models.User{Email: email})
+		if err != nil {
+			t.Fatalf("CreateUser %s: %v", email, err)
+		}
+
+		return u.ID
+	}
+
+	join := func(orgID, userID int64, role string) {
+		if err := members.AddMember(ctx, orgID, userID, role); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+
+	root := create("root@perspex.us")
+	admin := create("admin@perspex.us")
+	member := create("member@perspex.us")
+	rival := create("rival@perspex.us")
+
+	// The rival is a member of the admin's organization, and administers another.
+	join(1, admin, orgRepository.RoleAdmin)
+	join(1, member, orgRepository.RoleMember)
+	join(1, rival, orgRepository.RoleMember)
+	join(1, root, orgRepository.RoleMember)
+	join(2, rival, orgRepository.RoleAdmin)
+
+	policy, err := authz.New(members, users)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	policy.SetAdmins([]int64{root})
+	if err := policy.SetImpersonation([]authz.Rule{authz.RuleAdmin, authz.RuleOrgAdmin}); err != nil {
+		t.Fatalf("SetImpersonation: %v", err)
+	}
+
+	cases := map[string]struct {
+		id      *auth.Identity
+		target  int64
+		allowed bool
+	}{
+		"AdminImpersonatesMember":        {id: &auth.Identity{UserID: root, Admin: true}, target: member, allowed: true},
+		"AdminImpersonatesOrgAdmin":      {id: &auth.Identity{UserID: root, Admin: true}, target: rival, allowed: true},
+		"AdminImpersonatesNobody":        {id: &auth.Identity{UserID: root, Admin: true}, target: 999},
+		"OrgAdminImpersonatesMember":     {id: &auth.Identity{UserID: admin}, target: member, allowed: true},
+		"OrgAdminImpersonatesOrgAdmin":   {id: &auth.Identity{UserID: admin}, target: rival},
+		"OrgAdminImpersonatesAdmin":      {id: &auth.Identity{UserID: admin}, target: root},
+		"MemberImpersonatesMember":       {id: &auth.Identity{UserID: member}, target: rival},
+		"ImpersonatorImpersonatesMember": {id: &auth.Identity{UserID: member, Impersonator: &auth.Identity{UserID: root, Admin: true}}, target: rival},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			decision := policy.Impersonate(ctx, c.id, c.target)
+			if decision.Allowed != c.allowed {
+				t.Fatalf("expected allowed to be %v, got %+v", c.allowed, decision)
+			}
+		})
+	}
+}
+
+func TestAuthenticateGrantsAdmins(t *testing.T) {
+	policy, err := authz.New(nil, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	policy.SetAdmins([]int64{1})
+
+	for userID, admin := range map[int64]bool{1: true, 2: false} {
+		userID := userID
+
+		authenticate := policy.Authenticate(func(context.Context, *auth.Request) (any, error) {
+			return &auth.Identity{UserID: userID}, nil
+		})
+
+		identity, err := authenticate(context.Background(), &auth.Request{})
+		if err != nil {
+			t.Fatalf("authenticate: %v", err)
+		}
+
+		if got := identity.(*auth.Identity).Admin; got != admin {
+			t.Fatalf("user %d: expected admin to be %v, got %v", userID, admin, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+)
+
+// Cache is a key-value store for caching serialized records.
+type Cache interface {
+	// Get returns the value stored under key, and false if it is absent or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for the provided duration. A zero ttl never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key, if present.
+	Delete(ctx context.Context, key string) error
+}
+
+type nop struct{}
+
+// NewNop returns a Cache that stores nothing, so every lookup misses.
+func NewNop() Cache {
+	return nop{}
+}
+
+// Get implements Cache.
+func (nop) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+// Set implements Cache.
+func (nop) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+// Delete implements Cache.
+func (nop) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+type entry struct {
+	value   []byte
+	expires time.Time
+}
+
+// Memory is a process-local Cache.
+type Memory struct {
+	mu      sync.RWMutex
+	clock   clock.Clock
+	entries map[string]entry
+}
+
+// NewMemory returns a process-local Cache that expires entries using the provided clock.
+func NewMemory(clk clock.Clock) *Memory {
+	return &Memory{
+		clock:   clk,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get implements Cache.
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e, ok := m.entries[key]
+	if !ok || (!e.expires.IsZero() && !m.clock.Now().Before(e.expires)) {
+		return nil, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+// Set implements Cache.
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expires = m.clock.Now().Add(ttl)
+	}
+
+	m.entries[key] = e
+
+	return nil
+}
+
+// Delete implements Cache.
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/drift"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/migrate"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/server"
+)
+
+const usage = `Usage:
+    backend [flags]                         Start the server
+    backend serve [flags]                   Start the server
+    backend migrate [flags] up              Apply all pending migrations
+    backend migrate [flags] down [n]        Revert the last n migrations, default 1, or all with -1
+    backend migrate [flags] status          List migrations and whether they are applied
+    backend migrate [flags] force <version> Mark a version as applied and clean, after fixing a failed migration by hand
+    backend drift [flags]                   Check the sqlboiler models match the schema the migrations produce
+    backend config print [--redact] [flags] Print the effective config as YAML, optionally hiding secrets
+
+Every command accepts the config flags, see ` + "`backend serve -h`" + `. They take precedence over the environment, which
+takes precedence over the config file, which takes precedence over the defaults.
+`
+
+// Run runs the command named by args, returning the process exit code.
+func Run(args []string) int {
+	if len(args) == 0 || args[0] == "serve" || strings.HasPrefix(args[0], "-") && !isHelp(args[0]) {
+		if len(args) > 0 && args[0] == "serve" {
+			args = args[1:]
+		}
+
+		cfg, _, code := load("serve", args, nil)
+		if code >= 0 {
+			return code
+		}
+
+		go server.Serve(cfg)
+
+		select {}
+	}
+
+	switch args[0] {
+	case "migrate":
+		return Migrate(args[1:], os.Stdout)
+	case "drift":
+		return Drift(args[1:], os.Stdout)
+	case "config":
+		return Config(args[1:], os.Stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+// load loads the config for the named command, letting extra register flags of its own. It returns the arguments
+// left after the flags, and an exit code of -1 unless the command should stop.
+func load(name string, args []string, extra func(fs *flag.FlagSet)) (config.BackendConfig, []string, int) {
+	fs := flag.NewFlagSet("backend "+name, flag.ContinueOnError)
+	if extra != nil {
+		extra(fs)
+	}
+
+	cfg, rest, err := config.LoadFlags(fs, args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return cfg, nil, 0
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "config error: %s\n", err)
+		return cfg, nil, 1
+	}
+
+	return cfg, rest, -1
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "--help" || arg == "-help"
+}
+
+// Config prints the effective config, after every source has been applied.
+func Config(args []string, out io.Writer) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	var redact bool
+
+	cfg, _, code := load("config print", args[1:], func(fs *flag.FlagSet) {
+		fs.BoolVar(&redact, "redact", false, "Replace secrets, eg: passwords, with "+config.Redacted)
+	})
+	if code >= 0 {
+		return code
+	}
+
+	if redact {
+		cfg = cfg.Redacted()
+	}
+
+	b, err := cfg.YAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config print: %s\n", err)
+		return 1
+	}
+
+	out.Write(b)
+
+	return 0
+}
+
+// Migrate runs a migration subcommand against the writer database.
+func Migrate(args []string, out io.Writer) int {
+	cfg, args, code := load("migrate", args, nil)
+	if code >= 0 {
+		return code
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	z := logger.New(cfg)
+	defer z.Sync()
+
+	undo := logger.ReplaceGlobals(z)
+	defer undo()
+
+	dbs, err := postgres.Open(&cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "postgres connection error: %s\n", err)
+		return 1
+	}
+
+	defer dbs.Close()
+
+	migrator, err := migrate.Embedded(dbs.Writer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading migrations: %s\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid number of steps %q\n", args[1])
+				return 2
+			}
+		}
+
+		err = migrator.Down(ctx, steps)
+	case "force":
+		if len(args) < 2 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[1])
+			return 2
+		}
+
+		err = migrator.Force(ctx, version)
+	case "status":
+		err = status(ctx, migrator, out)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s: %s\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// Drift applies the migrations to a scratch database on the writer's server and reports how the generated models
+// differ from it, failing when they are stale.
+func Drift(args []string, out io.Writer) int {
+	cfg, _, code := load("drift", args, nil)
+	if code >= 0 {
+		return code
+	}
+
+	z := logger.New(cfg)
+	defer z.Sync()
+
+	undo := logger.ReplaceGlobals(z)
+	defer undo()
+
+	diffs, err := drift.Check(context.Background(), cfg.WriterPG)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drift: %s\n", err)
+		return 1
+	}
+
+	if len(diffs) == 0 {
+		fmt.Fprintln(out, "Models are up to date with the migrations.")
+		return 0
+	}
+
+	fmt.Fprintln(out, "Models are out of date with the migrations, regenerate them with `bin/prepare.sh -bb`:")
+	for _, d := range diffs {
+		fmt.Fprintf(out, "  %s\n", d)
+	}
+
+	return 1
+}
+
+func status(ctx context.Context, migrator *migrate.Migrator, out io.Writer) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+
+	for _, s := range statuses {
+		state := "pending"
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Applied:
+			state = "applied"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, state)
+	}
+
+	return w.Flush()
+}
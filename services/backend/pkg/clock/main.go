@@ -0,0 +1,34 @@
+package clock
+
+import "time"
+
+// Clock tells the current time. It is injected wherever the current time is read, so callers can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+type system struct{}
+
+// New returns a Clock backed by the system time.
+func New() Clock {
+	return system{}
+}
+
+// Now implements Clock.
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+type fixed struct {
+	t time.Time
+}
+
+// Fixed returns a Clock that always reports the provided time.
+func Fixed(t time.Time) Clock {
+	return fixed{t}
+}
+
+// Now implements Clock.
+func (f fixed) Now() time.Time {
+	return f.t
+}
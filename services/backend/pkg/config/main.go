@@ -1,102 +1,799 @@
 package config
 
 import (
+	"encoding"
+	"errors"
+	"flag"
 	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/jmandel1027/perspex/services/backend/pkg/utils"
+	"github.com/BurntSushi/toml"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
 )
 
-// LogConfig defines the configuration for the logger
+// Storage backends selectable with BACKEND_STORAGE.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// FileEnv names the config file to load, when the `--config` flag is not passed. The format is picked from the
+// extension, either `.yaml`, `.yml` or `.toml`.
+const FileEnv = "BACKEND_CONFIG_FILE"
+
+// Redacted replaces secrets in the output of BackendConfig.Redacted.
+const Redacted = "REDACTED"
+
+// LogConfig defines the configuration for the logger. Level is the root level, eg: `info`, and Levels sets those of
+// subsystems apart from it, as comma separated `name=level` pairs, eg: `postgres=debug,user=warn`. Both may be changed
+// while running, see logger.LevelsHandler.
+//
+// Sampled loggers, used on hot paths, log the first SampleInitial of each message within a second and every
+// SampleThereafter after that. A zero SampleInitial disables sampling.
 type LogConfig struct {
-	Verbose bool
+	Verbose          bool   `yaml:"verbose" env:"LOG_MODE,LOGMODE" flag:"verbose" default:"false"`
+	Level            string `yaml:"level" env:"LOG_LEVEL" flag:"level" default:"info"`
+	Levels           string `yaml:"levels" env:"LOG_LEVELS" flag:"levels"`
+	SampleInitial    int    `yaml:"sample_initial" env:"LOG_SAMPLE_INITIAL" flag:"sample-initial" default:"100"`
+	SampleThereafter int    `yaml:"sample_thereafter" env:"LOG_SAMPLE_THEREAFTER" flag:"sample-thereafter" default:"100"`
+}
+
+// GetLevels parses the subsystem levels.
+func (l LogConfig) GetLevels() (map[string]zapcore.Level, error) {
+	levels := map[string]zapcore.Level{}
+
+	for _, pair := range strings.Split(l.Levels, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("level %q must look like `name=level`", pair)
+		}
+
+		level, err := zapcore.ParseLevel(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+
+		levels[strings.TrimSpace(name)] = level
+	}
+
+	return levels, nil
 }
 
-// PostgresConfig configures the PostgreSQL connection.
+// PostgresConfig configures the PostgreSQL connection. The password is read from PasswordSecret, a secrets provider
+// reference, eg: `file:///var/run/secrets/postgres/password`, or else from PasswordFile, or else Password.
+//
+// Host and Port may list several comma separated servers, tried in order until one matches TargetSessionAttrs. A
+// single port applies to every host. Schema and TimeZone are set on every connection as the `search_path` and
+// `timezone`, and a zero StatementTimeout is unlimited.
+//
+// The pool keeps at least MinConns and at most MaxOpenConns connections, closing those idle for MaxIdleTime or open
+// for MaxLifespan.
 type PostgresConfig struct {
-	Name         string
-	User         string
-	Password     string
-	Host         string
-	Port         string
-	Schema       string
-	MaxOpenConns int
-	MaxIdleConns int
-	MaxLifespan  int
-	Debug        bool
-}
-
-// RedisConfig defines a redis connection configuration.
+	Name               string   `yaml:"name" env:"DB" flag:"name" default:"perspex"`
+	User               string   `yaml:"user" env:"USER" flag:"user" default:"perspex"`
+	Password           string   `yaml:"password" env:"PASSWORD" flag:"password" default:"pass" secret:"true"`
+	PasswordFile       string   `yaml:"password_file" env:"PASSWORD_FILE" flag:"password-file"`
+	PasswordSecret     string   `yaml:"password_secret" env:"PASSWORD_SECRET" flag:"password-secret"`
+	Host               string   `yaml:"host" env:"HOST" flag:"host" default:"postgresql.perspex.svc.cluster.local"`
+	Port               string   `yaml:"port" env:"PORT" flag:"port" default:"5432"`
+	Schema             string   `yaml:"schema" env:"SCHEMA" flag:"schema" default:"public"`
+	MaxOpenConns       int      `yaml:"max_open_conns" env:"MAX_OPEN_CONNECTIONS,MAXOPENCONNECTIONS" flag:"max-open-conns" default:"100"`
+	MinConns           int      `yaml:"min_conns" env:"MIN_CONNECTIONS" flag:"min-conns" default:"0"`
+	MaxIdleTime        Duration `yaml:"max_idle_time" env:"CONNECTION_IDLE_TIME" flag:"max-idle-time" default:"30m"`
+	MaxLifespan        Duration `yaml:"max_lifespan" env:"CONNECTION_LIFESPAN" flag:"max-lifespan" default:"128s"`
+	Debug              bool     `yaml:"debug" env:"DEBUG" flag:"debug" default:"false"`
+	SSLMode            string   `yaml:"sslmode" env:"SSLMODE" flag:"sslmode" default:"disable"`
+	SSLRootCert        string   `yaml:"sslrootcert" env:"SSLROOTCERT" flag:"sslrootcert"`
+	SSLCert            string   `yaml:"sslcert" env:"SSLCERT" flag:"sslcert"`
+	SSLKey             string   `yaml:"sslkey" env:"SSLKEY" flag:"sslkey"`
+	ApplicationName    string   `yaml:"application_name" env:"APPLICATION_NAME" flag:"application-name" default:"perspex-backend"`
+	ConnectTimeout     Duration `yaml:"connect_timeout" env:"CONNECT_TIMEOUT" flag:"connect-timeout" default:"10s"`
+	StatementTimeout   Duration `yaml:"statement_timeout" env:"STATEMENT_TIMEOUT" flag:"statement-timeout" default:"0s"`
+	TargetSessionAttrs string   `yaml:"target_session_attrs" env:"TARGET_SESSION_ATTRS" flag:"target-session-attrs" default:"any"`
+	TimeZone           string   `yaml:"timezone" env:"TIMEZONE" flag:"timezone" default:"UTC"`
+}
+
+// Accepted values of PostgresConfig.SSLMode and PostgresConfig.TargetSessionAttrs, as understood by libpq.
+var (
+	SSLModes           = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	TargetSessionAttrs = []string{"any", "read-write", "read-only", "primary", "standby", "prefer-standby"}
+)
+
+// RedisConfig defines a redis connection configuration. Like PostgresConfig, the password may be read from a secret.
 type RedisConfig struct {
-	Host               string
-	Password           string
-	Port               string
-	DB                 int
-	InsecureSkipVerify bool
-	TLS                bool
+	Host               string `yaml:"host" env:"HOST" flag:"host" default:"redis-writer.perspex"`
+	Password           string `yaml:"password" env:"PASSWORD" flag:"password" default:"pass" secret:"true"`
+	PasswordFile       string `yaml:"password_file" env:"PASSWORD_FILE" flag:"password-file"`
+	PasswordSecret     string `yaml:"password_secret" env:"PASSWORD_SECRET" flag:"password-secret"`
+	Port               string `yaml:"port" env:"PORT" flag:"port" default:"6369"`
+	DB                 int    `yaml:"db" env:"DB" flag:"db" default:"0"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"SKIP_VERIFY" flag:"skip-verify" default:"false"`
+	TLS                bool   `yaml:"tls" env:"TLS" flag:"tls" default:"false"`
+}
+
+// HTTPConfig defines the limits of the HTTP server.
+type HTTPConfig struct {
+	ReadTimeout     Duration `yaml:"read_timeout" env:"READ_TIMEOUT" flag:"read-timeout" default:"5m"`
+	WriteTimeout    Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT" flag:"write-timeout" default:"5m"`
+	IdleTimeout     Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT" flag:"idle-timeout" default:"5m"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"60s"`
+	MaxHeaderBytes  ByteSize `yaml:"max_header_bytes" env:"MAX_HEADER_BYTES" flag:"max-header-bytes" default:"8KiB"`
+}
+
+// RedactConfig controls how personal data is hidden from logs and traces. Fields are either masked, replaced with
+// Redacted, or hashed, replaced with a keyed hash so that values can still be correlated without being revealed. The
+// Key must be set to hash.
+// Fields named in Allow, comma separated, are left as they are, eg: to debug locally.
+type RedactConfig struct {
+	Mode  string `yaml:"mode" env:"MODE" flag:"mode" default:"mask"`
+	Key   string `yaml:"key" env:"KEY" flag:"key" secret:"true"`
+	Allow string `yaml:"allow" env:"ALLOW" flag:"allow"`
+}
+
+// Accepted values of RedactConfig.Mode.
+var RedactModes = []string{"mask", "hash"}
+
+// GetAllow lists the fields in Allow.
+func (r RedactConfig) GetAllow() []string {
+	return split(r.Allow)
 }
 
-// BackendConfig defines the configuration for the server
+// CORSConfig controls which browser origins may call the API. The Profile picks the origins allowed on top of
+// AllowedOrigins: `development` adds the frontend's dev server on any port of localhost, `production` adds none.
+// Origins are comma separated and match exactly, or with a single `*` wildcard, eg: `https://*.perspex.app` for any
+// subdomain. A lone `*` is refused, as credentials are allowed.
+//
+// The headers used by Connect, gRPC-Web and the REST gateway are always allowed and exposed, AllowedHeaders and
+// ExposedHeaders add to them.
+type CORSConfig struct {
+	Profile          string   `yaml:"profile" env:"PROFILE" flag:"profile" default:"production"`
+	AllowedOrigins   string   `yaml:"allowed_origins" env:"ALLOWED_ORIGINS" flag:"allowed-origins"`
+	AllowedMethods   string   `yaml:"allowed_methods" env:"ALLOWED_METHODS" flag:"allowed-methods" default:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   string   `yaml:"allowed_headers" env:"ALLOWED_HEADERS" flag:"allowed-headers"`
+	ExposedHeaders   string   `yaml:"exposed_headers" env:"EXPOSED_HEADERS" flag:"exposed-headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" env:"ALLOW_CREDENTIALS" flag:"allow-credentials" default:"true"`
+	MaxAge           Duration `yaml:"max_age" env:"MAX_AGE" flag:"max-age" default:"2h"`
+}
+
+// CORS profiles, see CORSConfig.
+const (
+	CORSDevelopment = "development"
+	CORSProduction  = "production"
+)
+
+// Accepted values of CORSConfig.Profile, and the origins each allows.
+var (
+	CORSProfiles = []string{CORSDevelopment, CORSProduction}
+
+	corsOrigins = map[string][]string{
+		CORSDevelopment: {"http://localhost:*", "http://127.0.0.1:*"},
+		CORSProduction:  nil,
+	}
+)
+
+// GetAllowedOrigins lists the origins of the profile, then those in AllowedOrigins.
+func (c CORSConfig) GetAllowedOrigins() []string {
+	return append(append([]string{}, corsOrigins[c.Profile]...), split(c.AllowedOrigins)...)
+}
+
+// GetAllowedMethods lists the methods in AllowedMethods.
+func (c CORSConfig) GetAllowedMethods() []string {
+	return split(c.AllowedMethods)
+}
+
+// GetAllowedHeaders lists the headers in AllowedHeaders.
+func (c CORSConfig) GetAllowedHeaders() []string {
+	return split(c.AllowedHeaders)
+}
+
+// GetExposedHeaders lists the headers in ExposedHeaders.
+func (c CORSConfig) GetExposedHeaders() []string {
+	return split(c.ExposedHeaders)
+}
+
+// AdminConfig guards the admin endpoints, such as changing log levels, which are only served when a Token is set.
+// Admins present it as a bearer token.
+type AdminConfig struct {
+	Token string `yaml:"token" env:"TOKEN" flag:"token" secret:"true"`
+}
+
+// AuthzConfig controls the authorization of RPCs. Rules are declared on each method with the `(options.v1.access)`
+// option, and PolicyFile, a YAML file, may override them by procedure, eg:
+//
+//	procedures:
+//	  /users.v1.UserService/RetrieveUsersPage:
+//	    allow: [admin, org_admin]
+//
+// Mode is `enforce`, denying callers the rules do not allow, `audit`, only logging the decisions that would deny
+// them, or `off`. Admins lists the IDs of the users who are global admins, comma separated, whatever credentials they
+// authenticate with.
+type AuthzConfig struct {
+	Mode       string `yaml:"mode" env:"MODE" flag:"mode" default:"enforce"`
+	PolicyFile string `yaml:"policy_file" env:"POLICY_FILE" flag:"policy-file"`
+	Admins     string `yaml:"admins" env:"ADMINS" flag:"admins"`
+}
+
+// Accepted values of AuthzConfig.Mode.
+var AuthzModes = []string{"enforce", "audit", "off"}
+
+// GetAdmins lists the user IDs in Admins, skipping any that are invalid.
+func (a AuthzConfig) GetAdmins() []int64 {
+	var ids []int64
+	for _, v := range split(a.Admins) {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// OutboxConfig controls the relay of domain events from the outbox, every Interval or as soon as a full batch has been
+// published. Sink is `redis`, adding them to the Stream on the connection configured by RedisConfig, `webhook`,
+// posting them to WebhookURL, or `none`, leaving them for another relay. The relay runs regardless of Sink while
+// WebhooksConfig is enabled, to queue events for the endpoints of organizations. Published events are kept for the
+// Retention, then deleted by the relay.
+type OutboxConfig struct {
+	Sink       string   `yaml:"sink" env:"SINK" flag:"sink" default:"none"`
+	Interval   Duration `yaml:"interval" env:"INTERVAL" flag:"interval" default:"1s"`
+	BatchSize  int      `yaml:"batch_size" env:"BATCH_SIZE" flag:"batch-size" default:"100"`
+	Stream     string   `yaml:"stream" env:"STREAM" flag:"stream" default:"perspex:events"`
+	WebhookURL string   `yaml:"webhook_url" env:"WEBHOOK_URL" flag:"webhook-url"`
+	Retention  Duration `yaml:"retention" env:"RETENTION" flag:"retention" default:"168h"`
+}
+
+// Accepted values of OutboxConfig.Sink.
+var OutboxSinks = []string{"none", "redis", "webhook"}
+
+// WebhooksConfig controls the delivery of domain events to the webhook endpoints of organizations. Every Interval, or
+// as soon as a full batch has been attempted, up to BatchSize due deliveries are posted at once, each waiting up to
+// Timeout for a response. A delivery is retried with exponential backoff until MaxAttempts have failed, and an endpoint
+// disabled once DisableAfter attempts in a row have. Endpoints may not point to loopback, private or link-local
+// addresses unless AllowPrivate, for development against local receivers.
+type WebhooksConfig struct {
+	Enabled      bool     `yaml:"enabled" env:"ENABLED" flag:"enabled" default:"true"`
+	Interval     Duration `yaml:"interval" env:"INTERVAL" flag:"interval" default:"1s"`
+	BatchSize    int      `yaml:"batch_size" env:"BATCH_SIZE" flag:"batch-size" default:"20"`
+	Timeout      Duration `yaml:"timeout" env:"TIMEOUT" flag:"timeout" default:"10s"`
+	MaxAttempts  int      `yaml:"max_attempts" env:"MAX_ATTEMPTS" flag:"max-attempts" default:"12"`
+	DisableAfter int      `yaml:"disable_after" env:"DISABLE_AFTER" flag:"disable-after" default:"50"`
+	AllowPrivate bool     `yaml:"allow_private" env:"ALLOW_PRIVATE" flag:"allow-private" default:"false"`
+}
+
+// WatchConfig controls the streams of changes to users. A Heartbeat is sent on a stream after that long without
+// changes, and changes are kept for the Retention, after which a watcher can no longer resume from them.
+type WatchConfig struct {
+	Heartbeat Duration `yaml:"heartbeat" env:"HEARTBEAT" flag:"heartbeat" default:"15s"`
+	Retention Duration `yaml:"retention" env:"RETENTION" flag:"retention" default:"24h"`
+}
+
+// TelemetryConfig configures the OpenTelemetry trace and metric exporters. Variables are named as the OpenTelemetry
+// SDKs name them, except for the pod's, which are meant to be set from the Kubernetes downward API.
+//
+// Exporters are `none`, `otlp` or, for traces only, `console`. OTLP is sent over `grpc` or `http/protobuf` to the
+// `host:port` Endpoint, or to the exporter's default endpoint when it is unset. Headers are comma separated
+// `key=value` pairs, eg: for a collector's API key. A parent's sampling decision is followed, otherwise SampleRatio
+// of new traces are sampled.
+type TelemetryConfig struct {
+	TracesExporter  string   `yaml:"traces_exporter" env:"OTEL_TRACES_EXPORTER" flag:"traces-exporter" default:"none"`
+	MetricsExporter string   `yaml:"metrics_exporter" env:"OTEL_METRICS_EXPORTER" flag:"metrics-exporter" default:"none"`
+	Protocol        string   `yaml:"protocol" env:"OTEL_EXPORTER_OTLP_PROTOCOL" flag:"protocol" default:"grpc"`
+	Endpoint        string   `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" flag:"endpoint"`
+	Insecure        bool     `yaml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE" flag:"insecure" default:"false"`
+	Headers         string   `yaml:"headers" env:"OTEL_EXPORTER_OTLP_HEADERS" flag:"headers" secret:"true"`
+	MetricInterval  Duration `yaml:"metric_interval" env:"OTEL_METRIC_INTERVAL" flag:"metric-interval" default:"60s"`
+	SampleRatio     float64  `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG" flag:"sample-ratio" default:"1"`
+	ServiceName     string   `yaml:"service_name" env:"OTEL_SERVICE_NAME" flag:"service-name" default:"perspex-backend"`
+	ServiceVersion  string   `yaml:"service_version" env:"OTEL_SERVICE_VERSION" flag:"service-version"`
+	PodName         string   `yaml:"pod_name" env:"POD_NAME" flag:"pod-name"`
+	PodNamespace    string   `yaml:"pod_namespace" env:"POD_NAMESPACE" flag:"pod-namespace"`
+	NodeName        string   `yaml:"node_name" env:"NODE_NAME" flag:"node-name"`
+}
+
+// Accepted values of the TelemetryConfig exporters and protocol.
+var (
+	TracesExporters  = []string{"none", "console", "otlp"}
+	MetricsExporters = []string{"none", "otlp"}
+	OTLPProtocols    = []string{"grpc", "http/protobuf"}
+)
+
+// GetHeaders parses the `key=value` pairs of Headers.
+func (t TelemetryConfig) GetHeaders() (map[string]string, error) {
+	headers := map[string]string{}
+
+	for _, pair := range strings.Split(t.Headers, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("header %q must look like `key=value`", pair)
+		}
+
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return headers, nil
+}
+
+// BackendConfig defines the configuration for the server. Its fields, and those of the structs it holds, are loaded
+// from, in increasing order of precedence:
+//
+//   - `default`, the value used when no other source sets one
+//   - `yaml`, the key in a YAML or TOML config file, nested under the keys of the enclosing structs
+//   - `env`, comma separated environment variables, prefixed by the `env` of the enclosing structs
+//   - `flag`, a command line flag, prefixed by the `flag` of the enclosing structs
+//
+// Fields tagged `secret:"true"` are hidden by Redacted.
 type BackendConfig struct {
-	Host     string
-	HttpPort string
-	GrpcPort string
-	Log      LogConfig
-	WriterPG PostgresConfig
-	ReaderPG PostgresConfig
-	Redis    RedisConfig
+	Host        string          `yaml:"host" env:"BACKEND_HOST" flag:"host" default:"0.0.0.0"`
+	HttpPort    string          `yaml:"http_port" env:"BACKEND_HTTP_PORT" flag:"http-port" default:"8000"`
+	GrpcPort    string          `yaml:"grpc_port" env:"BACKEND_GRPC_PORT" flag:"grpc-port" default:"8000"`
+	Storage     string          `yaml:"storage" env:"BACKEND_STORAGE" flag:"storage" default:"postgres"`
+	AutoMigrate bool            `yaml:"auto_migrate" env:"BACKEND_AUTO_MIGRATE" flag:"auto-migrate" default:"false"`
+	HTTP        HTTPConfig      `yaml:"http" env:"BACKEND_HTTP_" flag:"http-"`
+	Log         LogConfig       `yaml:"log" env:"BACKEND_" flag:"log-"`
+	WriterPG    PostgresConfig  `yaml:"writer_pg" env:"WRITER_POSTGRES_" flag:"writer-pg-"`
+	ReaderPG    PostgresConfig  `yaml:"reader_pg" env:"READER_POSTGRES_" flag:"reader-pg-"`
+	Redis       RedisConfig     `yaml:"redis" env:"REDIS_" flag:"redis-"`
+	Telemetry   TelemetryConfig `yaml:"telemetry" flag:"otel-"`
+	Admin       AdminConfig     `yaml:"admin" env:"BACKEND_ADMIN_" flag:"admin-"`
+	Redact      RedactConfig    `yaml:"redact" env:"BACKEND_REDACT_" flag:"redact-"`
+	CORS        CORSConfig      `yaml:"cors" env:"BACKEND_CORS_" flag:"cors-"`
+	Authz       AuthzConfig     `yaml:"authz" env:"BACKEND_AUTHZ_" flag:"authz-"`
+	Outbox      OutboxConfig    `yaml:"outbox" env:"BACKEND_OUTBOX_" flag:"outbox-"`
+	Webhooks    WebhooksConfig  `yaml:"webhooks" env:"BACKEND_WEBHOOKS_" flag:"webhooks-"`
+	Watch       WatchConfig     `yaml:"watch" env:"BACKEND_WATCH_" flag:"watch-"`
+}
+
+// ValidationError lists every problem found while loading a config, so they can all be fixed at once.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
 }
 
-// New return all constants using in Project
+func (e *ValidationError) add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+func (e *ValidationError) err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// New loads the config from defaults, the file named by BACKEND_CONFIG_FILE and the environment.
 func New() (BackendConfig, error) {
-	log := LogConfig{
-		Verbose: utils.MustGetBool("BACKEND_LOGMODE", "false"),
-	}
-
-	writerPG := PostgresConfig{
-		Name:         utils.MustGet("WRITER_POSTGRES_DB", "perspex"),
-		User:         utils.MustGet("WRITER_POSTGRES_USER", "perspex"),
-		Password:     utils.MustGet("WRITER_POSTGRES_PASSWORD", "pass"),
-		Host:         utils.MustGet("WRITER_POSTGRES_HOST", "postgresql.perspex.svc.cluster.local"),
-		Port:         utils.MustGet("WRITER_POSTGRES_PORT", "5432"),
-		Schema:       utils.MustGet("WRITER_POSTGRES_SCHEMA", "public"),
-		MaxOpenConns: utils.MustGetInt("WRITER_POSTGRES_MAXOPENCONNECTIONS", "100"),
-		MaxIdleConns: utils.MustGetInt("WRITER_POSTGRES_MAXOPENCONNECTIONS", "50"),
-		MaxLifespan:  utils.MustGetInt("WRITER_POSTGRES_CONNECTION_LIFESPAN", "128"),
-		Debug:        utils.MustGetBool("WRITER_POSTGRES_DEBUG", "false"),
-	}
-
-	readerPG := PostgresConfig{
-		Name:         utils.MustGet("READER_POSTGRES_DB", "perspex"),
-		User:         utils.MustGet("READER_POSTGRES_USER", "perspex"),
-		Password:     utils.MustGet("READER_POSTGRES_PASSWORD", "pass"),
-		Host:         utils.MustGet("READER_POSTGRES_HOST", "postgresql.perspex.svc.cluster.local"),
-		Port:         utils.MustGet("READER_POSTGRES_PORT", "5432"),
-		Schema:       utils.MustGet("READER_POSTGRES_SCHEMA", "public"),
-		MaxOpenConns: utils.MustGetInt("READER_POSTGRES_MAXOPENCONNECTIONS", "100"),
-		MaxIdleConns: utils.MustGetInt("READER_POSTGRES_MAXOPENCONNECTIONS", "50"),
-		MaxLifespan:  utils.MustGetInt("READER_POSTGRES_CONNECTION_LIFESPAN", "128"),
-		Debug:        utils.MustGetBool("READER_POSTGRES_DEBUG", "false"),
-	}
-
-	redis := RedisConfig{
-		Host:               utils.MustGet("REDIS_HOST", "redis-writer.perspex"),
-		Password:           utils.MustGet("REDIS_PASSWORD", "pass"),
-		Port:               utils.MustGet("REDIS_PORT", "6369"),
-		DB:                 utils.MustGetInt("REDIS_DB", "0"),
-		InsecureSkipVerify: utils.MustGetBool("REDIS_SKIP_VERIFY", "false"),
-		TLS:                utils.MustGetBool("REDIS_TLS", "false"),
-	}
-
-	return BackendConfig{
-		Host:     utils.MustGet("BACKEND_HOST", "0.0.0.0"),
-		HttpPort: utils.MustGet("BACKEND_HTTP_PORT", "8000"),
-		GrpcPort: utils.MustGet("BACKEND_GRPC_PORT", "8000"),
-		Log:      log,
-		WriterPG: writerPG,
-		ReaderPG: readerPG,
-		Redis:    redis,
-	}, nil
+	cfg, _, err := Load(nil)
+	return cfg, err
+}
+
+// Defaults returns the config holding the defaults alone, ignoring the config file and the environment, for callers
+// such as tests that must not pick up the settings of the process. It is not validated, so callers may override
+// settings before calling Validate.
+func Defaults() (BackendConfig, error) {
+	var cfg BackendConfig
+	problems := &ValidationError{}
+
+	for _, f := range walk(reflect.ValueOf(&cfg).Elem(), "", "", "") {
+		if err := f.set(f.def); err != nil {
+			problems.add("%s: invalid default %q: %s", f.path, f.def, err)
+		}
+	}
+
+	return cfg, problems.err()
+}
+
+// Load loads the config from defaults, a config file, the environment and the flags in args, returning the
+// arguments left after the flags.
+func Load(args []string) (BackendConfig, []string, error) {
+	return LoadFlags(flag.NewFlagSet("backend", flag.ContinueOnError), args)
+}
+
+// LoadFlags is Load, parsing args with fs so callers may register flags of their own.
+func LoadFlags(fs *flag.FlagSet, args []string) (BackendConfig, []string, error) {
+	var cfg BackendConfig
+	problems := &ValidationError{}
+
+	fields := walk(reflect.ValueOf(&cfg).Elem(), "", "", "")
+
+	file := fs.String("config", os.Getenv(FileEnv), fmt.Sprintf("YAML or TOML config `file` (env %s)", FileEnv))
+
+	// Flags are recorded as they are parsed and applied last, so they take precedence over every other source.
+	type setting struct {
+		field *field
+		value string
+	}
+
+	var flags []setting
+
+	for _, f := range fields {
+		f := f
+		fs.Var(&flagValue{
+			bool: f.value.Kind() == reflect.Bool,
+			set: func(s string) error {
+				flags = append(flags, setting{f, s})
+				return nil
+			},
+		}, f.flag, f.usage())
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	for _, f := range fields {
+		if err := f.set(f.def); err != nil {
+			problems.add("%s: invalid default %q: %s", f.path, f.def, err)
+		}
+	}
+
+	if *file != "" {
+		values, err := readFile(*file)
+		if err != nil {
+			problems.add("%s: %s", *file, err)
+		}
+
+		byPath := make(map[string]*field, len(fields))
+		for _, f := range fields {
+			byPath[f.path] = f
+		}
+
+		for _, path := range sortedKeys(values) {
+			f, ok := byPath[path]
+			if !ok {
+				problems.add("%s: unknown key %s", *file, path)
+				continue
+			}
+
+			if err := f.set(values[path]); err != nil {
+				problems.add("%s: %s: %s", *file, path, err)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		for _, env := range f.env {
+			v, ok := os.LookupEnv(env)
+			if !ok {
+				continue
+			}
+
+			if err := f.set(v); err != nil {
+				problems.add("%s: %s", env, err)
+			}
+
+			break
+		}
+	}
+
+	for _, s := range flags {
+		if err := s.field.set(s.value); err != nil {
+			problems.add("--%s: %s", s.field.flag, err)
+		}
+	}
+
+	var invalid *ValidationError
+	if errors.As(cfg.Validate(), &invalid) {
+		problems.Problems = append(problems.Problems, invalid.Problems...)
+	}
+
+	return cfg, fs.Args(), problems.err()
+}
+
+// Validate reports every setting that is out of range or inconsistent with another.
+func (c BackendConfig) Validate() error {
+	problems := &ValidationError{}
+
+	if c.Host == "" {
+		problems.add("host: must be set")
+	}
+
+	validPort(problems, "http_port", c.HttpPort)
+	validPort(problems, "grpc_port", c.GrpcPort)
+
+	switch c.Storage {
+	case StoragePostgres:
+		c.WriterPG.validate(problems, "writer_pg")
+		c.ReaderPG.validate(problems, "reader_pg")
+	case StorageMemory:
+	default:
+		problems.add("storage: must be %q or %q, got %q", StoragePostgres, StorageMemory, c.Storage)
+	}
+
+	for path, d := range map[string]Duration{
+		"http.read_timeout":     c.HTTP.ReadTimeout,
+		"http.write_timeout":    c.HTTP.WriteTimeout,
+		"http.idle_timeout":     c.HTTP.IdleTimeout,
+		"http.shutdown_timeout": c.HTTP.ShutdownTimeout,
+	} {
+		if d <= 0 {
+			problems.add("%s: must be positive, got %s", path, d)
+		}
+	}
+
+	if c.HTTP.MaxHeaderBytes <= 0 {
+		problems.add("http.max_header_bytes: must be positive, got %s", c.HTTP.MaxHeaderBytes)
+	}
+
+	if c.Redis.Host != "" {
+		validPort(problems, "redis.port", c.Redis.Port)
+	}
+
+	validPasswordRef(problems, "redis", c.Redis.PasswordFile, c.Redis.PasswordSecret)
+
+	if c.Outbox.Sink == "redis" && c.Redis.Host == "" {
+		problems.add("redis.host: must be set for the redis outbox sink")
+	}
+
+	if c.Redis.DB < 0 {
+		problems.add("redis.db: must not be negative, got %d", c.Redis.DB)
+	}
+
+	c.Telemetry.validate(problems, "telemetry")
+	c.CORS.validate(problems, "cors")
+
+	if !contains(AuthzModes, c.Authz.Mode) {
+		problems.add("authz.mode: must be one of %s, got %q", strings.Join(AuthzModes, ", "), c.Authz.Mode)
+	}
+
+	if _, err := os.Stat(c.Authz.PolicyFile); c.Authz.PolicyFile != "" && err != nil {
+		problems.add("authz.policy_file: %s", err)
+	}
+
+	for _, v := range split(c.Authz.Admins) {
+		if id, err := strconv.ParseInt(v, 10, 64); err != nil || id <= 0 {
+			problems.add("authz.admins: must list user IDs, got %q", v)
+		}
+	}
+
+	c.Outbox.validate(problems, "outbox")
+	c.Webhooks.validate(problems, "webhooks")
+	c.Watch.validate(problems, "watch")
+
+	if !contains(RedactModes, c.Redact.Mode) {
+		problems.add("redact.mode: must be one of %s, got %q", strings.Join(RedactModes, ", "), c.Redact.Mode)
+	}
+
+	// Without a key the hashes of guessable values, such as emails, could be reversed by hashing candidates.
+	if c.Redact.Mode == "hash" && c.Redact.Key == "" {
+		problems.add("redact.key: must be set to hash")
+	}
+
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		problems.add("log.level: %s", err)
+	}
+
+	if _, err := c.Log.GetLevels(); err != nil {
+		problems.add("log.levels: %s", err)
+	}
+
+	if c.Log.SampleInitial < 0 || c.Log.SampleThereafter < 0 {
+		problems.add("log: sampling must not be negative, got %d and %d", c.Log.SampleInitial, c.Log.SampleThereafter)
+	}
+
+	sort.Strings(problems.Problems)
+
+	return problems.err()
+}
+
+func (d PostgresConfig) validate(problems *ValidationError, path string) {
+	for key, v := range map[string]string{"name": d.Name, "user": d.User, "host": d.Host, "schema": d.Schema} {
+		if v == "" {
+			problems.add("%s.%s: must be set", path, key)
+		}
+	}
+
+	before := len(problems.Problems)
+
+	hosts, ports := strings.Split(d.Host, ","), strings.Split(d.Port, ",")
+	for _, port := range ports {
+		validPort(problems, path+".port", strings.TrimSpace(port))
+	}
+
+	if len(ports) != 1 && len(ports) != len(hosts) {
+		problems.add("%s.port: must list one port, or one per host (%d), got %d", path, len(hosts), len(ports))
+	}
+
+	validPasswordRef(problems, path, d.PasswordFile, d.PasswordSecret)
+
+	if !contains(SSLModes, d.SSLMode) {
+		problems.add("%s.sslmode: must be one of %s, got %q", path, strings.Join(SSLModes, ", "), d.SSLMode)
+	}
+
+	if !contains(TargetSessionAttrs, d.TargetSessionAttrs) {
+		problems.add("%s.target_session_attrs: must be one of %s, got %q", path, strings.Join(TargetSessionAttrs, ", "), d.TargetSessionAttrs)
+	}
+
+	if (d.SSLCert == "") != (d.SSLKey == "") {
+		problems.add("%s: sslcert and sslkey must be set together", path)
+	}
+
+	for key, file := range map[string]string{"sslrootcert": d.SSLRootCert, "sslcert": d.SSLCert, "sslkey": d.SSLKey} {
+		if _, err := os.Stat(file); file != "" && err != nil {
+			problems.add("%s.%s: %s", path, key, err)
+		}
+	}
+
+	if d.ConnectTimeout < 0 {
+		problems.add("%s.connect_timeout: must not be negative, got %s", path, d.ConnectTimeout)
+	}
+
+	if d.StatementTimeout < 0 {
+		problems.add("%s.statement_timeout: must not be negative, got %s", path, d.StatementTimeout)
+	}
+
+	// Let the driver check what is left, eg: that the certificates parse, once the settings are known to be sound.
+	if len(problems.Problems) == before {
+		if _, err := pgconn.ParseConfig(d.GetDataSourceName()); err != nil {
+			problems.add("%s: %s", path, err)
+		}
+	}
+
+	if d.MaxOpenConns < 0 {
+		problems.add("%s.max_open_conns: must not be negative, got %d", path, d.MaxOpenConns)
+	}
+
+	if d.MaxOpenConns > math.MaxInt32 {
+		problems.add("%s.max_open_conns: must not exceed %d, got %d", path, math.MaxInt32, d.MaxOpenConns)
+	}
+
+	if d.MinConns < 0 {
+		problems.add("%s.min_conns: must not be negative, got %d", path, d.MinConns)
+	}
+
+	// 0 leaves the pool's size to the driver, so only a set maximum bounds the minimum.
+	if d.MaxOpenConns > 0 && d.MinConns > d.MaxOpenConns {
+		problems.add("%s.min_conns: must not exceed max_open_conns (%d), got %d", path, d.MaxOpenConns, d.MinConns)
+	}
+
+	if d.MaxIdleTime < 0 {
+		problems.add("%s.max_idle_time: must not be negative, got %s", path, d.MaxIdleTime)
+	}
+
+	if d.MaxLifespan < 0 {
+		problems.add("%s.max_lifespan: must not be negative, got %s", path, d.MaxLifespan)
+	}
+}
+
+func validPasswordRef(problems *ValidationError, path, file, secret string) {
+	if file != "" && secret != "" {
+		problems.add("%s: set at most one of password_file and password_secret", path)
+	}
+
+	if _, name, ok := strings.Cut(secret, "://"); secret != "" && (!ok || name == "") {
+		problems.add("%s.password_secret: must look like <scheme>://<name>, got %q", path, secret)
+	}
+}
+
+func (t TelemetryConfig) validate(problems *ValidationError, path string) {
+	if !contains(TracesExporters, t.TracesExporter) {
+		problems.add("%s.traces_exporter: must be one of %s, got %q", path, strings.Join(TracesExporters, ", "), t.TracesExporter)
+	}
+
+	if !contains(MetricsExporters, t.MetricsExporter) {
+		problems.add("%s.metrics_exporter: must be one of %s, got %q", path, strings.Join(MetricsExporters, ", "), t.MetricsExporter)
+	}
+
+	if !contains(OTLPProtocols, t.Protocol) {
+		problems.add("%s.protocol: must be one of %s, got %q", path, strings.Join(OTLPProtocols, ", "), t.Protocol)
+	}
+
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		problems.add("%s.sample_ratio: must be between 0 and 1, got %g", path, t.SampleRatio)
+	}
+
+	if t.MetricInterval <= 0 {
+		problems.add("%s.metric_interval: must be positive, got %s", path, t.MetricInterval)
+	}
+
+	if t.ServiceName == "" {
+		problems.add("%s.service_name: must be set", path)
+	}
+
+	if _, err := t.GetHeaders(); err != nil {
+		problems.add("%s.headers: %s", path, err)
+	}
+}
+
+func (c CORSConfig) validate(problems *ValidationError, path string) {
+	if !contains(CORSProfiles, c.Profile) {
+		problems.add("%s.profile: must be one of %s, got %q", path, strings.Join(CORSProfiles, ", "), c.Profile)
+	}
+
+	for _, origin := range split(c.AllowedOrigins) {
+		scheme, host, ok := strings.Cut(origin, "://")
+
+		switch {
+		case origin == "*":
+			problems.add("%s.allowed_origins: must list origins, `*` would allow every one", path)
+		case !ok || (scheme != "http" && scheme != "https") || host == "":
+			problems.add("%s.allowed_origins: %q must look like http(s)://host[:port]", path, origin)
+		case strings.Count(origin, "*") > 1:
+			problems.add("%s.allowed_origins: %q must have at most one `*`", path, origin)
+		case strings.Contains(host, "/"):
+			problems.add("%s.allowed_origins: %q must not have a path", path, origin)
+		}
+	}
+
+	if len(c.GetAllowedMethods()) == 0 {
+		problems.add("%s.allowed_methods: must be set", path)
+	}
+
+	if c.MaxAge < 0 {
+		problems.add("%s.max_age: must not be negative, got %s", path, c.MaxAge)
+	}
+}
+
+func (o OutboxConfig) validate(problems *ValidationError, path string) {
+	if !contains(OutboxSinks, o.Sink) {
+		problems.add("%s.sink: must be one of %s, got %q", path, strings.Join(OutboxSinks, ", "), o.Sink)
+	}
+
+	if o.Interval <= 0 {
+		problems.add("%s.interval: must be positive, got %s", path, o.Interval)
+	}
+
+	if o.BatchSize <= 0 {
+		problems.add("%s.batch_size: must be positive, got %d", path, o.BatchSize)
+	}
+
+	if o.Retention <= 0 {
+		problems.add("%s.retention: must be positive, got %s", path, o.Retention)
+	}
+
+	switch {
+	case o.Sink == "redis" && o.Stream == "":
+		problems.add("%s.stream: must be set for the redis sink", path)
+	case o.Sink == "webhook" && o.WebhookURL == "":
+		problems.add("%s.webhook_url: must be set for the webhook sink", path)
+	}
+
+	if u, err := url.Parse(o.WebhookURL); o.WebhookURL != "" && (err != nil || u.Scheme != "http" && u.Scheme != "https") {
+		problems.add("%s.webhook_url: must be an http or https URL, got %q", path, o.WebhookURL)
+	}
+}
+
+func validPort(problems *ValidationError, path, port string) {
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		problems.add("%s: must be a port between 1 and 65535, got %q", path, port)
+	}
+}
+
+// Redacted returns a copy of the config with every secret replaced, so it can be printed or logged.
+func (c BackendConfig) Redacted() BackendConfig {
+	for _, f := range walk(reflect.ValueOf(&c).Elem(), "", "", "") {
+		if f.secret && f.value.String() != "" {
+			f.value.SetString(Redacted)
+		}
+	}
+
+	return c
+}
+
+// YAML renders the config in the format read from config files.
+func (c BackendConfig) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
 }
 
 // GetDebug returns true if debug information should be outputted to the DebugWriter handler.
@@ -104,16 +801,68 @@ func (d PostgresConfig) GetDebug() bool {
 	return d.Debug
 }
 
-// GetDataSourceName constructs a postgres DSN.
+// GetDataSourceName constructs a postgres DSN, in libpq's `key='value'` format. Unset options are left out.
 func (d PostgresConfig) GetDataSourceName() string {
-	return fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		d.Host,
-		d.Port,
-		d.User,
-		d.Password,
-		d.Name,
-	)
+	params := [][2]string{
+		{"host", d.Host},
+		{"port", d.Port},
+		{"user", d.User},
+		{"password", d.Password},
+		{"dbname", d.Name},
+		{"sslmode", d.SSLMode},
+		{"sslrootcert", d.SSLRootCert},
+		{"sslcert", d.SSLCert},
+		{"sslkey", d.SSLKey},
+		{"application_name", d.ApplicationName},
+		{"target_session_attrs", d.TargetSessionAttrs},
+		{"search_path", d.Schema},
+		{"timezone", d.TimeZone},
+	}
+
+	// connect_timeout is in whole seconds, so round up rather than disable a sub-second timeout.
+	if d.ConnectTimeout > 0 {
+		seconds := (time.Duration(d.ConnectTimeout) + time.Second - 1) / time.Second
+		params = append(params, [2]string{"connect_timeout", strconv.FormatInt(int64(seconds), 10)})
+	}
+
+	if d.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(time.Duration(d.StatementTimeout).Milliseconds(), 10)})
+	}
+
+	var dsn []string
+
+	for _, p := range params {
+		if p[1] == "" {
+			continue
+		}
+
+		dsn = append(dsn, fmt.Sprintf("%s='%s'", p[0], dsnEscaper.Replace(p[1])))
+	}
+
+	return strings.Join(dsn, " ")
+}
+
+var dsnEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// PasswordRef returns the secret reference the password is read from, or "" to use Password.
+func (d PostgresConfig) PasswordRef() string {
+	return passwordRef(d.PasswordFile, d.PasswordSecret)
+}
+
+// PasswordRef returns the secret reference the password is read from, or "" to use Password.
+func (r RedisConfig) PasswordRef() string {
+	return passwordRef(r.PasswordFile, r.PasswordSecret)
+}
+
+func passwordRef(file, secret string) string {
+	switch {
+	case secret != "":
+		return secret
+	case file != "":
+		return "file://" + file
+	default:
+		return ""
+	}
 }
 
 // GetMaxOpenConns gets the maximum number of open connections to the database.
@@ -121,12 +870,305 @@ func (d PostgresConfig) GetMaxOpenConns() int {
 	return d.MaxOpenConns
 }
 
-// GetMaxIdleConns gets the maximum number of connections in the idle connection pool.
-func (d PostgresConfig) GetMaxIdleConns() int {
-	return d.MaxIdleConns
+// GetMinConns gets the number of connections the pool keeps open, even when idle.
+func (d PostgresConfig) GetMinConns() int {
+	return d.MinConns
+}
+
+// GetMaxIdleTime gets the time after which an idle connection is closed.
+func (d PostgresConfig) GetMaxIdleTime() time.Duration {
+	return time.Duration(d.MaxIdleTime)
 }
 
 // GetConnMaxLifetime gets the maximum amount of time a connection may be reused.
 func (d PostgresConfig) GetConnMaxLifetime() time.Duration {
-	return time.Duration(d.MaxLifespan) * time.Second
+	return time.Duration(d.MaxLifespan)
+}
+
+// Duration is a time.Duration read as a Go duration string, eg: `90s` or `5m`. A bare number is read as seconds,
+// matching the settings that predate typed durations.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = Duration(time.Duration(n) * time.Second)
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected eg: 30s or 5m", s)
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// ByteSize is a number of bytes read with an optional unit, eg: `512`, `8KiB` or `10MB`. Units ending in `iB` are
+// powers of 1024, the others powers of 1000.
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"GB", 1e9},
+	{"MB", 1e6},
+	{"KB", 1e3},
+	{"B", 1},
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	unit := ByteSize(1)
+
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, unit = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size %q, expected eg: 512, 8KiB or 10MB", string(text))
+	}
+
+	*b = ByteSize(n) * unit
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// String renders the size in the largest binary unit that divides it exactly.
+func (b ByteSize) String() string {
+	for _, u := range byteUnits[:3] {
+		if b != 0 && b%u.size == 0 {
+			return fmt.Sprintf("%d%s", b/u.size, u.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%dB", int64(b))
+}
+
+// field is a settable leaf of the config, with the names it is loaded from.
+type field struct {
+	path   string
+	env    []string
+	flag   string
+	def    string
+	secret bool
+	value  reflect.Value
+}
+
+// walk lists the leaves of v, joining the names of enclosing structs onto those of their fields.
+func walk(v reflect.Value, path, env, flagPrefix string) []*field {
+	var fields []*field
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+
+		key := path + sf.Tag.Get("yaml")
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			fields = append(fields, walk(fv, key+".", env+sf.Tag.Get("env"), flagPrefix+sf.Tag.Get("flag"))...)
+			continue
+		}
+
+		f := &field{
+			path:   key,
+			flag:   flagPrefix + sf.Tag.Get("flag"),
+			def:    sf.Tag.Get("default"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  fv,
+		}
+
+		for _, name := range strings.Split(sf.Tag.Get("env"), ",") {
+			f.env = append(f.env, env+name)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+func (f *field) set(s string) error {
+	if u, ok := f.value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+
+		f.value.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+
+		f.value.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return nil
+}
+
+func (f *field) usage() string {
+	usage := "env " + strings.Join(f.env, ", ")
+	if f.secret {
+		return usage
+	}
+
+	return fmt.Sprintf("%s, default %q", usage, f.def)
+}
+
+// flagValue is a flag.Value which hands its value to set, treating the flag as a boolean switch if bool is set.
+type flagValue struct {
+	set  func(string) error
+	bool bool
+}
+
+func (v *flagValue) String() string     { return "" }
+func (v *flagValue) Set(s string) error { return v.set(s) }
+func (v *flagValue) IsBoolFlag() bool   { return v.bool }
+
+// readFile reads a YAML or TOML config file into values keyed by dotted path, eg: `writer_pg.host`.
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	flatten(values, "", raw)
+
+	return values, nil
+}
+
+func flatten(values map[string]string, prefix string, raw map[string]interface{}) {
+	for k, v := range raw {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flatten(values, prefix+k+".", v)
+		case nil:
+		default:
+			values[prefix+k] = fmt.Sprint(v)
+		}
+	}
+}
+
+// split lists the comma separated values in s, without blanks.
+func split(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (w WebhooksConfig) validate(problems *ValidationError, path string) {
+	if w.Interval <= 0 {
+		problems.add("%s.interval: must be positive, got %s", path, w.Interval)
+	}
+
+	if w.BatchSize <= 0 {
+		problems.add("%s.batch_size: must be positive, got %d", path, w.BatchSize)
+	}
+
+	if w.Timeout <= 0 {
+		problems.add("%s.timeout: must be positive, got %s", path, w.Timeout)
+	}
+
+	if w.MaxAttempts <= 0 {
+		problems.add("%s.max_attempts: must be positive, got %d", path, w.MaxAttempts)
+	}
+
+	if w.DisableAfter <= 0 {
+		problems.add("%s.disable_after: must be positive, got %d", path, w.DisableAfter)
+	}
+}
+
+func (w WatchConfig) validate(problems *ValidationError, path string) {
+	if w.Heartbeat <= 0 {
+		problems.add("%s.heartbeat: must be positive, got %s", path, w.Heartbeat)
+	}
+
+	if w.Retention <= 0 {
+		problems.add("%s.retention: must be positive, got %s", path, w.Retention)
+	}
 }
@@ -0,0 +1,315 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+)
+
+// write writes a config file named name to a temporary directory, returning its path.
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+
+	return path
+}
+
+// problems returns the problems of a ValidationError, failing the test for any other error.
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+
+	var invalid *config.ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+
+	return invalid.Problems
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := write(t, "backend.yaml", "writer_pg:\n  host: file\n  port: \"5434\"\n")
+
+	cases := map[string]struct {
+		args []string
+		env  map[string]string
+		host string
+		port string
+	}{
+		"Defaults": {
+			host: "postgresql.perspex.svc.cluster.local",
+			port: "5432",
+		},
+		"File": {
+			args: []string{"--config", file},
+			host: "file",
+			port: "5434",
+		},
+		"FileFromEnv": {
+			env:  map[string]string{config.FileEnv: file},
+			host: "file",
+			port: "5434",
+		},
+		"EnvOverFile": {
+			args: []string{"--config", file},
+			env:  map[string]string{"WRITER_POSTGRES_HOST": "env"},
+			host: "env",
+			port: "5434",
+		},
+		"FlagOverEnv": {
+			args: []string{"--config", file, "--writer-pg-host", "flag"},
+			env:  map[string]string{"WRITER_POSTGRES_HOST": "env"},
+			host: "flag",
+			port: "5434",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(config.FileEnv, "")
+
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, _, err := config.Load(c.args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if cfg.WriterPG.Host != c.host || cfg.WriterPG.Port != c.port {
+				t.Fatalf("expected %s:%s, got %s:%s", c.host, c.port, cfg.WriterPG.Host, cfg.WriterPG.Port)
+			}
+		})
+	}
+}
+
+func TestLoadLeavesArgs(t *testing.T) {
+	t.Setenv(config.FileEnv, "")
+
+	_, args, err := config.Load([]string{"--log-verbose", "migrate", "up"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if strings.Join(args, " ") != "migrate up" {
+		t.Fatalf("expected the arguments after the flags, got %v", args)
+	}
+}
+
+func TestLoadFormats(t *testing.T) {
+	files := map[string]string{
+		"backend.yaml": `
+http:
+  read_timeout: 30s
+  max_header_bytes: 16KiB
+log:
+  verbose: true
+redis:
+  db: 3
+telemetry:
+  sample_ratio: 0.5
+`,
+		"backend.toml": `
+[http]
+read_timeout = "30s"
+max_header_bytes = "16KiB"
+
+[log]
+verbose = true
+
+[redis]
+db = 3
+
+[telemetry]
+sample_ratio = 0.5
+`,
+	}
+
+	for name, content := range files {
+		name, content := name, content
+
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(config.FileEnv, write(t, name, content))
+
+			cfg, err := config.New()
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			if time.Duration(cfg.HTTP.ReadTimeout) != 30*time.Second || cfg.HTTP.MaxHeaderBytes != 16<<10 {
+				t.Fatalf("expected the http settings, got %+v", cfg.HTTP)
+			}
+
+			if !cfg.Log.Verbose || cfg.Redis.DB != 3 || cfg.Telemetry.SampleRatio != 0.5 {
+				t.Fatalf("expected the log, redis and telemetry settings, got %+v, %+v and %+v", cfg.Log, cfg.Redis, cfg.Telemetry)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsFiles(t *testing.T) {
+	cases := map[string]struct {
+		name    string
+		content string
+		problem string
+	}{
+		"UnknownKey":       {name: "backend.yaml", content: "writer_pg:\n  hots: db\n", problem: "unknown key writer_pg.hots"},
+		"UnknownExtension": {name: "backend.json", content: "{}", problem: `unsupported config file extension ".json"`},
+		"Malformed":        {name: "backend.toml", content: "[http", problem: "backend.toml"},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(config.FileEnv, write(t, c.name, c.content))
+
+			_, err := config.New()
+			if found := problems(t, err); !strings.Contains(strings.Join(found, "\n"), c.problem) {
+				t.Fatalf("expected a problem mentioning %q, got %v", c.problem, found)
+			}
+		})
+	}
+}
+
+func TestLoadAggregatesProblems(t *testing.T) {
+	t.Setenv(config.FileEnv, write(t, "backend.yaml", "storage: floppy\n"))
+	t.Setenv("BACKEND_HTTP_PORT", "0")
+	t.Setenv("BACKEND_HTTP_READ_TIMEOUT", "soon")
+
+	_, _, err := config.Load([]string{"--redis-db", "-1", "--authz-mode", "lenient"})
+
+	found := problems(t, err)
+
+	want := []string{
+		`BACKEND_HTTP_READ_TIMEOUT: invalid duration "soon"`,
+		`http_port: must be a port between 1 and 65535, got "0"`,
+		`storage: must be "postgres" or "memory", got "floppy"`,
+		`redis.db: must not be negative, got -1`,
+		`authz.mode: must be one of enforce, audit, off, got "lenient"`,
+	}
+
+	for _, w := range want {
+		if !strings.Contains(strings.Join(found, "\n"), w) {
+			t.Fatalf("expected a problem mentioning %q, got:\n%s", w, strings.Join(found, "\n"))
+		}
+	}
+
+	if !strings.Contains(err.Error(), "invalid config:\n  - ") {
+		t.Fatalf("expected the problems to be listed, got %q", err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"90s":  90 * time.Second,
+		"5m":   5 * time.Minute,
+		" 30 ": 30 * time.Second,
+		"1h2m": time.Hour + 2*time.Minute,
+	}
+
+	for s, want := range cases {
+		var d config.Duration
+		if err := d.UnmarshalText([]byte(s)); err != nil || time.Duration(d) != want {
+			t.Fatalf("%q: expected %s, got %s (%v)", s, want, d, err)
+		}
+	}
+
+	for _, s := range []string{"", "soon", "5 minutes", "1.5"} {
+		var d config.Duration
+		if err := d.UnmarshalText([]byte(s)); err == nil {
+			t.Fatalf("%q: expected an error, got %s", s, d)
+		}
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	cases := map[string]config.ByteSize{
+		"512":    512,
+		"512B":   512,
+		"8KiB":   8 << 10,
+		"8 KiB":  8 << 10,
+		"10MB":   10e6,
+		"2GiB":   2 << 30,
+		"1KB":    1000,
+		"16MiB ": 16 << 20,
+	}
+
+	for s, want := range cases {
+		var b config.ByteSize
+		if err := b.UnmarshalText([]byte(s)); err != nil || b != want {
+			t.Fatalf("%q: expected %d, got %d (%v)", s, want, b, err)
+		}
+	}
+
+	for _, s := range []string{"", "KiB", "8XB", "eight", "1.5MiB"} {
+		var b config.ByteSize
+		if err := b.UnmarshalText([]byte(s)); err == nil {
+			t.Fatalf("%q: expected an error, got %d", s, b)
+		}
+	}
+
+	for size, want := range map[config.ByteSize]string{8 << 10: "8KiB", 3 << 30: "3GiB", 1000: "1000B", 0: "0B"} {
+		if got := size.String(); got != want {
+			t.Fatalf("%d: expected %s, got %s", size, want, got)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg, err := config.Defaults()
+	if err != nil {
+		t.Fatalf("Defaults: %v", err)
+	}
+
+	cfg.WriterPG.Password = "writer-hunter2"
+	cfg.ReaderPG.Password = "reader-hunter2"
+	cfg.Redis.Password = "redis-hunter2"
+	cfg.Admin.Token = "admin-hunter2"
+	cfg.Redact.Key = ""
+
+	redacted := cfg.Redacted()
+
+	for name, v := range map[string]string{
+		"writer_pg.password": redacted.WriterPG.Password,
+		"reader_pg.password": redacted.ReaderPG.Password,
+		"redis.password":     redacted.Redis.Password,
+		"admin.token":        redacted.Admin.Token,
+	} {
+		if v != config.Redacted {
+			t.Fatalf("%s: expected to be redacted, got %q", name, v)
+		}
+	}
+
+	if redacted.Redact.Key != "" {
+		t.Fatalf("expected an unset secret to stay unset, got %q", redacted.Redact.Key)
+	}
+
+	if redacted.WriterPG.Host != cfg.WriterPG.Host || redacted.WriterPG.User != cfg.WriterPG.User {
+		t.Fatalf("expected the other settings to be kept, got %+v", redacted.WriterPG)
+	}
+
+	if cfg.WriterPG.Password != "writer-hunter2" {
+		t.Fatalf("expected the config redacted to be left as it was, got %q", cfg.WriterPG.Password)
+	}
+
+	out, err := redacted.YAML()
+	if err != nil {
+		t.Fatalf("YAML: %v", err)
+	}
+
+	if strings.Contains(string(out), "hunter2") {
+		t.Fatalf("expected no secret in the rendered config, got:\n%s", out)
+	}
+}
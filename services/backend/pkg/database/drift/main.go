@@ -0,0 +1,451 @@
+package drift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/strmangle"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/schemas/perspex"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/migrate"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+)
+
+// Column describes a column as the generated models see it. Type is the Go type sqlboiler maps the column to, which
+// also captures its nullability, eg: `string` or `null.String`.
+type Column struct {
+	Name    string
+	Type    string
+	Default bool
+}
+
+// Table describes a table as the generated models see it. sqlboiler records no indexes besides the primary key, so
+// it is the only index compared.
+type Table struct {
+	Name       string
+	Columns    map[string]Column
+	PrimaryKey []string
+}
+
+// Schema is a set of tables, keyed by name.
+type Schema map[string]*Table
+
+// Check applies the embedded migrations to a scratch database on the server cfg points at, and compares the result
+// with the generated models. It returns one line per difference, or none when the models are up to date. The scratch
+// database is dropped afterwards, so the configured user needs the CREATEDB privilege.
+func Check(ctx context.Context, cfg config.PostgresConfig) ([]string, error) {
+	models, err := Models(perspex.Models)
+	if err != nil {
+		return nil, fmt.Errorf("reading models: %w", err)
+	}
+
+	admin, err := postgres.Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer admin.Close()
+
+	scratch := cfg
+	scratch.Name = fmt.Sprintf("perspex_drift_%d", time.Now().UnixNano())
+
+	if _, err := admin.DB.ExecContext(ctx, fmt.Sprintf(`CREATE DATABASE %q`, scratch.Name)); err != nil {
+		return nil, fmt.Errorf("creating scratch database: %w", err)
+	}
+
+	defer func() {
+		if _, err := admin.DB.ExecContext(context.Background(), fmt.Sprintf(`DROP DATABASE IF EXISTS %q WITH (FORCE)`, scratch.Name)); err != nil {
+			otelzap.L().Ctx(ctx).Warn("Dropping Scratch Database Failed:", zap.String("database", scratch.Name), zap.Error(err))
+		}
+	}()
+
+	db, err := postgres.Connect(scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	migrator, err := migrate.Embedded(db.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		return nil, err
+	}
+
+	migrated, err := Introspect(ctx, db.DB, cfg.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("introspecting scratch database: %w", err)
+	}
+
+	return Diff(migrated, models), nil
+}
+
+// Diff lists how the models differ from the migrated schema, in a stable order.
+func Diff(migrated, models Schema) []string {
+	var diffs []string
+
+	for _, name := range union(keys(migrated), keys(models)) {
+		db, model := migrated[name], models[name]
+
+		switch {
+		case model == nil:
+			diffs = append(diffs, fmt.Sprintf("%s: table is in the database but not in the models", name))
+			continue
+		case db == nil:
+			diffs = append(diffs, fmt.Sprintf("%s: table is in the models but not in the database", name))
+			continue
+		}
+
+		for _, col := range union(keys(db.Columns), keys(model.Columns)) {
+			got, inDB := db.Columns[col]
+			want, inModels := model.Columns[col]
+
+			switch {
+			case !inModels:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: column is in the database but not in the models", name, col))
+			case !inDB:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: column is in the models but not in the database", name, col))
+			default:
+				if got.Type != want.Type {
+					diffs = append(diffs, fmt.Sprintf("%s.%s: type is %s in the database but %s in the models", name, col, got.Type, want.Type))
+				}
+
+				if got.Default != want.Default {
+					diffs = append(diffs, fmt.Sprintf("%s.%s: default is %s in the database but %s in the models", name, col, presence(got.Default), presence(want.Default)))
+				}
+			}
+		}
+
+		if !reflect.DeepEqual(db.PrimaryKey, model.PrimaryKey) {
+			diffs = append(diffs, fmt.Sprintf("%s: primary key is (%s) in the database but (%s) in the models", name, strings.Join(db.PrimaryKey, ", "), strings.Join(model.PrimaryKey, ", ")))
+		}
+	}
+
+	return diffs
+}
+
+// Introspect reads the tables in the named schema, mapping column types the way sqlboiler's psql driver does, which
+// also counts nullable columns as defaulting to NULL. The migrations table is skipped, as it is in `sqlboiler.toml`.
+func Introspect(ctx context.Context, db *sql.DB, schema string) (Schema, error) {
+	res := Schema{}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.table_name, c.column_name, c.data_type, c.udt_name, c.is_nullable = 'YES',
+			c.column_default IS NOT NULL OR c.is_identity = 'YES' OR c.is_generated = 'ALWAYS' OR c.is_nullable = 'YES'
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = $1 AND t.table_type = 'BASE TABLE' AND c.table_name <> $2
+		ORDER BY c.table_name, c.ordinal_position`, schema, migrate.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, name, dataType, udt string
+		var nullable, hasDefault bool
+
+		if err := rows.Scan(&table, &name, &dataType, &udt, &nullable, &hasDefault); err != nil {
+			return nil, err
+		}
+
+		res.table(table).Columns[name] = Column{
+			Name:    name,
+			Type:    goType(dataType, udt, nullable),
+			Default: hasDefault,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pks, err := db.QueryContext(ctx, `
+		SELECT cl.relname, a.attname
+		FROM pg_index i
+		JOIN pg_class cl ON cl.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = cl.oid AND a.attnum = k.attnum
+		WHERE i.indisprimary AND n.nspname = $1 AND cl.relname <> $2
+		ORDER BY cl.relname, k.ord`, schema, migrate.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer pks.Close()
+
+	for pks.Next() {
+		var table, column string
+		if err := pks.Scan(&table, &column); err != nil {
+			return nil, err
+		}
+
+		t := res.table(table)
+		t.PrimaryKey = append(t.PrimaryKey, column)
+	}
+
+	return res, pks.Err()
+}
+
+// Models reads table metadata from the generated sqlboiler source in fsys. The unexported column lists, like
+// `userColumnsWithDefault`, carry the metadata that the exported API does not, so the source is parsed rather than
+// the package reflected upon.
+func Models(fsys fs.FS) (Schema, error) {
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+
+		src, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	structs := map[string]*ast.StructType{}
+	lists := map[string][]string{}
+	tables := map[string]string{}
+
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.TypeSpec:
+				if s, ok := n.Type.(*ast.StructType); ok {
+					structs[n.Name.Name] = s
+				}
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if i >= len(n.Values) {
+						break
+					}
+
+					lit, ok := n.Values[i].(*ast.CompositeLit)
+					if !ok {
+						continue
+					}
+
+					values := literals(lit)
+
+					// eg: `UserTableColumns` holds `users.id`, naming the table behind the `User` model.
+					if model := strings.TrimSuffix(name.Name, "TableColumns"); model != name.Name && len(values) > 0 {
+						tables[model] = strings.SplitN(values[0], ".", 2)[0]
+						continue
+					}
+
+					lists[name.Name] = values
+				}
+			}
+
+			return true
+		})
+	}
+
+	res := Schema{}
+
+	for model, name := range tables {
+		s, ok := structs[model]
+		if !ok {
+			return nil, fmt.Errorf("model %s for table %s not found", model, name)
+		}
+
+		// The unexported lists are named after the table the way sqlboiler does, eg: `apiKey` for `api_keys`, whose
+		// model is `APIKey`.
+		prefix := strmangle.CamelCase(strmangle.Singular(name))
+		defaults := set(lists[prefix+"ColumnsWithDefault"])
+
+		t := res.table(name)
+		t.PrimaryKey = lists[prefix+"PrimaryKeyColumns"]
+
+		for _, field := range s.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			col := reflect.StructTag(tag).Get("boil")
+			if col == "" || col == "-" {
+				continue
+			}
+
+			t.Columns[col] = Column{
+				Name:    col,
+				Type:    expr(field.Type),
+				Default: defaults[col],
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (s Schema) table(name string) *Table {
+	t, ok := s[name]
+	if !ok {
+		t = &Table{Name: name, Columns: map[string]Column{}}
+		s[name] = t
+	}
+
+	return t
+}
+
+// goType mirrors the type translation of sqlboiler's psql driver, for the types it maps.
+func goType(dataType, udt string, nullable bool) string {
+	var t, nullT string
+
+	switch dataType {
+	case "bigint", "bigserial":
+		t, nullT = "int64", "null.Int64"
+	case "integer", "serial":
+		t, nullT = "int", "null.Int"
+	case "oid":
+		t, nullT = "uint32", "null.Uint32"
+	case "smallint", "smallserial":
+		t, nullT = "int16", "null.Int16"
+	case "decimal", "numeric":
+		t, nullT = "types.Decimal", "types.NullDecimal"
+	case "double precision":
+		t, nullT = "float64", "null.Float64"
+	case "real":
+		t, nullT = "float32", "null.Float32"
+	case "bytea":
+		t, nullT = "[]byte", "null.Bytes"
+	case "json", "jsonb":
+		t, nullT = "types.JSON", "null.JSON"
+	case "boolean":
+		t, nullT = "bool", "null.Bool"
+	case "date", "time", "timestamp without time zone", "timestamp with time zone",
+		"time without time zone", "time with time zone":
+		t, nullT = "time.Time", "null.Time"
+	case "ARRAY":
+		switch udt {
+		case "_int2", "_int4", "_int8":
+			return "types.Int64Array"
+		case "_bool":
+			return "types.BoolArray"
+		case "_float4", "_float8":
+			return "types.Float64Array"
+		case "_numeric":
+			return "types.DecimalArray"
+		case "_bytea":
+			return "types.BytesArray"
+		default:
+			return "types.StringArray"
+		}
+	default:
+		t, nullT = "string", "null.String"
+	}
+
+	if nullable {
+		return nullT
+	}
+
+	return t
+}
+
+// expr renders a field's type, eg: `time.Time`.
+func expr(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return expr(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + expr(e.X)
+	case *ast.ArrayType:
+		return "[]" + expr(e.Elt)
+	default:
+		return fmt.Sprintf("%T", e)
+	}
+}
+
+// literals returns the string literals in a composite literal, eg: `[]string{"id", "email"}`, including those of
+// keyed elements, eg: `{ID: "users.id"}`.
+func literals(lit *ast.CompositeLit) []string {
+	var res []string
+
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+
+		if b, ok := elt.(*ast.BasicLit); ok && b.Kind == token.STRING {
+			if v, err := strconv.Unquote(b.Value); err == nil {
+				res = append(res, v)
+			}
+		}
+	}
+
+	return res
+}
+
+func keys[V any](m map[string]V) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+
+	return res
+}
+
+// union returns the distinct values of a and b, sorted.
+func union(a, b []string) []string {
+	seen := set(append(append([]string{}, a...), b...))
+
+	res := keys(seen)
+	sort.Strings(res)
+
+	return res
+}
+
+func set(values []string) map[string]bool {
+	res := make(map[string]bool, len(values))
+	for _, v := range values {
+		res[v] = true
+	}
+
+	return res
+}
+
+func presence(ok bool) string {
+	if ok {
+		return "set"
+	}
+
+	return "unset"
+}
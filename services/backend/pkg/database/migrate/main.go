@@ -0,0 +1,471 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/migration"
+)
+
+// Table tracks the applied version. Its layout matches the `migrate` CLI, so either tool can manage a database.
+const Table = "schema_migrations"
+
+// lockKey identifies the session-level advisory lock held while migrating.
+const lockKey int64 = 1668889845
+
+// Error strings
+var (
+	ErrDirty = errors.New("database is dirty, a previous migration failed part way and must be fixed by hand")
+)
+
+// filePattern matches migration file names, eg: `1668889845_create_users.up.sql`.
+var filePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// directivePattern matches `-- migrate:up transaction:false` style directives.
+var directivePattern = regexp.MustCompile(`(?m)^\s*--\s*migrate:(up|down)\b(.*)$`)
+
+// Migration is a single versioned schema change, parsed from a pair of `<version>_<name>.{up,down}.sql` files.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Script
+	Down    Script
+}
+
+// Script is the parsed body of one direction of a migration.
+type Script struct {
+	Statements []string
+	// NoTransaction is set by a `transaction:false` directive. Such scripts run statement by statement outside of a
+	// transaction, which statements like `CREATE INDEX CONCURRENTLY` require.
+	NoTransaction bool
+}
+
+// Load parses the migrations held in fsys, sorted by ascending version.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	byVersion := map[int64]*Migration{}
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		match := filePattern.FindStringSubmatch(d.Name())
+		if match == nil {
+			return nil
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration version in %s: %w", path, err)
+		}
+
+		body, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != match[2] {
+			return fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		script := Parse(string(body))
+		if match[3] == "up" {
+			m.Up = script
+		} else {
+			m.Down = script
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Parse splits a migration file into statements and reads its directives.
+func Parse(body string) Script {
+	script := Script{Statements: Split(body)}
+
+	for _, match := range directivePattern.FindAllStringSubmatch(body, -1) {
+		for _, option := range strings.Fields(match[2]) {
+			if option == "transaction:false" {
+				script.NoTransaction = true
+			}
+		}
+	}
+
+	return script
+}
+
+// Split splits SQL into individual statements on semicolons, ignoring those inside comments, quoted strings,
+// quoted identifiers and dollar-quoted bodies. Statements consisting only of comments are dropped.
+func Split(body string) []string {
+	var statements []string
+
+	start := 0
+	meaningful := false
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '-' && strings.HasPrefix(body[i:], "--"):
+			end := strings.IndexByte(body[i:], '\n')
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end
+			}
+		case c == '/' && strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end + 3
+			}
+		case c == '\'' || c == '"':
+			meaningful = true
+			end := strings.IndexByte(body[i+1:], c)
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end + 1
+			}
+		case c == '$':
+			meaningful = true
+			tag := dollarTag(body[i:])
+			if tag == "" {
+				continue
+			}
+
+			end := strings.Index(body[i+len(tag):], tag)
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += len(tag) + end + len(tag) - 1
+			}
+		case c == ';':
+			if meaningful {
+				statements = append(statements, strings.TrimSpace(body[start:i]))
+			}
+
+			start = i + 1
+			meaningful = false
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			meaningful = true
+		}
+	}
+
+	if meaningful {
+		statements = append(statements, strings.TrimSpace(body[start:]))
+	}
+
+	return statements
+}
+
+// dollarTag returns the dollar-quote opening s, eg: `$$` or `$body$`, or an empty string if there is none.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || (i > 1 && c >= '0' && c <= '9')) {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// Status describes a migration and whether it has been applied.
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+	Dirty   bool
+}
+
+// executor is satisfied by both *sql.DB and *sql.Conn.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Migrator applies migrations to a database. Every operation holds a Postgres advisory lock, so concurrent
+// migrators, eg: several pods auto-migrating on startup, wait for each other instead of racing.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// New constructs a Migrator for the supplied migrations, as returned by Load.
+func New(db *sql.DB, migrations []*Migration) *Migrator {
+	return &Migrator{db, migrations}
+}
+
+// Embedded constructs a Migrator for the perspex migrations embedded in the binary.
+func Embedded(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(migration.Perspex)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(db, migrations), nil
+}
+
+// Up applies every migration newer than the database's current version, in order.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sql.Conn, current int64) error {
+		for _, mig := range m.migrations {
+			if mig.Version <= current {
+				continue
+			}
+
+			otelzap.L().Ctx(ctx).Info("Applying migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+			if err := apply(ctx, conn, mig.Up, mig.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts the most recently applied migrations, newest first. A negative number of steps reverts them all.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *sql.Conn, current int64) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps != 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > current {
+				continue
+			}
+
+			var target int64
+			if i > 0 {
+				target = m.migrations[i-1].Version
+			}
+
+			otelzap.L().Ctx(ctx).Info("Reverting migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+			if err := apply(ctx, conn, mig.Down, target); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// Force records the version as applied and clean without running any migrations. It is used to recover from a
+// dirty database once the failed migration has been fixed by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer m.unlock(conn)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return setVersion(ctx, conn, version, false)
+}
+
+// Status lists every migration, reporting which have been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+
+	current, dirty, err := Version(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{
+			Version: mig.Version,
+			Name:    mig.Name,
+			Applied: mig.Version <= current,
+			Dirty:   dirty && mig.Version == current,
+		}
+	}
+
+	return statuses, nil
+}
+
+// Version returns the currently applied version, zero when none has been, and whether it is dirty.
+func Version(ctx context.Context, db executor) (version int64, dirty bool, err error) {
+	err = db.QueryRowContext(ctx, `SELECT version, dirty FROM `+Table+` LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// locked runs f on a connection holding the advisory lock, refusing to run if the database is dirty.
+func (m *Migrator) locked(ctx context.Context, f func(conn *sql.Conn, current int64) error) error {
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer m.unlock(conn)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	current, dirty, err := Version(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("version %d: %w", current, ErrDirty)
+	}
+
+	return f(conn, current)
+}
+
+// lock pins a connection and takes the advisory lock on it, waiting for any other migrator to finish.
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("acquiring migration lock: %w", err)
+	}
+
+	return conn, nil
+}
+
+// unlock releases the advisory lock and returns the connection to the pool.
+func (m *Migrator) unlock(conn *sql.Conn) {
+	if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+		otelzap.L().Error("Failed to release migration lock", zap.Error(err))
+	}
+
+	conn.Close()
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig
+		}
+	}
+
+	return nil
+}
+
+// apply runs a script, recording the target version as dirty while it is in progress and clean once it completes.
+func apply(ctx context.Context, db executor, script Script, target int64) error {
+	if script.NoTransaction {
+		if err := setVersion(ctx, db, target, true); err != nil {
+			return err
+		}
+
+		for _, stmt := range script.Statements {
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+
+		return setVersion(ctx, db, target, false)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, stmt := range script.Statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if err := setVersionTx(ctx, tx, target, false); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setVersion replaces the recorded version.
+func setVersion(ctx context.Context, db executor, version int64, dirty bool) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := setVersionTx(ctx, tx, version, dirty); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setVersionTx(ctx context.Context, tx *sql.Tx, version int64, dirty bool) error {
+	if _, err := tx.ExecContext(ctx, `TRUNCATE `+Table); err != nil {
+		return err
+	}
+
+	// Like the `migrate` CLI, a clean zero version is recorded as an empty table.
+	if version <= 0 && !dirty {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `INSERT INTO `+Table+` (version, dirty) VALUES ($1, $2)`, version, dirty)
+	return err
+}
+
+func ensureTable(ctx context.Context, db executor) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`)
+	return err
+}
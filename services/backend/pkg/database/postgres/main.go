@@ -5,27 +5,69 @@ import (
 	"database/sql"
 	"errors"
 	"sync"
+	"time"
 
-	// import the `pgx` driver for use in `sql.Open`
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	// stdlib also registers the `pgx` driver for use in `sql.Open`
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
-	"github.com/volatiletech/sqlboiler/v4/boil"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapio"
 
 	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/secrets"
+	"github.com/jmandel1027/perspex/services/backend/pkg/telemetry"
 )
 
-// DB is a PostgreSQL connection. It uses the `pgx` driver wrapped by the `pgx/stdlib` compatibility layer.
+// DB is a PostgreSQL connection to both writer and reader. Each is a native `pgxpool` pool, for pgx features such as
+// batches, COPY and LISTEN/NOTIFY, bridged to `database/sql` for sqlboiler. Both share the pool's connections.
 type DB struct {
-	Writer *sql.DB
-	Reader *sql.DB
+	Writer     *sql.DB
+	Reader     *sql.DB
+	WriterPool *pgxpool.Pool
+	ReaderPool *pgxpool.Pool
+}
+
+// Pool is a native pool, with a `database/sql` bridge over its connections.
+type Pool struct {
+	*pgxpool.Pool
+	DB *sql.DB
+}
+
+// Hooks are called over the lifecycle of pooled connections, after the built in setup. Any may be nil.
+type Hooks struct {
+	// AfterConnect is called once a connection is established, failing the connection on error.
+	AfterConnect func(ctx context.Context, conn *pgx.Conn) error
+	// BeforeAcquire is called before a connection is handed out, destroying it unless true is returned.
+	BeforeAcquire func(ctx context.Context, conn *pgx.Conn) bool
+	// AfterRelease is called when a connection is returned, destroying it unless true is returned.
+	AfterRelease func(conn *pgx.Conn) bool
+	// BeforeClose is called before a connection is closed.
+	BeforeClose func(conn *pgx.Conn)
+}
+
+// Stats is a snapshot of the writer and reader pools.
+type Stats struct {
+	Writer *pgxpool.Stat
+	Reader *pgxpool.Stat
 }
 
 // Tx is a PostgreSQL transaction.
 type Tx struct {
 	*sql.Tx
 	mu sync.Mutex
+
+	// ctx is the context the transaction began in, parenting the spans of its commit or rollback.
+	ctx context.Context
+
+	// committed are the functions to call once the transaction has committed, see AfterCommit.
+	hooks     sync.Mutex
+	committed []func()
 }
 
 // Error strings
@@ -50,6 +92,14 @@ const txWriteKey Key = 42
 // txKey is the context key for the request-scoped transaction, arbitrarily set to 42.
 const txReaderKey Key = 43
 
+// log is sampled, as it is called for every transaction and connection.
+func log() *otelzap.Logger {
+	return logger.Sampled("postgres")
+}
+
+// maxAttempts bounds how many times a transaction started by DB.InTx is run when it fails to serialize.
+const maxAttempts = 3
+
 // StdTxOpts are standard repeatable read transaction options used for most database operations.
 var StdTxOpts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
 
@@ -64,21 +114,21 @@ var ReadOnlyTxOpts = &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly:
 // transaction within the passed context. A non-nil error will be returned
 // if the context is not open or does not contain a transaction.
 func InTx(ctx context.Context, opts *sql.TxOptions, f func(tx *Tx) error) error {
-	otelzap.Ctx(ctx).Info("in transaction")
+	log().Ctx(ctx).Debug("In transaction")
 	if ctx.Err() != nil {
-		otelzap.Ctx(ctx).Info(ctx.Err().Error())
+		log().Ctx(ctx).Debug("Transaction context is done", zap.Error(ctx.Err()))
 		return ErrTXRequiresActiveCtx
 	}
 
 	key, err := WhichConnection(ctx, opts)
 	if err != nil {
-		otelzap.Ctx(ctx).Error("Requires opts", zap.Error(err))
+		log().Ctx(ctx).Error("Requires opts", zap.Error(err))
 		return ErrTXRequiresOpts
 	}
 
 	tx, ok := FromContext(ctx, *key)
 	if !ok {
-		otelzap.Ctx(ctx).Error("Transaction requires active ctx")
+		log().Ctx(ctx).Error("Transaction requires active ctx")
 		return ErrTXRequiresActiveCtx
 	}
 
@@ -89,6 +139,52 @@ func InTx(ctx context.Context, opts *sql.TxOptions, f func(tx *Tx) error) error
 	return f(tx)
 }
 
+// InTx provides the passed function with the transaction carried by the passed context, like the package level InTx.
+// When the context carries no transaction, such as outside of an RPC, a transaction is started on the matching
+// connection instead, committed on success and rolled back on failure.
+func (db *DB) InTx(ctx context.Context, opts *sql.TxOptions, f func(tx *Tx) error) error {
+	key, err := WhichConnection(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := FromContext(ctx, *key); ok {
+		return InTx(ctx, opts, f)
+	}
+
+	conn := db.Writer
+	if opts.ReadOnly {
+		conn = db.Reader
+	}
+
+	for attempt := 1; ; attempt++ {
+		tx, err := BeginTx(ctx, conn, opts)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Execute(f)
+		if attempt == maxAttempts || !Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		log().Ctx(ctx).Debug("Retrying transaction", zap.Int("attempt", attempt), zap.Error(err))
+		metrics.Tx(metrics.TxRetry)
+	}
+}
+
+// Retryable reports whether err is a serialization failure or deadlock, which Postgres resolves by aborting one of
+// the transactions involved, so that running it again may succeed.
+func Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// serialization_failure, deadlock_detected
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
 // WhichConnection returns the key for the connection to use for the given transaction options.
 func WhichConnection(ctx context.Context, opts *sql.TxOptions) (*Key, error) {
 	if ctx.Err() != nil {
@@ -110,81 +206,331 @@ func WhichConnection(ctx context.Context, opts *sql.TxOptions) (*Key, error) {
 }
 
 // Open opens a database connection to both writer and reader.
-func Open(cfg *config.BackendConfig) (*DB, error) {
-	writer, err := sql.Open("pgx", cfg.WriterPG.GetDataSourceName())
+func Open(cfg *config.BackendConfig, hooks ...Hooks) (*DB, error) {
+	writer, err := Connect(cfg.WriterPG, hooks...)
 	if err != nil {
-		otelzap.L().Error("WriterPG Error: ", zap.Error(err))
+		log().Error("WriterPG Error: ", zap.Error(err))
 		return nil, err
 	}
 
-	writer.SetMaxOpenConns(cfg.WriterPG.GetMaxOpenConns())
-	writer.SetMaxIdleConns(cfg.WriterPG.GetMaxIdleConns())
-	writer.SetConnMaxLifetime(cfg.WriterPG.GetConnMaxLifetime())
-
-	reader, err := sql.Open("pgx", cfg.ReaderPG.GetDataSourceName())
+	reader, err := Connect(cfg.ReaderPG, hooks...)
 	if err != nil {
-		otelzap.L().Error("ReaderPG Error: ", zap.Error(err))
+		log().Error("ReaderPG Error: ", zap.Error(err))
+		writer.Close()
 		return nil, err
 	}
 
-	reader.SetMaxOpenConns(cfg.ReaderPG.GetMaxOpenConns())
-	reader.SetMaxIdleConns(cfg.ReaderPG.GetMaxIdleConns())
-	reader.SetConnMaxLifetime(cfg.ReaderPG.GetConnMaxLifetime())
+	return &DB{
+		Writer:     writer.DB,
+		Reader:     reader.DB,
+		WriterPool: writer.Pool,
+		ReaderPool: reader.Pool,
+	}, nil
+}
 
-	return &DB{writer, reader}, nil
+// Close closes the bridges, then the pools beneath them.
+func (db *DB) Close() {
+	db.Writer.Close()
+	db.Reader.Close()
+	db.WriterPool.Close()
+	db.ReaderPool.Close()
 }
 
-// BeginTx initializes a transaction.
-func BeginTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*Tx, error) {
-	if ctx.Err() != nil {
-		return nil, ErrTXRequiresActiveCtx
+// Stats returns a snapshot of the pools' connections and acquisitions.
+func (db *DB) Stats() Stats {
+	return Stats{Writer: db.WriterPool.Stat(), Reader: db.ReaderPool.Stat()}
+}
+
+// Connect opens a pool for cfg. Connections are dialled lazily, as they are needed. When the password is read from a
+// secret it is resolved again for every new connection, so a rotated password is picked up by new connections while
+// open ones live out their lifespan.
+func Connect(cfg config.PostgresConfig, hooks ...Hooks) (*Pool, error) {
+	pc, err := pgxpool.ParseConfig(cfg.GetDataSourceName())
+	if err != nil {
+		return nil, err
 	}
 
-	if opts == nil {
-		return nil, ErrTXRequiresOpts
+	pc.ConnConfig.Tracer = tracers{metrics.Tracer{}, telemetry.QueryTracer{}, debugger{}}
+
+	pc.MinConns = int32(cfg.GetMinConns())
+
+	// Zero settings keep the driver's defaults.
+	if cfg.GetMaxOpenConns() > 0 {
+		pc.MaxConns = int32(cfg.GetMaxOpenConns())
 	}
 
-	cfg, err := config.New()
+	if cfg.GetConnMaxLifetime() > 0 {
+		pc.MaxConnLifetime = cfg.GetConnMaxLifetime()
+	}
+
+	if cfg.GetMaxIdleTime() > 0 {
+		pc.MaxConnIdleTime = cfg.GetMaxIdleTime()
+	}
+
+	if ref := cfg.PasswordRef(); ref != "" {
+		password := secrets.New(ref, cfg.Password)
+
+		// Fail fast on a misconfigured secret, rather than on the first query.
+		if _, err := password.Value(context.Background()); err != nil {
+			return nil, err
+		}
+
+		pc.BeforeConnect = func(ctx context.Context, conn *pgx.ConnConfig) error {
+			v, err := password.Value(ctx)
+			if err != nil {
+				return err
+			}
+
+			conn.Password = v
+			return nil
+		}
+	}
+
+	// search_path and timezone are sent as runtime parameters by every new connection, see GetDataSourceName.
+	pc.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		log().Ctx(ctx).Debug("Postgres connection opened",
+			zap.String("host", conn.Config().Host),
+			zap.Uint32("pid", conn.PgConn().PID()),
+		)
+
+		for _, h := range hooks {
+			if h.AfterConnect == nil {
+				continue
+			}
+
+			if err := h.AfterConnect(ctx, conn); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	pc.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		for _, h := range hooks {
+			if h.BeforeAcquire != nil && !h.BeforeAcquire(ctx, conn) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	pc.AfterRelease = func(conn *pgx.Conn) bool {
+		for _, h := range hooks {
+			if h.AfterRelease != nil && !h.AfterRelease(conn) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	pc.BeforeClose = func(conn *pgx.Conn) {
+		log().Debug("Postgres connection closed", zap.Uint32("pid", conn.PgConn().PID()))
+
+		for _, h := range hooks {
+			if h.BeforeClose != nil {
+				h.BeforeClose(conn)
+			}
+		}
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), pc)
 	if err != nil {
 		return nil, err
 	}
 
-	if cfg.Log.Verbose {
-		writer := &zapio.Writer{
-			Log:   otelzap.Ctx(ctx).Logger().Logger,
-			Level: zap.DebugLevel,
-		}
+	return &Pool{Pool: pool, DB: stdlib.OpenDBFromPool(pool)}, nil
+}
 
-		defer writer.Close()
+// Close closes the bridge, then the pool beneath it.
+func (p *Pool) Close() {
+	p.DB.Close()
+	p.Pool.Close()
+}
 
-		boil.DebugMode = true
-		boil.DebugWriter = writer
+// BeginTx initializes a transaction.
+func BeginTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*Tx, error) {
+	if ctx.Err() != nil {
+		return nil, ErrTXRequiresActiveCtx
+	}
+
+	if opts == nil {
+		return nil, ErrTXRequiresOpts
 	}
 
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
-		otelzap.L().Ctx(ctx).Error("Failed to begin transaction", zap.Error(err))
+		log().Ctx(ctx).Error("Failed to begin transaction", zap.Error(err))
 		return nil, err
 	}
 
-	return &Tx{Tx: tx}, nil
+	return &Tx{Tx: tx, ctx: ctx}, nil
 }
 
-// Execute runs a tx-scoped function, commiting on success and rolling back on failure.
+// Execute runs a tx-scoped function, commiting on success and rolling back on failure. A failed commit is returned.
 func (tx *Tx) Execute(fn TxFunc) (err error) {
 
 	defer func() {
-		if p := recover(); err != nil || p != nil {
+		// Panics are passed on once rolled back, to be recovered from by the caller.
+		if p := recover(); p != nil {
 			tx.Rollback()
-		} else {
-			tx.Commit()
+			panic(p)
 		}
 
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
 	}()
 
 	return fn(tx)
 }
 
+// Commit commits the transaction, counting it as rolled back if the commit fails. Once committed, the functions
+// registered with AfterCommit are called in the order they were registered.
+func (tx *Tx) Commit() error {
+	span := tx.span("COMMIT")
+
+	err := tx.Tx.Commit()
+	record(err)
+	telemetry.End(span, err)
+
+	if err == nil {
+		tx.hooks.Lock()
+		committed := tx.committed
+		tx.committed = nil
+		tx.hooks.Unlock()
+
+		for _, f := range committed {
+			f()
+		}
+	}
+
+	return err
+}
+
+// AfterCommit registers f to be called once the transaction has committed, such as to evict cached copies of the rows
+// it changed. f is never called when the transaction rolls back.
+func (tx *Tx) AfterCommit(f func()) {
+	tx.hooks.Lock()
+	defer tx.hooks.Unlock()
+
+	tx.committed = append(tx.committed, f)
+}
+
+// Rollback rolls the transaction back.
+func (tx *Tx) Rollback() error {
+	span := tx.span("ROLLBACK")
+
+	err := tx.Tx.Rollback()
+	if err != sql.ErrTxDone {
+		metrics.Tx(metrics.TxRollback)
+	}
+
+	telemetry.End(span, err)
+
+	return err
+}
+
+// span starts a span for ending the transaction, within the trace it began in. `database/sql` ends transactions
+// without a context, so the statement's own span would have no parent.
+func (tx *Tx) span(operation string) trace.Span {
+	ctx := tx.ctx
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return trace.SpanFromContext(context.Background())
+	}
+
+	_, span := telemetry.Tracer().Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationKey.String(operation)),
+	)
+
+	return span
+}
+
+// tracers hands every query to each of its tracers in turn.
+type tracers []pgx.QueryTracer
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t tracers) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	for _, tracer := range t {
+		ctx = tracer.TraceQueryStart(ctx, conn, data)
+	}
+
+	return ctx
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t tracers) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	for i := len(t) - 1; i >= 0; i-- {
+		t[i].TraceQueryEnd(ctx, conn, data)
+	}
+}
+
+type debugKey struct{}
+
+type statementKey struct{}
+
+// WithDebug returns a context whose statements are logged to the `sql` logger, with fields. Only the statements and
+// the number of values bound to them are logged, the values may hold personal data that the redaction policy cannot
+// tell apart within a message.
+func WithDebug(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, debugKey{}, logger.Named("sql").Logger.With(fields...))
+}
+
+// debugger logs the statements run with a context from WithDebug.
+type debugger struct{}
+
+type statement struct {
+	log   *zap.Logger
+	sql   string
+	args  int
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (debugger) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	log, ok := ctx.Value(debugKey{}).(*zap.Logger)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, statementKey{}, statement{log: log, sql: data.SQL, args: len(data.Args), start: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (debugger) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	s, ok := ctx.Value(statementKey{}).(statement)
+	if !ok {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("statement", s.sql),
+		zap.Int("args", s.args),
+		zap.Duration("duration", time.Since(s.start)),
+	}
+
+	if data.Err != nil {
+		fields = append(fields, zap.Error(data.Err))
+	}
+
+	s.log.Info("Query", fields...)
+}
+
+// record counts the outcome of a commit, Postgres rolls back a transaction that fails to commit.
+func record(err error) {
+	switch {
+	case err == nil:
+		metrics.Tx(metrics.TxCommit)
+	case err != sql.ErrTxDone:
+		metrics.Tx(metrics.TxRollback)
+	}
+}
+
 // Lock locks the transaction, preventing concurrent use.
 func (tx *Tx) Lock() {
 	tx.mu.Lock()
@@ -197,23 +543,6 @@ func (tx *Tx) Unlock() {
 
 // WithTx creates a transaction block, commits on success, and rolls back on failure.
 func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn SqlTxFunc) error {
-	cfg, err := config.New()
-	if err != nil {
-		return err
-	}
-
-	if cfg.Log.Verbose {
-		writer := &zapio.Writer{
-			Log:   otelzap.Ctx(ctx).Logger().Logger,
-			Level: zap.DebugLevel,
-		}
-
-		defer writer.Close()
-
-		boil.DebugMode = true
-		boil.DebugWriter = writer
-	}
-
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
@@ -222,21 +551,29 @@ func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn SqlTxFunc)
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
+			metrics.Tx(metrics.TxRollback)
+			panic(p)
 		} else if err != nil {
 			tx.Rollback()
+			metrics.Tx(metrics.TxRollback)
 		} else {
-			if err := tx.Commit(); err != nil {
+			err := tx.Commit()
+			record(err)
+
+			if err != nil {
 				// https://golang.org/pkg/database/sql/#Tx
 				// After a call to Commit or Rollback, all operations on the
 				// transaction fail with ErrTxDone.
 				if err == sql.ErrTxDone {
-					otelzap.L().Ctx(ctx).Error("Failed to commit transaction", zap.Error(err))
+					log().Ctx(ctx).Error("Failed to commit transaction", zap.Error(err))
 				}
 			}
 		}
 	}()
 
-	return fn(tx)
+	err = fn(tx)
+
+	return err
 }
 
 // FromContext extracts an active Postgres transaction from a context.
@@ -0,0 +1,35 @@
+package postgres_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+)
+
+func TestRetryable(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"SerializationFailure": {err: &pgconn.PgError{Code: "40001"}, want: true},
+		"Deadlock":             {err: &pgconn.PgError{Code: "40P01"}, want: true},
+		"Wrapped":              {err: fmt.Errorf("Couldn't update user: %w", &pgconn.PgError{Code: "40001"}), want: true},
+		"UniqueViolation":      {err: &pgconn.PgError{Code: "23505"}},
+		"Other":                {err: errors.New("Couldn't update user: serialization failure")},
+		"Nil":                  {},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			if got := postgres.Retryable(c.err); got != c.want {
+				t.Fatalf("expected %t, got %t", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package harness
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	connect "github.com/bufbuild/connect-go"
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	apikeys "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/apikeys/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	apikeyRepository "github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository"
+	apikeyService "github.com/jmandel1027/perspex/services/backend/pkg/apikey/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	auditRepository "github.com/jmandel1027/perspex/services/backend/pkg/audit/repository"
+	auditService "github.com/jmandel1027/perspex/services/backend/pkg/audit/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/authz"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/migrate"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	transaction "github.com/jmandel1027/perspex/services/backend/pkg/middleware/postgres"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	outboxRepository "github.com/jmandel1027/perspex/services/backend/pkg/outbox/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
+	"github.com/jmandel1027/perspex/services/backend/pkg/router"
+	userRepository "github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	userService "github.com/jmandel1027/perspex/services/backend/pkg/user/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook"
+	webhookRepository "github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+	webhookService "github.com/jmandel1027/perspex/services/backend/pkg/webhook/service"
+)
+
+const (
+	user     = "postgres"
+	password = "postgres"
+
+	// template is the database holding the migrated schema, cloned for every test.
+	template = "perspex_template"
+)
+
+// Postgres is an ephemeral Postgres server with the perspex migrations applied to a template database. It is
+// typically started once per package from TestMain and shared by all of its tests.
+type Postgres struct {
+	server   *embeddedpostgres.EmbeddedPostgres
+	port     uint32
+	dir      string
+	seq      int64
+	defaults config.PostgresConfig
+}
+
+// Harness is the full backend handler, served over HTTP against an isolated database.
+type Harness struct {
+	Config *config.BackendConfig
+	DB     *postgres.DB
+	Log    *otelzap.Logger
+	Clock  clock.Clock
+	Server *httptest.Server
+	Users  usersconnect.UserServiceClient
+	Watch  *watch.Hub
+}
+
+// ServiceFunc builds a service to mount, using the harness' dependencies.
+type ServiceFunc func(h *Harness) registry.Service
+
+// Start starts an ephemeral Postgres server on a free port and migrates the template database. Server output is
+// discarded unless `HARNESS_VERBOSE` is set.
+func Start() (*Postgres, error) {
+	defaults, err := config.Defaults()
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "perspex-harness-")
+	if err != nil {
+		return nil, err
+	}
+
+	var logs io.Writer = io.Discard
+	if _, ok := os.LookupEnv("HARNESS_VERBOSE"); ok {
+		logs = os.Stderr
+	}
+
+	server := embeddedpostgres.NewDatabase(
+		embeddedpostgres.DefaultConfig().
+			Port(port).
+			Username(user).
+			Password(password).
+			Database("postgres").
+			RuntimePath(dir).
+			Logger(logs),
+	)
+
+	if err := server.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	pg := &Postgres{server: server, port: port, dir: dir, defaults: defaults.WriterPG}
+
+	if err := pg.migrate(); err != nil {
+		pg.Stop()
+		return nil, err
+	}
+
+	return pg, nil
+}
+
+var (
+	// shared is the server started by Main, nil when it could not be started for the reason held by unavailable.
+	shared      *Postgres
+	unavailable error
+)
+
+// Main runs the tests of a package from its TestMain, starting a server shared by all of them beforehand and stopping
+// it once they have run. When the server can't be started, such as without network access to fetch its binaries, the
+// tests calling Require are skipped, unless `CI` is set, in which case they all fail.
+func Main(m *testing.M) {
+	pg, err := Start()
+	if err != nil {
+		if _, ok := os.LookupEnv("CI"); ok {
+			fmt.Fprintf(os.Stderr, "starting postgres: %v\n", err)
+			os.Exit(1)
+		}
+
+		unavailable = err
+	}
+
+	shared = pg
+	code := m.Run()
+
+	if pg != nil {
+		if err := pg.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "stopping postgres: %v\n", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// Require returns the server started by Main, skipping the test when it could not be started.
+func Require(t testing.TB) *Postgres {
+	t.Helper()
+
+	if shared == nil {
+		if unavailable == nil {
+			t.Fatal("harness.Main must run the tests of the package, from its TestMain")
+		}
+
+		t.Skipf("postgres is unavailable: %v", unavailable)
+	}
+
+	return shared
+}
+
+// Stop stops the server and removes its data.
+func (pg *Postgres) Stop() error {
+	defer os.RemoveAll(pg.dir)
+
+	return pg.server.Stop()
+}
+
+// New serves the backend against a fresh database cloned from the template, so tests may run in parallel without
+// observing each other's writes. Only the supplied services are mounted, or the UserService when none are supplied.
+// Everything is torn down when the test completes.
+func (pg *Postgres) New(t testing.TB, services ...ServiceFunc) *Harness {
+	t.Helper()
+
+	return pg.NewWith(t, nil, services...)
+}
+
+// NewWith is New, serving the backend with the config holding the defaults, see config.Defaults, and the harness'
+// database and address, overridden by configure when it is not nil. The settings of the process are ignored, so tests
+// behave the same wherever they run.
+func (pg *Postgres) NewWith(t testing.TB, configure func(cfg *config.BackendConfig), services ...ServiceFunc) *Harness {
+	t.Helper()
+
+	cfg, err := config.Defaults()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(nil)
+
+	cfg.WriterPG = pg.Database(t)
+	cfg.ReaderPG = cfg.WriterPG
+	cfg.Host, cfg.HttpPort, _ = net.SplitHostPort(srv.Listener.Addr().String())
+
+	// Receivers of test webhooks listen on loopback.
+	cfg.Webhooks.AllowPrivate = true
+
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validating config: %v", err)
+	}
+
+	dbs, err := postgres.Open(&cfg)
+	if err != nil {
+		t.Fatalf("opening database %s: %v", cfg.WriterPG.Name, err)
+	}
+
+	t.Cleanup(dbs.Close)
+
+	log := otelzap.New(zap.NewNop())
+	if _, ok := os.LookupEnv("HARNESS_VERBOSE"); ok {
+		log = otelzap.New(zap.NewExample())
+	}
+
+	h := &Harness{
+		Config: &cfg,
+		DB:     dbs,
+		Log:    log,
+		Clock:  clock.New(),
+		Server: srv,
+	}
+
+	// The hub listens for user changes until the test completes, before the database is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	h.Watch = watch.NewHub(
+		userRepository.NewUserRepository(h.Config, dbs, log, cache.NewNop(), h.Clock, audit.Nop(), outbox.Nop()),
+		log, h.Clock, cfg.Watch,
+	)
+
+	go h.Watch.Run(ctx)
+	t.Cleanup(cancel)
+
+	if len(services) == 0 {
+		services = []ServiceFunc{Users}
+	}
+
+	mounted := make([]registry.Service, len(services))
+	for i, build := range services {
+		mounted[i] = build(h)
+	}
+
+	policy, err := authz.Load(cfg.Authz,
+		orgRepository.NewMembershipRepository(dbs, log, audit.Nop(), outbox.Nop()),
+		userRepository.NewUserRepository(h.Config, dbs, log, cache.NewNop(), h.Clock, audit.Nop(), outbox.Nop()),
+	)
+	if err != nil {
+		t.Fatalf("loading authorization policy: %v", err)
+	}
+
+	authn := auth.New(policy.Authenticate(auth.First(apikeyService.Authenticate(
+		apikeyRepository.NewAPIKeyRepository(dbs, log, h.Clock, audit.Nop()), h.Clock, log,
+	))))
+
+	// Like the server, accept HTTP/2 without TLS, so the gateway can reach the handlers over gRPC.
+	// The gateway's connection back to the server is closed once the server has.
+	serving, stop := context.WithCancel(context.Background())
+	t.Cleanup(stop)
+
+	srv.Config.Handler = h2c.NewHandler(router.Route(serving, h.Config, authn, policy, mounted...), &http2.Server{})
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	h.Users = usersconnect.NewUserServiceClient(srv.Client(), srv.URL)
+
+	return h
+}
+
+// Users mounts the UserService backed by the harness' database.
+func Users(h *Harness) registry.Service {
+	events := auditRepository.NewEventRepository(h.DB, h.Log, h.Clock)
+	published := outboxRepository.NewEventRepository(h.DB, h.Log, h.Clock)
+	repo := userRepository.NewUserRepository(h.Config, h.DB, h.Log, cache.NewNop(), h.Clock, events, published)
+	members := orgRepository.NewMembershipRepository(h.DB, h.Log, events, published)
+
+	return userService.Register(userService.NewUserService(repo, h.Watch, members, h.Log), transaction.Interceptors(h.DB)...)
+}
+
+// Database creates a fresh database cloned from the template, dropped when the test completes, returning the settings
+// for connecting to it. Tests of repositories use it without serving the backend.
+func (pg *Postgres) Database(t testing.TB) config.PostgresConfig {
+	t.Helper()
+
+	name := fmt.Sprintf("perspex_test_%d", atomic.AddInt64(&pg.seq, 1))
+
+	if err := pg.exec("postgres", fmt.Sprintf(`CREATE DATABASE %q TEMPLATE %q`, name, template)); err != nil {
+		t.Fatalf("creating database %s: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		if err := pg.exec("postgres", fmt.Sprintf(`DROP DATABASE IF EXISTS %q WITH (FORCE)`, name)); err != nil {
+			t.Errorf("dropping database %s: %v", name, err)
+		}
+	})
+
+	return pg.config(name)
+}
+
+// Key creates an API key for the user, returning the bearer token authenticating requests as them.
+func (h *Harness) Key(t testing.TB, userID int64) string {
+	t.Helper()
+
+	events := auditRepository.NewEventRepository(h.DB, h.Log, h.Clock)
+	members := orgRepository.NewMembershipRepository(h.DB, h.Log, events, outbox.Nop())
+	svc := apikeyService.NewApiKeyService(apikeyRepository.NewAPIKeyRepository(h.DB, h.Log, h.Clock, events), members, h.Clock, h.Log)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+
+	res, err := svc.CreateApiKey(ctx, connect.NewRequest(&apikeys.CreateApiKeyRequest{Name: t.Name()}))
+	if err != nil {
+		t.Fatalf("creating api key for user %d: %v", userID, err)
+	}
+
+	return res.Msg.Secret
+}
+
+// ApiKeys mounts the ApiKeyService backed by the harness' database.
+func ApiKeys(h *Harness) registry.Service {
+	events := auditRepository.NewEventRepository(h.DB, h.Log, h.Clock)
+	repo := apikeyRepository.NewAPIKeyRepository(h.DB, h.Log, h.Clock, events)
+	members := orgRepository.NewMembershipRepository(h.DB, h.Log, events, outboxRepository.NewEventRepository(h.DB, h.Log, h.Clock))
+
+	return apikeyService.Register(apikeyService.NewApiKeyService(repo, members, h.Clock, h.Log), transaction.Interceptors(h.DB)...)
+}
+
+// Audit mounts the AuditService backed by the harness' database.
+func Audit(h *Harness) registry.Service {
+	repo := auditRepository.NewEventRepository(h.DB, h.Log, h.Clock)
+
+	return auditService.Register(auditService.NewAuditService(repo, h.Log), transaction.Interceptors(h.DB)...)
+}
+
+// Webhooks mounts the WebhookService backed by the harness' database.
+func Webhooks(h *Harness) registry.Service {
+	repo := webhookRepository.NewWebhookRepository(h.DB, h.Log, h.Clock)
+	members := orgRepository.NewMembershipRepository(h.DB, h.Log, audit.Nop(), outbox.Nop())
+
+	return webhookService.Register(webhookService.NewWebhookService(repo, members, h.Log, h.Config.Webhooks), transaction.Interceptors(h.DB)...)
+}
+
+// Dispatcher returns a sink queueing webhook deliveries of the events relayed to it, to pass to Relay.
+func Dispatcher(h *Harness) *webhook.Dispatcher {
+	repo := webhookRepository.NewWebhookRepository(h.DB, h.Log, h.Clock)
+
+	return webhook.NewDispatcher(repo, orgRepository.NewMembershipRepository(h.DB, h.Log, audit.Nop(), outbox.Nop()))
+}
+
+// WebhookWorker returns a worker delivering the harness' webhooks with client, typically that of an httptest receiver.
+// Tests call Worker.Flush to attempt the deliveries due so far.
+func WebhookWorker(h *Harness, client *http.Client) *webhook.Worker {
+	repo := webhookRepository.NewWebhookRepository(h.DB, h.Log, h.Clock)
+
+	return webhook.NewWorker(repo, client, h.Log, h.Clock, h.Config.Webhooks)
+}
+
+// Relay returns a relay of the domain events in the harness' outbox to sink, typically a sink.Memory. Tests call
+// Relay.Flush to publish the events written so far.
+func Relay(h *Harness, sink outbox.Sink) *outbox.Relay {
+	return outbox.NewRelay(outboxRepository.NewEventRepository(h.DB, h.Log, h.Clock), sink, h.Log, h.Clock, h.Config.Outbox)
+}
+
+// Spans records every span ended while the test runs, in memory, by installing a tracer provider that samples every
+// trace. It must be called before New, which hands the provider to the interceptors. The previous provider is
+// restored once the test completes, so tests recording spans must not run in parallel.
+func Spans(t testing.TB) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		tp.Shutdown(context.Background())
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return exporter
+}
+
+// migrate creates the template database and applies the embedded migrations to it.
+func (pg *Postgres) migrate() error {
+	if err := pg.exec("postgres", fmt.Sprintf(`CREATE DATABASE %q`, template)); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("pgx", pg.config(template).GetDataSourceName())
+	if err != nil {
+		return err
+	}
+
+	// The template must have no open connections when it is cloned.
+	defer db.Close()
+
+	migrator, err := migrate.Embedded(db)
+	if err != nil {
+		return err
+	}
+
+	return migrator.Up(context.Background())
+}
+
+// exec runs a statement against the named database on a short-lived connection.
+func (pg *Postgres) exec(database string, stmt string) error {
+	db, err := sql.Open("pgx", pg.config(database).GetDataSourceName())
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	_, err = db.Exec(stmt)
+	return err
+}
+
+// config returns the settings of the writer pool by default, connecting to the named database of the server. Pools are
+// kept small, as every test opens its own against the server's limited connections.
+func (pg *Postgres) config(database string) config.PostgresConfig {
+	cfg := pg.defaults
+	cfg.Name = database
+	cfg.User = user
+	cfg.Password = password
+	cfg.Host = "127.0.0.1"
+	cfg.Port = fmt.Sprint(pg.port)
+	cfg.SSLMode = "disable"
+	cfg.MaxOpenConns = 8
+
+	return cfg
+}
+
+func freePort() (uint32, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+
+	defer l.Close()
+
+	return uint32(l.Addr().(*net.TCPAddr).Port), nil
+}
@@ -0,0 +1,243 @@
+package harness_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	connect "github.com/bufbuild/connect-go"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/harness"
+	transaction "github.com/jmandel1027/perspex/services/backend/pkg/middleware/postgres"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
+	userRepository "github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	userService "github.com/jmandel1027/perspex/services/backend/pkg/user/service"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+// enforced serves the backend with authorization enforced, whatever the default mode.
+func enforced(t *testing.T, services ...harness.ServiceFunc) *harness.Harness {
+	return harness.Require(t).NewWith(t, func(cfg *config.BackendConfig) {
+		cfg.Authz.Mode = "enforce"
+	}, services...)
+}
+
+func register(t *testing.T, h *harness.Harness, email string) *users.User {
+	t.Helper()
+
+	res, err := h.Users.RegisterUser(context.Background(), connect.NewRequest(&users.RegisterUserRequest{
+		User: &users.User{Email: email, FirstName: "Jane", LastName: "Doe"},
+	}))
+	if err != nil {
+		t.Fatalf("RegisterUser %s: %v", email, err)
+	}
+
+	return res.Msg.User
+}
+
+func retrieve(h *harness.Harness, token string, id int64) (*users.User, error) {
+	req := connect.NewRequest(&users.RetrieveUserRequest{Id: id})
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := h.Users.RetrieveUser(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Msg.User, nil
+}
+
+func TestRegisterAndRetrieve(t *testing.T) {
+	h := enforced(t)
+
+	jane := register(t, h, "jane@perspex.us")
+	john := register(t, h, "john@perspex.us")
+
+	if _, err := retrieve(h, "", jane.Id); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("RetrieveUser without credentials: expected unauthenticated, got %v", err)
+	}
+
+	token := h.Key(t, jane.Id)
+
+	found, err := retrieve(h, token, jane.Id)
+	if err != nil {
+		t.Fatalf("RetrieveUser of the caller: %v", err)
+	}
+
+	if found.Email != jane.Email {
+		t.Fatalf("expected %s, got %s", jane.Email, found.Email)
+	}
+
+	if _, err := retrieve(h, token, john.Id); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("RetrieveUser of another user: expected permission denied, got %v", err)
+	}
+
+	if _, err := retrieve(h, "pk_invalid", jane.Id); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("RetrieveUser with an invalid key: expected unauthenticated, got %v", err)
+	}
+}
+
+func TestOrganizationAdminRetrievesMembers(t *testing.T) {
+	h := enforced(t)
+
+	admin := register(t, h, "admin@perspex.us")
+	member := register(t, h, "member@perspex.us")
+	stranger := register(t, h, "stranger@perspex.us")
+
+	var orgID int64
+	if err := h.DB.Writer.QueryRow(`INSERT INTO organizations (name) VALUES ('Perspex') RETURNING id`).Scan(&orgID); err != nil {
+		t.Fatalf("creating organization: %v", err)
+	}
+
+	members := orgRepository.NewMembershipRepository(h.DB, h.Log, audit.Nop(), outbox.Nop())
+	ctx := context.Background()
+
+	if err := members.AddMember(ctx, orgID, admin.Id, orgRepository.RoleAdmin); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	if err := members.AddMember(ctx, orgID, member.Id, orgRepository.RoleMember); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	token := h.Key(t, admin.Id)
+
+	if _, err := retrieve(h, token, member.Id); err != nil {
+		t.Fatalf("RetrieveUser of a member by their admin: %v", err)
+	}
+
+	if _, err := retrieve(h, token, stranger.Id); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("RetrieveUser of a non-member: expected permission denied, got %v", err)
+	}
+}
+
+func TestDuplicateEmail(t *testing.T) {
+	h := enforced(t)
+
+	register(t, h, "taken@perspex.us")
+
+	_, err := h.Users.RegisterUser(context.Background(), connect.NewRequest(&users.RegisterUserRequest{
+		User: &users.User{Email: "taken@perspex.us"},
+	}))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists {
+		t.Fatalf("RegisterUser with a taken email: expected already exists, got %v", err)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	h := enforced(t)
+
+	res, err := h.Server.Client().Post(h.Server.URL+"/v1/user", "application/json",
+		strings.NewReader(`{"email": "gateway@perspex.us", "firstName": "Jane", "lastName": "Doe"}`))
+	if err != nil {
+		t.Fatalf("POST /v1/user: %v", err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("POST /v1/user: expected 200, got %d", res.StatusCode)
+	}
+
+	res, err = h.Server.Client().Get(h.Server.URL + "/v1/user/1")
+	if err != nil {
+		t.Fatalf("GET /v1/user/1: %v", err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("GET /v1/user/1 without credentials: expected 401, got %d", res.StatusCode)
+	}
+}
+
+// failing registers the user, then fails the request as fail does, after the write.
+type failing struct {
+	usersconnect.UnimplementedUserServiceHandler
+	repo userRepository.IUserRepository
+	fail func(ctx context.Context, svc *failing, email string) error
+}
+
+func (svc *failing) RegisterUser(ctx context.Context, rec *connect.Request[users.RegisterUserRequest]) (*connect.Response[users.RegisterUserResponse], error) {
+	if _, err := svc.repo.CreateUser(ctx, &models.User{Email: rec.Msg.User.Email}); err != nil {
+		return nil, err
+	}
+
+	if err := svc.fail(ctx, svc, rec.Msg.User.Email); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&users.RegisterUserResponse{}), nil
+}
+
+func TestFailedRequestsRollBack(t *testing.T) {
+	cases := map[string]struct {
+		fail func(ctx context.Context, svc *failing, email string) error
+		code connect.Code
+	}{
+		"Error": {
+			fail: func(context.Context, *failing, string) error {
+				return connect.NewError(connect.CodeFailedPrecondition, errors.New("failed after writing"))
+			},
+			code: connect.CodeFailedPrecondition,
+		},
+		"Panic": {
+			fail: func(context.Context, *failing, string) error { panic("failed after writing") },
+			code: connect.CodeInternal,
+		},
+		// The handler ignores a failed statement, which aborts the transaction, so the commit fails instead.
+		"Commit": {
+			fail: func(ctx context.Context, svc *failing, email string) error {
+				svc.repo.CreateUser(ctx, &models.User{Email: email})
+				return nil
+			},
+			code: connect.CodeAborted,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			h := enforced(t, func(h *harness.Harness) registry.Service {
+				repo := userRepository.NewUserRepository(h.Config, h.DB, h.Log, cache.NewNop(), h.Clock, audit.Nop(), outbox.Nop())
+
+				return userService.Register(&failing{repo: repo, fail: c.fail}, transaction.Interceptors(h.DB)...)
+			})
+
+			email := fmt.Sprintf("%s@perspex.us", strings.ToLower(name))
+
+			_, err := h.Users.RegisterUser(context.Background(), connect.NewRequest(&users.RegisterUserRequest{
+				User: &users.User{Email: email},
+			}))
+			if connect.CodeOf(err) != c.code {
+				t.Fatalf("expected %s, got %v", c.code, err)
+			}
+
+			var count int
+			if err := h.DB.Writer.QueryRow(`SELECT count(*) FROM users WHERE email = $1`, email).Scan(&count); err != nil {
+				t.Fatalf("counting users: %v", err)
+			}
+
+			if count != 0 {
+				t.Fatalf("expected the write of a failed request to be rolled back, found %d users", count)
+			}
+		})
+	}
+}
@@ -1,17 +1,44 @@
 package logger
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/redact"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// New returns a new logger
+// registry holds the core shared by the root and subsystem loggers, and the levels gating each of them.
+type registry struct {
+	base       *zap.Logger
+	levels     *Levels
+	initial    int
+	thereafter int
+
+	mu      sync.Mutex
+	loggers map[string]*otelzap.Logger
+}
+
+// std is the registry of the last logger built by New.
+var (
+	stdMu sync.RWMutex
+	std   *registry
+)
+
+// New returns a new logger at the configured root level, and makes it the parent of the subsystem loggers returned
+// by Named and Sampled.
 func New(cfg config.BackendConfig) *otelzap.Logger {
 
 	config := zap.Config{
-		Encoding:         "json",
+		Encoding: "json",
+		// Levels are enforced by each logger, see Levels, so the core itself accepts everything.
 		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
 		OutputPaths:      []string{"stdout"},
 		ErrorOutputPaths: []string{"stderr"},
@@ -23,6 +50,7 @@ func New(cfg config.BackendConfig) *otelzap.Logger {
 			LevelKey:      "level",
 			StacktraceKey: "stack",
 			TimeKey:       "time",
+			NameKey:       "logger",
 		},
 	}
 
@@ -32,10 +60,33 @@ func New(cfg config.BackendConfig) *otelzap.Logger {
 		config.EncoderConfig.FunctionKey = "path"
 	}
 
-	logger, _ := config.Build()
+	// Personal data is hidden from every logger, see redact.Policy.
+	redact.Configure(cfg.Redact)
+
+	base, _ := config.Build(zap.WrapCore(redact.Core))
+
+	// The config is validated when loaded, so the levels parse.
+	root, _ := zapcore.ParseLevel(cfg.Log.Level)
+	subsystems, _ := cfg.Log.GetLevels()
+
+	levels := NewLevels(root, subsystems)
+
+	r := &registry{
+		base:       base,
+		levels:     levels,
+		initial:    cfg.Log.SampleInitial,
+		thereafter: cfg.Log.SampleThereafter,
+		loggers:    map[string]*otelzap.Logger{},
+	}
+
+	stdMu.Lock()
+	std = r
+	stdMu.Unlock()
+
+	logger := r.logger("", false)
 	logger.Info("Logger construction succeeded")
 
-	return otelzap.New(logger)
+	return logger
 }
 
 // ReplaceGlobals replaces zap's global loggers with the provided logger.
@@ -43,3 +94,229 @@ func ReplaceGlobals(logger *otelzap.Logger) func() {
 	undo := otelzap.ReplaceGlobals(logger)
 	return undo
 }
+
+// Named returns the logger of a subsystem, eg: `postgres`, whose level may be set apart from the root's. Before New
+// is called it derives from the global logger instead.
+func Named(name string) *otelzap.Logger {
+	return named(name, false)
+}
+
+// Sampled is Named, for hot paths. Within each second only the first of each message are logged, and after that
+// only every so many, as configured by LogConfig.
+func Sampled(name string) *otelzap.Logger {
+	return named(name, true)
+}
+
+// LevelsHandler serves the levels of the loggers built by New, see Levels.ServeHTTP.
+func LevelsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stdMu.RLock()
+		reg := std
+		stdMu.RUnlock()
+
+		if reg == nil {
+			http.Error(w, "logging is not configured", http.StatusServiceUnavailable)
+			return
+		}
+
+		reg.levels.ServeHTTP(w, r)
+	})
+}
+
+func named(name string, sampled bool) *otelzap.Logger {
+	stdMu.RLock()
+	reg := std
+	stdMu.RUnlock()
+
+	if reg == nil {
+		return otelzap.New(otelzap.L().Logger.Named(name))
+	}
+
+	return reg.logger(name, sampled)
+}
+
+// logger builds, or returns the already built, logger of a subsystem, or the root logger for an empty name.
+func (r *registry) logger(name string, sampled bool) *otelzap.Logger {
+	key := name
+	if sampled {
+		key += "/sampled"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.loggers[key]; ok {
+		return l
+	}
+
+	level := r.levels.enabler(name)
+
+	z := r.base.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if sampled && r.initial > 0 {
+			core = zapcore.NewSamplerWithOptions(core, time.Second, r.initial, r.thereafter)
+		}
+
+		return &levelCore{Core: core, level: level}
+	}))
+
+	if name != "" {
+		z = z.Named(name)
+	}
+
+	// Entries logged with a context carry its trace ID, so they can be matched to the access log of the request.
+	l := otelzap.New(z, otelzap.WithTraceIDField(true))
+	r.loggers[key] = l
+
+	return l
+}
+
+// levelCore gates a core by a level of its own, which unlike zapcore.NewIncreaseLevelCore may be below the core's.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(e.Level) {
+		return ce
+	}
+
+	return c.Core.Check(e, ce)
+}
+
+// Levels are the root level and the levels of those subsystems set apart from it. Subsystems without a level of
+// their own follow the root's.
+type Levels struct {
+	root zap.AtomicLevel
+
+	mu        sync.RWMutex
+	subsystem map[string]zap.AtomicLevel
+}
+
+// NewLevels returns the root level and the levels of subsystems set apart from it.
+func NewLevels(root zapcore.Level, subsystems map[string]zapcore.Level) *Levels {
+	l := &Levels{root: zap.NewAtomicLevelAt(root), subsystem: make(map[string]zap.AtomicLevel, len(subsystems))}
+	for name, level := range subsystems {
+		l.subsystem[name] = zap.NewAtomicLevelAt(level)
+	}
+
+	return l
+}
+
+// Set sets the level of a subsystem, or of the root for an empty name.
+func (l *Levels) Set(name string, level zapcore.Level) {
+	if name == "" {
+		l.root.SetLevel(level)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lvl, ok := l.subsystem[name]; ok {
+		lvl.SetLevel(level)
+		return
+	}
+
+	l.subsystem[name] = zap.NewAtomicLevelAt(level)
+}
+
+// Reset makes a subsystem follow the root level again.
+func (l *Levels) Reset(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.subsystem, name)
+}
+
+// LevelState is the JSON form of Levels, also accepted to change a single level. An empty Level resets a
+// subsystem to the root's.
+type LevelState struct {
+	Level      string            `json:"level"`
+	Subsystem  string            `json:"subsystem,omitempty"`
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+}
+
+// State returns the current levels.
+func (l *Levels) State() LevelState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	state := LevelState{Level: l.root.String(), Subsystems: make(map[string]string, len(l.subsystem))}
+	for name, level := range l.subsystem {
+		state.Subsystems[name] = level.String()
+	}
+
+	return state
+}
+
+// ServeHTTP returns the levels on GET, and changes one on PUT, eg: `{"subsystem": "postgres", "level": "debug"}`.
+func (l *Levels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req LevelState
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+			return
+		}
+
+		if req.Level == "" && req.Subsystem != "" {
+			l.Reset(req.Subsystem)
+			break
+		}
+
+		level, err := zapcore.ParseLevel(req.Level)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		l.Set(req.Subsystem, level)
+		otelzap.L().Ctx(r.Context()).Info("Log level changed",
+			zap.String("subsystem", req.Subsystem),
+			zap.Stringer("level", level),
+		)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut}, ", "))
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	json.NewEncoder(w).Encode(l.State())
+}
+
+// enabler checks the level of a subsystem as it is when each entry is logged, so later changes apply to loggers
+// already built.
+func (l *Levels) enabler(name string) zapcore.LevelEnabler {
+	if name == "" {
+		return l.root
+	}
+
+	return zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		l.mu.RLock()
+		lvl, ok := l.subsystem[name]
+		l.mu.RUnlock()
+
+		if !ok {
+			return l.root.Enabled(level)
+		}
+
+		return lvl.Enabled(level)
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
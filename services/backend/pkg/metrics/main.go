@@ -0,0 +1,291 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Namespace prefixes every metric registered by the backend.
+const Namespace = "perspex"
+
+// Transaction outcomes
+const (
+	TxCommit   = "commit"
+	TxRollback = "rollback"
+	TxRetry    = "retry"
+)
+
+// HandlerHTTP labels the panics of plain HTTP handlers, whose paths are unbounded.
+const HandlerHTTP = "http"
+
+var (
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of database queries by table and operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"table", "operation"})
+
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Database queries that failed, by table and operation.",
+	}, []string{"table", "operation"})
+
+	txOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      "transactions_total",
+		Help:      "Transactions by outcome: commit, rollback or retry.",
+	}, []string{"outcome"})
+
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "rpc",
+		Name:      "requests_total",
+		Help:      "Handled RPCs by procedure and Connect code.",
+	}, []string{"procedure", "code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "rpc",
+		Name:      "duration_seconds",
+		Help:      "Latency of handled RPCs by procedure and Connect code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"procedure", "code"})
+
+	rpcInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "rpc",
+		Name:      "in_flight",
+		Help:      "RPCs currently being handled, by procedure.",
+	}, []string{"procedure"})
+
+	panics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "panics_total",
+		Help:      "Panics recovered from, by RPC procedure, or `http` for other handlers.",
+	}, []string{"handler"})
+)
+
+func init() {
+	prometheus.MustRegister(queryDuration, queryErrors, txOutcomes, rpcRequests, rpcDuration, rpcInFlight, panics)
+
+	// Outcomes are initialised so rates can be taken before the first of each is seen.
+	for _, outcome := range []string{TxCommit, TxRollback, TxRetry} {
+		txOutcomes.WithLabelValues(outcome)
+	}
+}
+
+// Tx counts a transaction outcome, one of TxCommit, TxRollback or TxRetry.
+func Tx(outcome string) {
+	txOutcomes.WithLabelValues(outcome).Inc()
+}
+
+// Panic counts a panic recovered from in handler, an RPC procedure or HandlerHTTP.
+func Panic(handler string) {
+	panics.WithLabelValues(handler).Inc()
+}
+
+// RegisterPool registers collectors for a `database/sql` bridge and the native pool beneath it, labelled with name,
+// eg: writer or reader.
+func RegisterPool(reg prometheus.Registerer, name string, db *sql.DB, pool *pgxpool.Pool) error {
+	if err := reg.Register(collectors.NewDBStatsCollector(db, name)); err != nil {
+		return err
+	}
+
+	return reg.Register(NewPoolCollector(name, pool))
+}
+
+// PoolCollector exports the connection and acquisition stats of a native pool.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquires        *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	canceled        *prometheus.Desc
+}
+
+// NewPoolCollector returns a collector for pool, labelled with name.
+func NewPoolCollector(name string, pool *pgxpool.Pool) *PoolCollector {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(Namespace, "db_pool", metric), help, nil, labels)
+	}
+
+	return &PoolCollector{
+		pool:            pool,
+		acquired:        desc("acquired_connections", "Connections currently acquired from the pool."),
+		idle:            desc("idle_connections", "Idle connections in the pool."),
+		total:           desc("connections", "Connections in the pool, including those being established."),
+		max:             desc("max_connections", "Maximum size of the pool."),
+		acquires:        desc("acquires_total", "Successful acquisitions from the pool."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Time spent acquiring connections from the pool."),
+		emptyAcquires:   desc("empty_acquires_total", "Acquisitions that waited because the pool was empty."),
+		canceled:        desc("canceled_acquires_total", "Acquisitions canceled by their context."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquires
+	ch <- c.canceled
+}
+
+// Collect implements prometheus.Collector.
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
+
+// tableRe finds the table a statement targets, as written by sqlboiler, eg: `SELECT "users".* FROM "users"`.
+var tableRe = regexp.MustCompile(`(?i)\b(?:from|into|update)\s+((?:"[^"]+"|\w+)(?:\.(?:"[^"]+"|\w+))?)`)
+
+// operations bounds the operation label, anything else is reported as `other`.
+var operations = map[string]bool{
+	"select": true, "insert": true, "update": true, "delete": true,
+	"begin": true, "commit": true, "rollback": true,
+}
+
+// Statement returns the table and operation of a query, for labelling. The table is empty when there is none, eg:
+// for `BEGIN`.
+func Statement(query string) (table string, operation string) {
+	query = strings.TrimSpace(query)
+
+	operation = "other"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		if op := strings.ToLower(strings.TrimRight(fields[0], "(;")); operations[op] {
+			operation = op
+		}
+	}
+
+	if m := tableRe.FindStringSubmatch(query); m != nil {
+		table = strings.ReplaceAll(m[1], `"`, "")
+	}
+
+	return table, operation
+}
+
+// Tracer times every query run on a connection, see pgx.ConnConfig.Tracer.
+type Tracer struct{}
+
+type traceKey struct{}
+
+type trace struct {
+	start     time.Time
+	table     string
+	operation string
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	table, operation := Statement(data.SQL)
+	return context.WithValue(ctx, traceKey{}, trace{start: time.Now(), table: table, operation: operation})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (Tracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	t, ok := ctx.Value(traceKey{}).(trace)
+	if !ok {
+		return
+	}
+
+	queryDuration.WithLabelValues(t.table, t.operation).Observe(time.Since(t.start).Seconds())
+
+	if data.Err != nil {
+		queryErrors.WithLabelValues(t.table, t.operation).Inc()
+	}
+}
+
+// Code returns the label for the outcome of an RPC, `ok` or the Connect code of err.
+func Code(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	return connect.CodeOf(err).String()
+}
+
+// Interceptor records rate, errors and duration of handled RPCs.
+type Interceptor struct{}
+
+// NewInterceptor returns an Interceptor recording into the default registry.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+func (i *Interceptor) observe(procedure string) func(err error) {
+	start := time.Now()
+	rpcInFlight.WithLabelValues(procedure).Inc()
+
+	return func(err error) {
+		code := Code(err)
+
+		rpcInFlight.WithLabelValues(procedure).Dec()
+		rpcRequests.WithLabelValues(procedure, code).Inc()
+		rpcDuration.WithLabelValues(procedure, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		done := i.observe(req.Spec().Procedure)
+
+		res, err := next(ctx, req)
+		done(err)
+
+		return res, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor, observing streams once they end.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		done := i.observe(conn.Spec().Procedure)
+
+		err := next(ctx, conn)
+		done(err)
+
+		return err
+	}
+}
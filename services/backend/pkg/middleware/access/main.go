@@ -0,0 +1,308 @@
+package access
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// Header carries the ID of a request, taken from the caller when it sends a valid one, and returned on every response.
+const Header = "X-Request-Id"
+
+// Attribute is the span attribute holding the request ID, so a trace can be found from the ID and vice versa.
+const Attribute = attribute.Key("http.request_id")
+
+// maxLength bounds the IDs taken from callers, which are logged as they are.
+const maxLength = 128
+
+type key int
+
+const requestIDKey key = iota
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty string if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Field returns the request ID carried by ctx as a log field, eg: `log.Ctx(ctx).Info("...", access.Field(ctx))`.
+func Field(ctx context.Context) zap.Field {
+	return zap.String("request_id", FromContext(ctx))
+}
+
+// NewID returns a random request ID.
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether a request ID sent by a caller may be used as it is. IDs are bounded in length and limited to
+// printable characters without spaces, so they can neither bloat nor forge log lines.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestID gives every request an ID, the caller's own if it sends a valid one in Header, and returns it on the
+// response. The ID is set on the request too, so the REST gateway forwards it to the RPC it proxies to.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !Valid(id) {
+			id = NewID()
+			r.Header.Set(Header, id)
+		}
+
+		w.Header().Set(Header, id)
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// log returns the access logger, whose level may be set apart to silence or sample it.
+func log() *otelzap.Logger {
+	return logger.Named("access")
+}
+
+// Log logs one line for each request served by next. It is meant for the routes that are not Connect handlers, eg:
+// metrics and the REST gateway, as those are logged by the Interceptor.
+func Log(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		ctx := r.Context()
+		fields := []zap.Field{
+			Field(ctx),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("protocol", r.Proto),
+			zap.String("peer", peer(r.RemoteAddr)),
+			zap.Int("status", rec.status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int64("request_size", r.ContentLength),
+			zap.Int64("response_size", rec.size),
+		}
+
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+		}
+
+		log().Ctx(ctx).Info("HTTP request", fields...)
+	})
+}
+
+// recorder keeps the status and size of a response.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+	wrote  bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status = status
+		r.wrote = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wrote = true
+
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Flush implements http.Flusher, for the streaming responses of the gateway.
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Interceptor logs one line for each handled RPC. It must come after the tracing interceptor, so the RPC's span can
+// be tagged with the request ID.
+type Interceptor struct{}
+
+// NewInterceptor returns an Interceptor logging to the `access` logger.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+// call is what is known of an RPC before it is handled.
+type call struct {
+	ctx       context.Context
+	start     time.Time
+	procedure string
+	peer      connect.Peer
+	identity  func() any
+}
+
+func (i *Interceptor) begin(ctx context.Context, spec connect.Spec, p connect.Peer, header http.Header) (context.Context, *call) {
+	id := FromContext(ctx)
+	if id == "" {
+		if id = header.Get(Header); !Valid(id) {
+			id = NewID()
+		}
+
+		ctx = NewContext(ctx, id)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(Attribute.String(id))
+
+	// The identity is attached by the authentication interceptor, which runs after this one.
+	ctx, identity := auth.Observe(ctx)
+
+	return ctx, &call{ctx: ctx, start: time.Now(), procedure: spec.Procedure, peer: p, identity: identity}
+}
+
+// end logs the call once handled, with the identity of the caller, if authenticated.
+func (c *call) end(err error, received int, sent int) {
+	fields := []zap.Field{
+		Field(c.ctx),
+		zap.String("procedure", c.procedure),
+		zap.String("protocol", c.peer.Protocol),
+		zap.String("peer", peer(c.peer.Addr)),
+		zap.String("code", metrics.Code(err)),
+		zap.Duration("latency", time.Since(c.start)),
+		zap.Int("request_size", received),
+		zap.Int("response_size", sent),
+	}
+
+	if identity := c.identity(); identity != nil {
+		fields = append(fields, zap.Any("identity", identity))
+	}
+
+	if sc := trace.SpanContextFromContext(c.ctx); sc.IsValid() {
+		fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+	}
+
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	log().Ctx(c.ctx).Info("RPC", fields...)
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		ctx, c := i.begin(ctx, req.Spec(), req.Peer(), req.Header())
+
+		res, err := next(ctx, req)
+
+		// Handlers return typed nil responses along with errors, so only the responses of successful calls are sized.
+		var sent int
+		if err == nil {
+			sent = size(res.Any())
+		}
+
+		c.end(err, size(req.Any()), sent)
+
+		return res, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor, logging streams once they end with the total size of the
+// messages received and sent.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, c := i.begin(ctx, conn.Spec(), conn.Peer(), conn.RequestHeader())
+
+		counted := &countingConn{StreamingHandlerConn: conn}
+
+		err := next(ctx, counted)
+		c.end(err, counted.received, counted.sent)
+
+		return err
+	}
+}
+
+// countingConn adds up the size of the messages of a stream.
+type countingConn struct {
+	connect.StreamingHandlerConn
+	received int
+	sent     int
+}
+
+func (c *countingConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil {
+		c.received += size(msg)
+	}
+
+	return err
+}
+
+func (c *countingConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if err == nil {
+		c.sent += size(msg)
+	}
+
+	return err
+}
+
+// size returns the encoded size of a message, or 0 for anything other than a proto message.
+func size(msg any) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+
+	return 0
+}
+
+// peer strips the port from an address, which identifies the connection rather than the caller.
+func peer(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
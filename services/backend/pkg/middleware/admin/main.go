@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// DebugHeader asks for the SQL run by a request to be logged, eg: `X-Debug-SQL: true`. It is only honoured for callers
+// authenticated as admins, or impersonating a user as one.
+const DebugHeader = "X-Debug-SQL"
+
+// Authorized reports whether header carries the admin token as a bearer token. Nobody is an admin without a token.
+func Authorized(token string, header http.Header) bool {
+	if token == "" {
+		return false
+	}
+
+	auth := header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) == 1
+}
+
+// Handler serves next to admins only.
+func Handler(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(token, r.Header) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Interceptor logs the SQL run by the requests of admins carrying DebugHeader. It must run after the authentication
+// interceptor, which identifies the caller.
+type Interceptor struct{}
+
+// NewInterceptor returns an Interceptor.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+// debug reports whether the SQL of a request should be logged. The admin is whoever really made the call, so that an
+// admin impersonating a user can debug the requests they make as them.
+func (i *Interceptor) debug(ctx context.Context, header http.Header) bool {
+	if on, _ := strconv.ParseBool(header.Get(DebugHeader)); !on {
+		return false
+	}
+
+	id, ok := auth.IdentityFromContext(ctx)
+
+	return ok && id.Actor().Admin
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient || !i.debug(ctx, req.Header()) {
+			return next(ctx, req)
+		}
+
+		ctx = postgres.WithDebug(ctx, zap.String("procedure", req.Spec().Procedure))
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if !i.debug(ctx, conn.RequestHeader()) {
+			return next(ctx, conn)
+		}
+
+		ctx = postgres.WithDebug(ctx, zap.String("procedure", conn.Spec().Procedure))
+
+		return next(ctx, conn)
+	}
+}
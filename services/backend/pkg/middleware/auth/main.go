@@ -2,15 +2,26 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 )
 
 type key int
 
-const identityKey key = iota
+const (
+	identityKey key = iota
+	observerKey
+	credentialsKey
+)
 
 // GetIdentity retrieves the authenticated identity, if any, from the request
 // context.
@@ -18,18 +29,134 @@ func GetIdentity(ctx context.Context) any {
 	return ctx.Value(identityKey)
 }
 
+// Identity is the identity attached by authentication functions that know who
+// the caller is, as understood by the authorization policy.
+type Identity struct {
+	// UserID is the caller's user, or 0 for callers that are not users.
+	UserID int64 `json:"user_id,omitempty"`
+	// Subject names the caller in logs, eg: the credential it presented.
+	Subject string `json:"subject,omitempty"`
+	// Admin grants access to every procedure that allows global admins.
+	Admin bool `json:"admin,omitempty"`
+	// Scopes restricts the caller to these services or procedures, eg:
+	// "users.v1.UserService" or "users.v1.UserService/RetrieveUser". An empty
+	// list restricts nothing.
+	Scopes []string `json:"scopes,omitempty"`
+	// Impersonator is the real caller when it impersonates this identity, for
+	// audit logs to record who actually acted.
+	Impersonator *Identity `json:"impersonator,omitempty"`
+}
+
+// Actor returns the identity that really made the call: the impersonator, if
+// any, or the identity itself.
+func (id *Identity) Actor() *Identity {
+	if id.Impersonator != nil {
+		return id.Impersonator
+	}
+	return id
+}
+
+// InScope reports whether the identity's scopes cover procedure, in the
+// "/package.Service/Method" form of [connect.Spec].
+func (id *Identity) InScope(procedure string) bool {
+	if len(id.Scopes) == 0 {
+		return true
+	}
+
+	procedure = strings.TrimPrefix(procedure, "/")
+	service, _, _ := strings.Cut(procedure, "/")
+
+	for _, scope := range id.Scopes {
+		scope = strings.TrimPrefix(scope, "/")
+		if scope == procedure || scope == service {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IdentityFromContext retrieves the authenticated identity from the request
+// context, if it is an [Identity].
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := GetIdentity(ctx).(*Identity)
+	return id, ok && id != nil
+}
+
 // WithoutIdentity strips the authenticated identity, if any, from the provided
 // context.
 func WithoutIdentity(ctx context.Context) context.Context {
 	return context.WithValue(ctx, identityKey, nil)
 }
 
+// Observe returns a copy of ctx in which the identity attached by an Interceptor
+// further down the chain is also reported to the returned function, for
+// interceptors that run before authentication, such as the access log.
+func Observe(ctx context.Context) (context.Context, func() any) {
+	var identity any
+	return context.WithValue(ctx, observerKey, &identity), func() any { return identity }
+}
+
+// WithIdentity returns a copy of ctx with identity attached in place of the
+// authenticated one, such as when a caller impersonates another.
+func WithIdentity(ctx context.Context, identity any) context.Context {
+	return attach(ctx, identity)
+}
+
+// attach attaches the identity to the context, reporting it to the observer, if
+// any.
+func attach(ctx context.Context, identity any) context.Context {
+	if observed, ok := ctx.Value(observerKey).(*any); ok {
+		*observed = identity
+	}
+	return context.WithValue(ctx, identityKey, identity)
+}
+
 // Errorf is a convenience function that returns an error coded with
 // [connect.CodeUnauthenticated].
 func Errorf(template string, args ...any) *connect.Error {
 	return connect.NewError(connect.CodeUnauthenticated, fmt.Errorf(template, args...))
 }
 
+// ErrNoCredentials is returned by an [Authenticator] when the request carries no
+// credentials it understands, so that [First] may try the next one.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator is an authentication function for [New], returning the
+// [Identity] of the caller.
+type Authenticator func(context.Context, *Request) (*Identity, error)
+
+// First combines authenticators into an authentication function for [New],
+// trying each in turn until one recognizes the credentials of the request.
+// Requests without any credentials are let through without an identity, for
+// the authorization policy to decide on; credentials that are recognized but
+// invalid are rejected.
+func First(authenticators ...Authenticator) func(context.Context, *Request) (any, error) {
+	return func(ctx context.Context, req *Request) (any, error) {
+		for _, authenticate := range authenticators {
+			identity, err := authenticate(ctx, req)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return identity, nil
+		}
+		return nil, nil
+	}
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header, if any.
+func BearerToken(header http.Header) (string, bool) {
+	scheme, token, ok := strings.Cut(header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
 // Request describes a single RPC invocation.
 type Request struct {
 	Spec   connect.Spec
@@ -70,11 +197,14 @@ func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
 		if err != nil {
 			return nil, err
 		}
-		return next(context.WithValue(ctx, identityKey, identity), req)
+		ctx = context.WithValue(ctx, credentialsKey, req.Header().Get("Authorization"))
+		return next(attach(ctx, identity), req)
 	}
 }
 
-// WrapStreamingClient implements connect.Interceptor with a no-op.
+// WrapStreamingClient implements connect.Interceptor with a no-op, as
+// Interceptor only authenticates the calls handled by a server. See
+// [ClientInterceptor] for attaching credentials to outgoing calls.
 func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
 	return next
 }
@@ -90,6 +220,221 @@ func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) co
 		if err != nil {
 			return err
 		}
-		return next(context.WithValue(ctx, identityKey, identity), conn)
+		ctx = context.WithValue(ctx, credentialsKey, conn.RequestHeader().Get("Authorization"))
+		return next(attach(ctx, identity), conn)
+	}
+}
+
+// Credentials supplies the Authorization header of outgoing calls, eg:
+// "Bearer <token>". An empty header sends the call without credentials.
+//
+// Credentials must be safe to call concurrently.
+type Credentials func(ctx context.Context) (string, error)
+
+// Static returns Credentials presenting a fixed bearer token, such as an API
+// key.
+func Static(token string) Credentials {
+	header := "Bearer " + token
+	return func(context.Context) (string, error) {
+		return header, nil
+	}
+}
+
+// Forward returns Credentials presenting the credentials of the incoming call
+// being handled, as seen by an [Interceptor], so downstream services act on
+// behalf of the same caller. Only forward credentials to trusted services.
+func Forward() Credentials {
+	return func(ctx context.Context) (string, error) {
+		header, _ := ctx.Value(credentialsKey).(string)
+		return header, nil
+	}
+}
+
+// ClientCredentials configures the OAuth2 client credentials grant of RFC 6749,
+// section 4.4.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Client sends the token requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// expiryDelta is how long before they expire access tokens are refreshed, so
+// they don't expire in flight.
+const expiryDelta = 10 * time.Second
+
+// OAuth2 returns Credentials presenting access tokens obtained with the client
+// credentials grant. Tokens are cached and refreshed shortly before they expire.
+func OAuth2(cfg ClientCredentials) Credentials {
+	src := &tokenSource{cfg: cfg}
+	return src.authorization
+}
+
+// tokenSource caches the access token of a client credentials grant.
+type tokenSource struct {
+	cfg ClientCredentials
+
+	mu     sync.Mutex
+	header string
+	expiry time.Time
+}
+
+// authorization returns the cached token, fetching a new one if it is missing
+// or about to expire. Concurrent callers wait for a single fetch.
+func (s *tokenSource) authorization(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.header != "" && (s.expiry.IsZero() || time.Now().Add(expiryDelta).Before(s.expiry)) {
+		return s.header, nil
+	}
+
+	header, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("fetching oauth2 token: %w", err))
+	}
+
+	s.header, s.expiry = header, expiry
+	return header, nil
+}
+
+func (s *tokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+
+	client := s.cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", time.Time{}, fmt.Errorf("token endpoint returned %s: %.256s", res.Status, body)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("decoding token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", time.Time{}, errors.New("token response has no access_token")
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "Bearer") {
+		tokenType = "Bearer"
+	}
+
+	var expiry time.Time
+	if token.ExpiresIn > 0 {
+		expiry = start.Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return tokenType + " " + token.AccessToken, expiry, nil
+}
+
+// ClientInterceptor is a client-side authentication interceptor, attaching
+// credentials to outgoing unary and streaming calls, eg:
+//
+//	usersconnect.NewUserServiceClient(http.DefaultClient, url,
+//		connect.WithInterceptors(auth.NewClient(auth.Static(key))))
+type ClientInterceptor struct {
+	credentials Credentials
+}
+
+// NewClient constructs a new ClientInterceptor presenting the supplied
+// credentials. Calls fail without being sent if the credentials can't be
+// obtained.
+func NewClient(credentials Credentials) *ClientInterceptor {
+	return &ClientInterceptor{credentials}
+}
+
+// authorize sets the Authorization header of an outgoing call.
+func (i *ClientInterceptor) authorize(ctx context.Context, header http.Header) error {
+	authorization, err := i.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	if authorization != "" {
+		header.Set("Authorization", authorization)
+	}
+	return nil
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *ClientInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.authorize(ctx, req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *ClientInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		if err := i.authorize(ctx, conn.RequestHeader()); err != nil {
+			return &failedConn{StreamingClientConn: conn, err: err}
+		}
+		return conn
 	}
 }
+
+// WrapStreamingHandler implements connect.Interceptor with a no-op.
+func (i *ClientInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// failedConn is a stream that fails without being sent, as its credentials
+// couldn't be obtained.
+type failedConn struct {
+	connect.StreamingClientConn
+	err error
+}
+
+func (c *failedConn) Send(any) error {
+	return c.err
+}
+
+func (c *failedConn) CloseRequest() error {
+	return c.err
+}
+
+func (c *failedConn) Receive(any) error {
+	return c.err
+}
+
+func (c *failedConn) CloseResponse() error {
+	return nil
+}
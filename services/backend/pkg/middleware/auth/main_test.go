@@ -0,0 +1,271 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// issuer is an OAuth2 token endpoint granting numbered tokens to the client credentials it expects.
+type issuer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	status    int
+	expiresIn int64
+	issued    int
+	scope     string
+}
+
+func newIssuer(t *testing.T, expiresIn int64) *issuer {
+	t.Helper()
+
+	i := &issuer{status: http.StatusOK, expiresIn: expiresIn}
+	i.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		id, secret, _ := r.BasicAuth()
+		if r.Method != http.MethodPost || r.FormValue("grant_type") != "client_credentials" || id != "client" || secret != "s3cret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		if i.status != http.StatusOK {
+			http.Error(w, `{"error":"temporarily_unavailable"}`, i.status)
+			return
+		}
+
+		i.issued++
+		i.scope = r.FormValue("scope")
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", i.issued),
+			"token_type":   "bearer",
+			"expires_in":   i.expiresIn,
+		})
+	}))
+
+	t.Cleanup(i.Close)
+
+	return i
+}
+
+func (i *issuer) credentials() auth.Credentials {
+	return auth.OAuth2(auth.ClientCredentials{
+		TokenURL:     i.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cret",
+		Scopes:       []string{"users.read", "users.write"},
+		Client:       i.Client(),
+	})
+}
+
+func (i *issuer) requested() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.scope
+}
+
+func (i *issuer) fail(status int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.status = status
+}
+
+// recorder is a users service recording the Authorization header of the calls it receives, which are otherwise
+// unimplemented, unless it forwards them downstream.
+type recorder struct {
+	usersconnect.UnimplementedUserServiceHandler
+
+	mu         sync.Mutex
+	headers    []string
+	downstream usersconnect.UserServiceClient
+}
+
+func (r *recorder) RetrieveUser(ctx context.Context, req *connect.Request[users.RetrieveUserRequest]) (*connect.Response[users.RetrieveUserResponse], error) {
+	if r.downstream == nil {
+		return r.UnimplementedUserServiceHandler.RetrieveUser(ctx, req)
+	}
+
+	return r.downstream.RetrieveUser(ctx, connect.NewRequest(req.Msg))
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string{}, r.headers...)
+}
+
+// serve serves r behind an Interceptor, returning a client of it presenting credentials.
+func serve(t *testing.T, r *recorder, credentials auth.Credentials) usersconnect.UserServiceClient {
+	t.Helper()
+
+	record := auth.New(func(_ context.Context, req *auth.Request) (any, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.headers = append(r.headers, req.Header.Get("Authorization"))
+
+		return nil, nil
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(usersconnect.NewUserServiceHandler(r, connect.WithInterceptors(record)))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return usersconnect.NewUserServiceClient(server.Client(), server.URL, connect.WithInterceptors(auth.NewClient(credentials)))
+}
+
+func code(err error) connect.Code {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code()
+	}
+
+	return 0
+}
+
+// call makes a unary call, which must reach the server.
+func call(t *testing.T, client usersconnect.UserServiceClient) {
+	t.Helper()
+
+	if _, err := client.RetrieveUser(context.Background(), connect.NewRequest(&users.RetrieveUserRequest{Id: 1})); code(err) != connect.CodeUnimplemented {
+		t.Fatalf("expected the call to reach the server, got %v", err)
+	}
+}
+
+// watch opens a stream, returning the error it ends with.
+func watch(client usersconnect.UserServiceClient) error {
+	stream, err := client.WatchUsers(context.Background(), connect.NewRequest(&users.WatchUsersRequest{}))
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close()
+
+	for stream.Receive() {
+	}
+
+	return stream.Err()
+}
+
+func TestOAuth2(t *testing.T) {
+	cases := map[string]struct {
+		expiresIn int64
+		calls     int
+		want      []string
+	}{
+		"Cached":                {expiresIn: 3600, calls: 3, want: []string{"Bearer token-1", "Bearer token-1", "Bearer token-1"}},
+		"NeverExpires":          {calls: 2, want: []string{"Bearer token-1", "Bearer token-1"}},
+		"RefreshedBeforeExpiry": {expiresIn: 5, calls: 3, want: []string{"Bearer token-1", "Bearer token-2", "Bearer token-3"}},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			tokens := newIssuer(t, c.expiresIn)
+			r := &recorder{}
+			client := serve(t, r, tokens.credentials())
+
+			for n := 0; n < c.calls; n++ {
+				call(t, client)
+			}
+
+			if got := r.received(); strings.Join(got, ",") != strings.Join(c.want, ",") {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+
+			if scope := tokens.requested(); scope != "users.read users.write" {
+				t.Fatalf("expected the scopes to be requested, got %q", scope)
+			}
+		})
+	}
+}
+
+func TestOAuth2Failures(t *testing.T) {
+	tokens := newIssuer(t, 5)
+	r := &recorder{}
+	client := serve(t, r, tokens.credentials())
+
+	call(t, client)
+
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusBadRequest} {
+		tokens.fail(status)
+
+		_, err := client.RetrieveUser(context.Background(), connect.NewRequest(&users.RetrieveUserRequest{Id: 1}))
+		if code(err) != connect.CodeUnauthenticated || !strings.Contains(err.Error(), fmt.Sprint(status)) {
+			t.Fatalf("%d: expected the token endpoint's failure, got %v", status, err)
+		}
+
+		if err := watch(client); code(err) != connect.CodeUnauthenticated {
+			t.Fatalf("%d: expected the stream to fail with the token endpoint, got %v", status, err)
+		}
+	}
+
+	if got := r.received(); len(got) != 1 {
+		t.Fatalf("expected the calls without credentials not to be sent, got %v", got)
+	}
+
+	tokens.fail(http.StatusOK)
+	call(t, client)
+
+	if got := r.received(); got[len(got)-1] != "Bearer token-2" {
+		t.Fatalf("expected a new token once the endpoint recovers, got %v", got)
+	}
+}
+
+func TestClientInterceptorStreams(t *testing.T) {
+	r := &recorder{}
+
+	if err := watch(serve(t, r, auth.Static("pk_key"))); code(err) != connect.CodeUnimplemented {
+		t.Fatalf("expected the stream to reach the server, got %v", err)
+	}
+
+	if got := r.received(); len(got) != 1 || got[0] != "Bearer pk_key" {
+		t.Fatalf("expected the stream to carry the credentials, got %v", got)
+	}
+}
+
+func TestForward(t *testing.T) {
+	downstream := &recorder{}
+	upstream := &recorder{downstream: serve(t, downstream, auth.Forward())}
+
+	cases := map[string]string{
+		"Credentials":   "Bearer caller",
+		"NoCredentials": "",
+	}
+
+	for name, header := range cases {
+		header := header
+
+		t.Run(name, func(t *testing.T) {
+			var credentials auth.Credentials = func(context.Context) (string, error) { return header, nil }
+
+			call(t, serve(t, upstream, credentials))
+
+			if got := downstream.received(); got[len(got)-1] != header {
+				t.Fatalf("expected %q to be forwarded, got %v", header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package impersonate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/authz"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+)
+
+// Header is sent by privileged callers to act as the user with the ID it holds.
+const Header = "X-Impersonate-User"
+
+// Response headers flagging the calls served to an impersonator.
+const (
+	UserHeader  = "X-Impersonated-User"
+	ActorHeader = "X-Impersonated-By"
+)
+
+// Interceptor lets callers allowed by the policy act as another user, by attaching the impersonated user as the
+// identity with the caller as its impersonator. It must come after authentication and before authorization, so the
+// procedure is authorized for the impersonated user. Every attempt is logged, with the real caller.
+type Interceptor struct {
+	policy *authz.Policy
+}
+
+// NewInterceptor returns an Interceptor restricting impersonation to the callers allowed by policy, see
+// authz.Policy.Impersonate.
+func NewInterceptor(policy *authz.Policy) *Interceptor {
+	return &Interceptor{policy: policy}
+}
+
+// impersonate returns ctx with the identity of the user named by the Header, if it is set, and the response headers
+// flagging the impersonation.
+func (i *Interceptor) impersonate(ctx context.Context, procedure string, header http.Header) (context.Context, http.Header, error) {
+	value := header.Get(Header)
+	if value == "" {
+		return ctx, nil, nil
+	}
+
+	userID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return ctx, nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s must be a user ID", Header))
+	}
+
+	actor, _ := auth.IdentityFromContext(ctx)
+	decision := i.policy.Impersonate(ctx, actor, userID)
+
+	fields := []zap.Field{
+		access.Field(ctx),
+		zap.String("procedure", procedure),
+		zap.Int64("impersonated_user_id", userID),
+		zap.Bool("allowed", decision.Allowed),
+	}
+
+	if actor != nil {
+		fields = append(fields, zap.Int64("user_id", actor.UserID), zap.String("subject", actor.Subject))
+	}
+
+	if decision.Allowed {
+		fields = append(fields, zap.String("rule", string(decision.Rule)))
+	} else {
+		fields = append(fields, zap.String("reason", decision.Reason))
+	}
+
+	logger.Named("impersonate").Ctx(ctx).Info("Impersonation", fields...)
+
+	switch {
+	case decision.Allowed:
+	case actor == nil:
+		return ctx, nil, auth.Errorf("impersonation requires authentication")
+	default:
+		return ctx, nil, connect.NewError(connect.CodePermissionDenied, errors.New("impersonation is not allowed"))
+	}
+
+	// The impersonator's scopes still apply, but not its admin rights.
+	identity := &auth.Identity{
+		UserID:       userID,
+		Subject:      "user:" + value,
+		Scopes:       actor.Scopes,
+		Impersonator: actor,
+	}
+
+	flags := http.Header{}
+	flags.Set(UserHeader, value)
+	flags.Set(ActorHeader, actor.Subject)
+
+	return auth.WithIdentity(ctx, identity), flags, nil
+}
+
+// flag copies the headers flagging an impersonation onto the response.
+func flag(dst http.Header, flags http.Header) {
+	for k, v := range flags {
+		dst[k] = v
+	}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		ctx, flags, err := i.impersonate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := next(ctx, req)
+
+		var connectErr *connect.Error
+		switch {
+		case flags == nil:
+		case err == nil:
+			flag(res.Header(), flags)
+		case errors.As(err, &connectErr):
+			flag(connectErr.Meta(), flags)
+		}
+
+		return res, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, flags, err := i.impersonate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+
+		flag(conn.ResponseHeader(), flags)
+
+		return next(ctx, conn)
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"net/http"
 
 	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
 	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
-	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
 )
 
 type key int
@@ -58,46 +60,55 @@ func New(f func(context.Context, *Request) (*sql.DB, *sql.TxOptions, error)) *In
 	return &Interceptor{f}
 }
 
-// WrapUnary implements connect.Interceptor.
+// WrapUnary implements connect.Interceptor. Requests that cannot get a transaction fail as unavailable, without
+// reaching the handler. The transaction is committed if the handler succeeds, and the request fails if the commit
+// does, as its writes are lost.
 func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
-	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+	return func(ctx context.Context, req connect.AnyRequest) (res connect.AnyResponse, err error) {
 		conn, settings, err := i.connection(ctx, &Request{
 			Spec:   req.Spec(),
 			Peer:   req.Peer(),
 			Header: req.Header(),
 		})
+		if err != nil {
+			return nil, connect.NewError(connect.CodeUnavailable, err)
+		}
 
+		key, err := postgres.WhichConnection(ctx, settings)
 		if err != nil {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithCancel(ctx)
-			cancel()
+			return nil, connect.NewError(connect.CodeUnavailable, err)
 		}
 
 		tx, err := postgres.BeginTx(ctx, conn, settings)
 		if err != nil {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithCancel(ctx)
-			cancel()
+			return nil, connect.NewError(connect.CodeUnavailable, err)
 		}
 
-		key, err := postgres.WhichConnection(ctx, settings)
-		if err != nil {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithCancel(ctx)
-			cancel()
+		log := logger.Sampled("postgres")
+
+		rollback := func() {
+			log.Ctx(ctx).Debug("Rolling back")
+			if err := tx.Rollback(); err != nil {
+				log.Ctx(ctx).Info("Rollback failed", zap.Error(err))
+			}
 		}
 
 		defer func() {
-			if p := recover(); err != nil || p != nil {
-				otelzap.L().Info("recovering, rollingback")
-				if err := tx.Rollback(); err != nil {
-					otelzap.L().Info(err.Error())
-				}
-			} else {
-				otelzap.L().Info("committing")
-				if err := tx.Commit(); err != nil {
-					otelzap.L().Info(err.Error())
-				}
+			// Panics are passed on once rolled back, to be turned into errors by the recovery interceptor.
+			if p := recover(); p != nil {
+				rollback()
+				panic(p)
+			}
+
+			if err != nil {
+				rollback()
+				return
+			}
+
+			log.Ctx(ctx).Debug("Committing")
+			if cerr := tx.Commit(); cerr != nil {
+				log.Ctx(ctx).Info("Commit failed", zap.Error(cerr))
+				res, err = nil, connect.NewError(connect.CodeAborted, cerr)
 			}
 		}()
 
@@ -110,10 +121,11 @@ func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) conn
 	return next
 }
 
-// WrapStreamingHandler implements connect.Interceptor.
+// WrapStreamingHandler implements connect.Interceptor. Streams may outlive any transaction, so none is held for them,
+// and repositories begin their own for each query made while streaming.
 func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
 	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
-		stream, opts, err := i.connection(ctx, &Request{
+		_, opts, err := i.connection(ctx, &Request{
 			Spec:   conn.Spec(),
 			Peer:   conn.Peer(),
 			Header: conn.RequestHeader(),
@@ -122,17 +134,27 @@ func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) co
 			return err
 		}
 
-		key, err := postgres.WhichConnection(ctx, opts)
-		if err != nil {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithCancel(ctx)
-			cancel()
+		if _, err := postgres.WhichConnection(ctx, opts); err != nil {
+			return connect.NewError(connect.CodeInternal, err)
 		}
 
-		return next(context.WithValue(ctx, key, stream), conn)
+		return next(ctx, conn)
 	}
 }
 
 func Wrap(ctx context.Context, db *sql.DB, txOpts *sql.TxOptions) (*sql.DB, *sql.TxOptions, error) {
 	return db, txOpts, nil
 }
+
+// Interceptors returns the reader and writer transaction interceptors for the supplied connections.
+func Interceptors(dbs *postgres.DB) []connect.Interceptor {
+	reader := func(ctx context.Context, req *Request) (*sql.DB, *sql.TxOptions, error) {
+		return Wrap(ctx, dbs.Reader, postgres.ReadOnlyTxOpts)
+	}
+
+	writer := func(ctx context.Context, req *Request) (*sql.DB, *sql.TxOptions, error) {
+		return Wrap(ctx, dbs.Writer, postgres.StdTxOpts)
+	}
+
+	return []connect.Interceptor{New(reader), New(writer)}
+}
@@ -0,0 +1,64 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	connect "github.com/bufbuild/connect-go"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	transaction "github.com/jmandel1027/perspex/services/backend/pkg/middleware/postgres"
+)
+
+func unary(t *testing.T, f func(context.Context, *transaction.Request) (*sql.DB, *sql.TxOptions, error)) (bool, error) {
+	t.Helper()
+
+	var called bool
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		called = true
+		return connect.NewResponse(&struct{}{}), nil
+	}
+
+	_, err := transaction.New(f).WrapUnary(next)(context.Background(), connect.NewRequest(&struct{}{}))
+
+	return called, err
+}
+
+func TestUnavailable(t *testing.T) {
+	unreachable, err := sql.Open("pgx", "postgres://perspex@127.0.0.1:1/perspex?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+
+	t.Cleanup(func() { unreachable.Close() })
+
+	cases := map[string]func(context.Context, *transaction.Request) (*sql.DB, *sql.TxOptions, error){
+		"Connection": func(context.Context, *transaction.Request) (*sql.DB, *sql.TxOptions, error) {
+			return nil, nil, errors.New("no connection")
+		},
+		"Options": func(context.Context, *transaction.Request) (*sql.DB, *sql.TxOptions, error) {
+			return unreachable, nil, nil
+		},
+		"Begin": func(context.Context, *transaction.Request) (*sql.DB, *sql.TxOptions, error) {
+			return unreachable, postgres.StdTxOpts, nil
+		},
+	}
+
+	for name, connection := range cases {
+		connection := connection
+
+		t.Run(name, func(t *testing.T) {
+			called, err := unary(t, connection)
+			if connect.CodeOf(err) != connect.CodeUnavailable {
+				t.Fatalf("expected unavailable, got %v", err)
+			}
+
+			if called {
+				t.Fatal("expected the handler not to be called without a transaction")
+			}
+		})
+	}
+}
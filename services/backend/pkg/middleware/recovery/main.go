@@ -0,0 +1,103 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+)
+
+// Errorf returns the error sent in place of a panic, which only carries the ID it was logged under, so nothing of the
+// handler's state leaks to the caller.
+func Errorf(id string) *connect.Error {
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("internal error, reference %s", id))
+}
+
+// recovered logs a recovered panic with the stack it was raised from, and returns the ID it was logged under.
+func recovered(ctx context.Context, handler string, p any) string {
+	id := access.NewID()
+
+	metrics.Panic(handler)
+	logger.Named("recovery").Ctx(ctx).Error("Recovered from panic",
+		zap.String("error_id", id),
+		zap.String("handler", handler),
+		zap.Any("panic", p),
+		zap.ByteString("stack", debug.Stack()),
+		access.Field(ctx),
+	)
+
+	return id
+}
+
+// Handler recovers from panics in next, responding with an Internal Server Error if nothing was written yet.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+
+			// Aborted handlers are left to the server, which closes the connection without logging.
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			id := recovered(r.Context(), metrics.HandlerHTTP, p)
+			http.Error(w, Errorf(id).Message(), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Interceptor turns panics in handlers into Internal errors. It must come after the interceptors observing the
+// outcome of RPCs, so they see the error, and before those holding transactions, so they roll back first.
+type Interceptor struct{}
+
+// NewInterceptor returns an Interceptor.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (res connect.AnyResponse, err error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				res, err = nil, Errorf(recovered(ctx, req.Spec().Procedure, p))
+			}
+		}()
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor with a no-op.
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = Errorf(recovered(ctx, conn.Spec().Procedure, p))
+			}
+		}()
+
+		return next(ctx, conn)
+	}
+}
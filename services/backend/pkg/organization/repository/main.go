@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	events "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+)
+
+// Roles of organization members
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+// ErrInvalidRole is returned when adding a member with a role other than RoleMember or RoleAdmin.
+var ErrInvalidRole = errors.New("role must be member or admin")
+
+// Membership is the role of a user in an organization, as recorded in audit events.
+type Membership struct {
+	OrganizationID int64  `json:"organization_id"`
+	UserID         int64  `json:"user_id"`
+	Role           string `json:"role"`
+}
+
+// Change returns the audit event of the membership changing from before to after, either of which is nil when there
+// is no membership.
+func Change(ctx context.Context, before *Membership, after *Membership) (*audit.Event, error) {
+	m := after
+	if m == nil {
+		m = before
+	}
+
+	return audit.NewEvent(ctx, audit.EntityOrganizationMember, fmt.Sprintf("%d:%d", m.OrganizationID, m.UserID), before, after)
+}
+
+// DomainEvent returns the domain event of a member joining with after's role, when before is nil, or changing role
+// from before's. It is nil when the role is unchanged.
+func DomainEvent(ctx context.Context, before *Membership, after *Membership) (*outbox.Event, error) {
+	var msg proto.Message
+
+	switch {
+	case before == nil:
+		msg = &events.OrganizationMemberAdded{OrganizationId: after.OrganizationID, UserId: after.UserID, Role: after.Role}
+	case before.Role != after.Role:
+		msg = &events.OrganizationMemberRoleChanged{
+			OrganizationId: after.OrganizationID,
+			UserId:         after.UserID,
+			PreviousRole:   before.Role,
+			Role:           after.Role,
+		}
+	default:
+		return nil, nil
+	}
+
+	return outbox.NewEvent(ctx, outbox.AggregateOrganizationMember, fmt.Sprintf("%d:%d", after.OrganizationID, after.UserID), msg)
+}
+
+// IMembershipRepository is the interface for organization memberships, satisfying authz.Memberships.
+type IMembershipRepository interface {
+	AddMember(ctx context.Context, orgID int64, userID int64, role string) error
+	Administers(ctx context.Context, adminID int64, userID int64) (bool, error)
+	Oversees(ctx context.Context, adminID int64, userID int64) (bool, error)
+	Role(ctx context.Context, orgID int64, userID int64) (string, error)
+	Organizations(ctx context.Context, userID int64) ([]int64, error)
+}
+
+// MembershipRepository --
+type MembershipRepository struct {
+	dbs    *postgres.DB
+	log    *otelzap.Logger
+	audit  audit.Recorder
+	outbox outbox.Writer
+}
+
+// NewMembershipRepository Creates a new Membership repo instance
+func NewMembershipRepository(dbs *postgres.DB, log *otelzap.Logger, rec audit.Recorder, out outbox.Writer) *MembershipRepository {
+	return &MembershipRepository{dbs: dbs, log: log, audit: rec, outbox: out}
+}
+
+// AddMember adds a user to an organization with a role, or changes the role of a member.
+func (repo *MembershipRepository) AddMember(ctx context.Context, orgID int64, userID int64, role string) error {
+	if role != RoleMember && role != RoleAdmin {
+		return ErrInvalidRole
+	}
+
+	return repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		var before *Membership
+
+		previous, err := models.OrganizationMembers(
+			models.OrganizationMemberWhere.OrganizationID.EQ(orgID),
+			models.OrganizationMemberWhere.UserID.EQ(userID),
+			qm.For("UPDATE"),
+		).One(ctx, tx)
+
+		switch {
+		case err == nil:
+			before = &Membership{OrganizationID: orgID, UserID: userID, Role: previous.Role}
+		case err != sql.ErrNoRows:
+			warning := fmt.Sprintf("Couldn't add organization member: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't add organization member: %w", err)
+		}
+
+		member := &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: role}
+		conflict := []string{models.OrganizationMemberColumns.OrganizationID, models.OrganizationMemberColumns.UserID}
+		update := boil.Whitelist(models.OrganizationMemberColumns.Role, models.OrganizationMemberColumns.UpdatedAt)
+
+		if err := member.Upsert(ctx, tx, true, conflict, update, boil.Infer()); err != nil {
+			warning := fmt.Sprintf("Couldn't add organization member: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't add organization member: %w", err)
+		}
+
+		after := &Membership{OrganizationID: orgID, UserID: userID, Role: role}
+
+		e, err := Change(ctx, before, after)
+		if err == nil {
+			err = repo.audit.Record(ctx, tx, e)
+		}
+
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't audit organization member: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't audit organization member: %w", err)
+		}
+
+		published, err := DomainEvent(ctx, before, after)
+		if err == nil && published != nil {
+			err = repo.outbox.Write(ctx, tx, published)
+		}
+
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't publish organization member event: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't publish organization member event: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Administers reports whether adminID is an admin of an organization userID is a member of.
+func (repo *MembershipRepository) Administers(ctx context.Context, adminID int64, userID int64) (ok bool, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		ok, err = models.OrganizationMembers(
+			qm.InnerJoin("organization_members member ON member.organization_id = organization_members.organization_id"),
+			models.OrganizationMemberWhere.UserID.EQ(adminID),
+			models.OrganizationMemberWhere.Role.EQ(RoleAdmin),
+			qm.Where("member.user_id = ?", userID),
+		).Exists(ctx, tx)
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't check organization admin: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't check organization admin: %w", err)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Oversees reports whether adminID administers every organization userID administers, if any. It looks for an
+// organization the user administers and the admin does not.
+func (repo *MembershipRepository) Oversees(ctx context.Context, adminID int64, userID int64) (ok bool, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		overlooked, err := models.OrganizationMembers(
+			qm.LeftOuterJoin(
+				"organization_members admin ON admin.organization_id = organization_members.organization_id AND admin.user_id = ? AND admin.role = ?",
+				adminID, RoleAdmin,
+			),
+			models.OrganizationMemberWhere.UserID.EQ(userID),
+			models.OrganizationMemberWhere.Role.EQ(RoleAdmin),
+			qm.Where("admin.user_id IS NULL"),
+		).Exists(ctx, tx)
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't check organization admin: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't check organization admin: %w", err)
+		}
+
+		ok = !overlooked
+		return nil
+	})
+
+	return
+}
+
+// Role finds the role of userID in an organization, empty when they are not a member.
+func (repo *MembershipRepository) Role(ctx context.Context, orgID int64, userID int64) (res string, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		member, err := models.FindOrganizationMember(ctx, tx, orgID, userID)
+		if err != nil && err != sql.ErrNoRows {
+			warning := fmt.Sprintf("Couldn't find organization role: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't find organization role: %w", err)
+		}
+
+		if member != nil {
+			res = member.Role
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Organizations finds the IDs of the organizations userID is a member of, in ascending order.
+func (repo *MembershipRepository) Organizations(ctx context.Context, userID int64) (res []int64, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		members, err := models.OrganizationMembers(
+			models.OrganizationMemberWhere.UserID.EQ(userID),
+			qm.OrderBy(models.OrganizationMemberColumns.OrganizationID),
+		).All(ctx, tx)
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't find organizations of member: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't find organizations of member: %w", err)
+		}
+
+		for _, m := range members {
+			res = append(res, m.OrganizationID)
+		}
+
+		return nil
+	})
+
+	return
+}
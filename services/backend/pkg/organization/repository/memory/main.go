@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+)
+
+// MembershipRepository is an in-memory repository.IMembershipRepository, for running without a database.
+type MembershipRepository struct {
+	mu      sync.RWMutex
+	audit   audit.Recorder
+	outbox  outbox.Writer
+	members map[int64]map[int64]string
+}
+
+var _ repository.IMembershipRepository = (*MembershipRepository)(nil)
+
+// NewMembershipRepository Creates a new, empty in-memory Membership repo instance
+func NewMembershipRepository(rec audit.Recorder, out outbox.Writer) *MembershipRepository {
+	return &MembershipRepository{audit: rec, outbox: out, members: make(map[int64]map[int64]string)}
+}
+
+// AddMember adds a user to an organization with a role, or changes the role of a member.
+func (repo *MembershipRepository) AddMember(ctx context.Context, orgID int64, userID int64, role string) error {
+	if role != repository.RoleMember && role != repository.RoleAdmin {
+		return repository.ErrInvalidRole
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var before *repository.Membership
+	if previous, ok := repo.members[orgID][userID]; ok {
+		before = &repository.Membership{OrganizationID: orgID, UserID: userID, Role: previous}
+	}
+
+	after := &repository.Membership{OrganizationID: orgID, UserID: userID, Role: role}
+
+	e, err := repository.Change(ctx, before, after)
+	if err == nil {
+		err = repo.audit.Record(ctx, nil, e)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Couldn't audit organization member: %w", err)
+	}
+
+	published, err := repository.DomainEvent(ctx, before, after)
+	if err == nil && published != nil {
+		err = repo.outbox.Write(ctx, nil, published)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Couldn't publish organization member event: %w", err)
+	}
+
+	if repo.members[orgID] == nil {
+		repo.members[orgID] = make(map[int64]string)
+	}
+
+	repo.members[orgID][userID] = role
+
+	return nil
+}
+
+// Administers reports whether adminID is an admin of an organization userID is a member of.
+func (repo *MembershipRepository) Administers(ctx context.Context, adminID int64, userID int64) (bool, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, members := range repo.members {
+		if _, ok := members[userID]; ok && members[adminID] == repository.RoleAdmin {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Oversees reports whether adminID administers every organization userID administers, if any.
+func (repo *MembershipRepository) Oversees(ctx context.Context, adminID int64, userID int64) (bool, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, members := range repo.members {
+		if members[userID] == repository.RoleAdmin && members[adminID] != repository.RoleAdmin {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Role finds the role of userID in an organization, empty when they are not a member.
+func (repo *MembershipRepository) Role(ctx context.Context, orgID int64, userID int64) (string, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return repo.members[orgID][userID], nil
+}
+
+// Organizations finds the IDs of the organizations userID is a member of, in ascending order.
+func (repo *MembershipRepository) Organizations(ctx context.Context, userID int64) ([]int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var res []int64
+
+	for orgID, members := range repo.members {
+		if _, ok := members[userID]; ok {
+			res = append(res, orgID)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+
+	return res, nil
+}
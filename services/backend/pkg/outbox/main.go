@@ -0,0 +1,266 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+)
+
+// Types of the aggregates events are published for
+const (
+	AggregateUser               = "user"
+	AggregateOrganizationMember = "organization_member"
+)
+
+// Event is a row of the `outbox` table, a domain event defined in `events.v1` awaiting relay. Events of an aggregate
+// are published in ID order.
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	RequestID     string          `json:"request_id,omitempty"`
+	Attempts      int             `json:"-"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// NewEvent returns the event of msg happening to an aggregate, within the request of ctx. The payload is the JSON
+// encoding of msg, and the type its full name, eg: `events.v1.UserRegistered`.
+func NewEvent(ctx context.Context, aggregateType string, aggregateID any, msg proto.Message) (*Event, error) {
+	payload, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding domain event: %w", err)
+	}
+
+	return &Event{
+		AggregateType: aggregateType,
+		AggregateID:   fmt.Sprint(aggregateID),
+		Type:          string(msg.ProtoReflect().Descriptor().FullName()),
+		Payload:       payload,
+		RequestID:     access.FromContext(ctx),
+	}, nil
+}
+
+// Message decodes the payload into a message of the event's type.
+func (e *Event) Message() (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(e.Type))
+	if err != nil {
+		return nil, fmt.Errorf("decoding domain event %s: %w", e.Type, err)
+	}
+
+	msg := mt.New().Interface()
+	if err := protojson.Unmarshal(e.Payload, msg); err != nil {
+		return nil, fmt.Errorf("decoding domain event %s: %w", e.Type, err)
+	}
+
+	return msg, nil
+}
+
+// Writer writes events in the transaction of the change they describe, so both are committed or neither is. Storage
+// without transactions passes a nil tx. Callers must hold a lock on the aggregate, such as its row, so that its events
+// are written in the order their transactions commit.
+type Writer interface {
+	Write(ctx context.Context, tx *postgres.Tx, event *Event) error
+}
+
+type nop struct{}
+
+// Nop returns a Writer that discards events.
+func Nop() Writer {
+	return nop{}
+}
+
+// Write implements Writer.
+func (nop) Write(context.Context, *postgres.Tx, *Event) error {
+	return nil
+}
+
+// Sink publishes events to other services. Events may be published more than once, when the relay fails to record
+// that they were.
+type Sink interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+type sinks []Sink
+
+// Sinks returns a Sink publishing events to each of sinks in turn, which fails as soon as one of them does. The event
+// is then published to all of them again, by the next relay.
+func Sinks(s ...Sink) Sink {
+	if len(s) == 1 {
+		return s[0]
+	}
+
+	return sinks(s)
+}
+
+// Publish implements Sink.
+func (s sinks) Publish(ctx context.Context, e *Event) error {
+	for _, sink := range s {
+		if err := sink.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PublishFunc publishes a claimed event, which is retried later on error.
+type PublishFunc func(ctx context.Context, event *Event) error
+
+// Source holds the events awaiting relay.
+type Source interface {
+	// Relay claims up to limit events that are due, each the oldest pending event of its aggregate, and publishes
+	// them in turn. Published events are marked as such, and failed ones rescheduled after Backoff. It returns how
+	// many were claimed.
+	Relay(ctx context.Context, limit int, publish PublishFunc) (int, error)
+	// Prune deletes the events published before a time, returning how many were.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+const (
+	// Retry delays double from minBackoff on every failed attempt, up to maxBackoff.
+	minBackoff = time.Second
+	maxBackoff = 10 * time.Minute
+
+	// pruneEvery is how often events published longer ago than the retention are deleted.
+	pruneEvery = time.Hour
+)
+
+// Backoff returns how long to wait before publishing an event again once it has failed attempts times.
+func Backoff(attempts int) time.Duration {
+	d := minBackoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > maxBackoff {
+		return maxBackoff
+	}
+
+	return d
+}
+
+// Relay publishes the events of a Source to a Sink, polling it for more every interval.
+type Relay struct {
+	source    Source
+	sink      Sink
+	log       *otelzap.Logger
+	clock     clock.Clock
+	interval  time.Duration
+	batch     int
+	retention time.Duration
+}
+
+// NewRelay returns a Relay configured by cfg, whose Sink it ignores in favour of sink.
+func NewRelay(source Source, sink Sink, log *otelzap.Logger, clk clock.Clock, cfg config.OutboxConfig) *Relay {
+	return &Relay{
+		source:    source,
+		sink:      sink,
+		log:       log,
+		clock:     clk,
+		interval:  time.Duration(cfg.Interval),
+		batch:     cfg.BatchSize,
+		retention: time.Duration(cfg.Retention),
+	}
+}
+
+// Run relays events until ctx is done, and deletes those published longer ago than the retention. A full batch is
+// followed by the next straight away, otherwise the relay waits for the interval.
+func (r *Relay) Run(ctx context.Context) {
+	r.log.Ctx(ctx).Info("Outbox relay started", zap.Duration("interval", r.interval), zap.Int("batch", r.batch))
+
+	go r.prune(ctx)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Ctx(ctx).Info("Outbox relay stopped")
+			return
+		case <-timer.C:
+		}
+
+		n, err := r.source.Relay(ctx, r.batch, r.publish)
+		if err != nil {
+			r.log.Ctx(ctx).Error("Error relaying outbox: ", zap.Error(err))
+		}
+
+		if err == nil && n == r.batch {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.interval)
+		}
+	}
+}
+
+// Flush relays batches until none are due, returning how many events were claimed.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		n, err := r.source.Relay(ctx, r.batch, r.publish)
+		total += n
+
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+}
+
+// Prune deletes the events published longer ago than the retention, returning how many were.
+func (r *Relay) Prune(ctx context.Context) (int, error) {
+	return r.source.Prune(ctx, r.clock.Now().Add(-r.retention))
+}
+
+// prune prunes the outbox every pruneEvery, until ctx is done.
+func (r *Relay) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneEvery)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Prune(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Ctx(ctx).Error("Error pruning outbox: ", zap.Error(err))
+		} else if n > 0 {
+			r.log.Ctx(ctx).Info("Pruned outbox", zap.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish hands an event to the sink, logging failures.
+func (r *Relay) publish(ctx context.Context, e *Event) error {
+	err := r.sink.Publish(ctx, e)
+	if err != nil {
+		r.log.Ctx(ctx).Warn("Couldn't publish domain event",
+			zap.Int64("event_id", e.ID),
+			zap.String("type", e.Type),
+			zap.String("aggregate", e.AggregateType+":"+e.AggregateID),
+			zap.Int("attempts", e.Attempts+1),
+			zap.Error(err),
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,233 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox/repository/memory"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox/sink"
+)
+
+// ticker is a clock that only moves when told to.
+type ticker struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ticker) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ticker) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// failing fails the first n publishes.
+type failing struct {
+	n int
+}
+
+func (f *failing) Publish(ctx context.Context, e *outbox.Event) error {
+	if f.n > 0 {
+		f.n--
+		return errors.New("sink unavailable")
+	}
+
+	return nil
+}
+
+func write(t *testing.T, repo *memory.EventRepository, aggregateID string) {
+	t.Helper()
+
+	if err := repo.Write(context.Background(), nil, &outbox.Event{AggregateType: outbox.AggregateUser, AggregateID: aggregateID}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+// settings configures a relay polling every interval for batches of 10 events, which keeps them for an hour once
+// published.
+func settings(interval time.Duration) config.OutboxConfig {
+	return config.OutboxConfig{Interval: config.Duration(interval), BatchSize: 10, Retention: config.Duration(time.Hour)}
+}
+
+func ids(events []outbox.Event) []int64 {
+	res := make([]int64, len(events))
+	for i, e := range events {
+		res[i] = e.ID
+	}
+
+	return res
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		30: 10 * time.Minute,
+	}
+
+	for attempts, want := range cases {
+		if got := outbox.Backoff(attempts); got != want {
+			t.Fatalf("Backoff(%d): expected %s, got %s", attempts, want, got)
+		}
+	}
+}
+
+func TestRelayFlush(t *testing.T) {
+	repo := memory.NewEventRepository(&ticker{now: time.Now()})
+
+	write(t, repo, "1")
+	write(t, repo, "1")
+	write(t, repo, "2")
+
+	published := sink.NewMemory()
+	relay := outbox.NewRelay(repo, published, otelzap.New(zap.NewNop()), clock.New(), settings(time.Second))
+
+	n, err := relay.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if n != 3 {
+		t.Fatalf("expected 3 events to be claimed, got %d", n)
+	}
+
+	// Only the oldest event of an aggregate is claimed at a time, so the second of user 1 waits for the first.
+	if got := ids(published.Events()); len(got) != 3 || got[0] != 1 || got[1] != 3 || got[2] != 2 {
+		t.Fatalf("expected events 1, 3 then 2, got %v", got)
+	}
+
+	if n, _ := relay.Flush(context.Background()); n != 0 {
+		t.Fatalf("expected published events not to be claimed again, got %d", n)
+	}
+}
+
+func TestRelayRetries(t *testing.T) {
+	clk := &ticker{now: time.Now()}
+	repo := memory.NewEventRepository(clk)
+
+	write(t, repo, "1")
+	write(t, repo, "1")
+
+	published := sink.NewMemory()
+
+	// The memory sink sees the event on every attempt, the failing one only lets it through the third time.
+	relay := outbox.NewRelay(repo, outbox.Sinks(published, &failing{n: 2}), otelzap.New(zap.NewNop()), clk, settings(time.Second))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if _, err := relay.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+
+		if n, _ := relay.Flush(context.Background()); n != 0 {
+			t.Fatalf("attempt %d: expected a failed event to wait for its backoff, got %d claimed", attempt, n)
+		}
+
+		clk.Add(outbox.Backoff(attempt))
+	}
+
+	if _, err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// Event 1 reached the memory sink on each of its 3 attempts, and the event queued behind it once it succeeded.
+	if got := ids(published.Events()); len(got) != 4 || got[0] != 1 || got[2] != 1 || got[3] != 2 {
+		t.Fatalf("expected event 1 three times then event 2, got %v", got)
+	}
+}
+
+func TestRelayRun(t *testing.T) {
+	repo := memory.NewEventRepository(&ticker{now: time.Now()})
+	write(t, repo, "1")
+
+	published := sink.NewMemory()
+	relay := outbox.NewRelay(repo, published, otelzap.New(zap.NewNop()), clock.New(), settings(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for len(published.Events()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the running relay to publish the event")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// Events written while running are picked up on the next poll.
+	write(t, repo, "2")
+
+	for len(published.Events()) < 2 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the running relay to publish the event written later")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+}
+
+func TestRelayPrune(t *testing.T) {
+	clk := &ticker{now: time.Now()}
+	repo := memory.NewEventRepository(clk)
+
+	write(t, repo, "1")
+	write(t, repo, "2")
+
+	relay := outbox.NewRelay(repo, outbox.Sinks(sink.NewMemory(), &failing{n: 1}), otelzap.New(zap.NewNop()), clk, settings(time.Second))
+
+	// Event 1 fails and stays pending, event 2 is published.
+	if _, err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	clk.Add(30 * time.Minute)
+
+	if n, err := relay.Prune(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected events published within the retention to be kept, got %d (%v)", n, err)
+	}
+
+	clk.Add(31 * time.Minute)
+
+	if n, err := relay.Prune(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected the event published before the retention to be pruned, got %d (%v)", n, err)
+	}
+
+	// The pending event is still relayed, and later writes don't reuse the pruned ID.
+	write(t, repo, "3")
+
+	published := sink.NewMemory()
+	relay = outbox.NewRelay(repo, published, otelzap.New(zap.NewNop()), clk, settings(time.Second))
+
+	if _, err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got := ids(published.Events()); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("expected events 1 and 3, got %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+)
+
+// IEventRepository is the interface for the outbox, written to by mutations and read by the relay.
+type IEventRepository interface {
+	outbox.Writer
+	outbox.Source
+}
+
+// EventRepository --
+type EventRepository struct {
+	dbs   *postgres.DB
+	log   *otelzap.Logger
+	clock clock.Clock
+}
+
+var _ IEventRepository = (*EventRepository)(nil)
+
+// NewEventRepository Creates a new outbox repo instance
+func NewEventRepository(dbs *postgres.DB, log *otelzap.Logger, clk clock.Clock) *EventRepository {
+	return &EventRepository{dbs: dbs, log: log, clock: clk}
+}
+
+// pending selects the events that are the oldest pending of their aggregate. Their successors stay unclaimed until
+// they are published.
+const pending = `NOT EXISTS (
+  SELECT 1
+  FROM outbox earlier
+  WHERE earlier.aggregate_type = outbox.aggregate_type
+    AND earlier.aggregate_id = outbox.aggregate_id
+    AND earlier.published_at IS NULL
+    AND earlier.id < outbox.id
+)`
+
+// Write writes the event in tx, the transaction of the change it describes, or in one of its own if tx is nil. It is
+// due straight away.
+func (repo *EventRepository) Write(ctx context.Context, tx *postgres.Tx, e *outbox.Event) error {
+	if tx == nil {
+		return repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+			return repo.Write(ctx, tx, e)
+		})
+	}
+
+	e.CreatedAt = repo.clock.Now()
+
+	m := &models.Outbox{
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		EventType:     e.Type,
+		Payload:       types.JSON(e.Payload),
+		RequestID:     e.RequestID,
+		AvailableAt:   e.CreatedAt,
+		CreatedAt:     e.CreatedAt,
+	}
+
+	if err := m.Insert(ctx, tx, boil.Infer()); err != nil {
+		return repo.failed(ctx, "write domain event", err)
+	}
+
+	e.ID = m.ID
+	return nil
+}
+
+// Relay claims a batch of due events and publishes them, holding their locks until the outcomes are committed. An
+// event whose outcome fails to commit is published again.
+func (repo *EventRepository) Relay(ctx context.Context, limit int, publish outbox.PublishFunc) (n int, err error) {
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		claimed, err := repo.claim(ctx, tx, limit)
+		if err != nil {
+			return err
+		}
+
+		n = len(claimed)
+
+		for _, m := range claimed {
+			err := publish(ctx, event(m))
+			m.Attempts++
+
+			if err != nil {
+				m.LastError = err.Error()
+				m.AvailableAt = repo.clock.Now().Add(outbox.Backoff(m.Attempts))
+
+				_, err = m.Update(ctx, tx, boil.Whitelist(
+					models.OutboxColumns.Attempts, models.OutboxColumns.LastError, models.OutboxColumns.AvailableAt,
+				))
+				if err != nil {
+					return repo.failed(ctx, "reschedule domain event", err)
+				}
+
+				continue
+			}
+
+			m.LastError = ""
+			m.PublishedAt = null.TimeFrom(repo.clock.Now())
+
+			_, err = m.Update(ctx, tx, boil.Whitelist(
+				models.OutboxColumns.Attempts, models.OutboxColumns.LastError, models.OutboxColumns.PublishedAt,
+			))
+			if err != nil {
+				return repo.failed(ctx, "mark domain event published", err)
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// claim locks a batch of due events. Other relays skip the locked rows rather than wait for them.
+func (repo *EventRepository) claim(ctx context.Context, tx *postgres.Tx, limit int) (models.OutboxSlice, error) {
+	res, err := models.Outboxes(
+		models.OutboxWhere.PublishedAt.IsNull(),
+		models.OutboxWhere.AvailableAt.LTE(repo.clock.Now()),
+		qm.Where(pending),
+		qm.OrderBy(models.OutboxColumns.ID),
+		qm.Limit(limit),
+		qm.For("UPDATE SKIP LOCKED"),
+	).All(ctx, tx)
+
+	return res, repo.failed(ctx, "claim domain events", err)
+}
+
+// Prune deletes the events published before a time
+func (repo *EventRepository) Prune(ctx context.Context, before time.Time) (n int, err error) {
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		rows, err := models.Outboxes(models.OutboxWhere.PublishedAt.LT(null.TimeFrom(before))).DeleteAll(ctx, tx)
+		n = int(rows)
+
+		return repo.failed(ctx, "prune domain events", err)
+	})
+
+	return
+}
+
+// event converts the model of an event.
+func event(m *models.Outbox) *outbox.Event {
+	return &outbox.Event{
+		ID:            m.ID,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID,
+		Type:          m.EventType,
+		Payload:       json.RawMessage(m.Payload),
+		RequestID:     m.RequestID,
+		Attempts:      m.Attempts,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+// failed logs and wraps an unexpected error.
+func (repo *EventRepository) failed(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	warning := fmt.Sprintf("Couldn't %s: %s", action, err)
+	repo.log.Ctx(ctx).Error(warning)
+
+	return fmt.Errorf("Couldn't %s: %w", action, err)
+}
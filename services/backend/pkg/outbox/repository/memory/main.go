@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox/repository"
+)
+
+// entry is an event with its relay state.
+type entry struct {
+	event     outbox.Event
+	available time.Time
+	claimed   bool
+	published *time.Time
+}
+
+// EventRepository is an in-memory repository.IEventRepository, for running without a database. Events are written as
+// soon as the change they describe is made, there being no transaction to join.
+type EventRepository struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	seq     int64
+	entries []*entry
+}
+
+var _ repository.IEventRepository = (*EventRepository)(nil)
+
+// NewEventRepository Creates a new, empty in-memory outbox repo instance
+func NewEventRepository(clk clock.Clock) *EventRepository {
+	return &EventRepository{clock: clk}
+}
+
+// Write appends the event
+func (repo *EventRepository) Write(ctx context.Context, tx *postgres.Tx, e *outbox.Event) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.seq++
+
+	e.ID = repo.seq
+	e.CreatedAt = repo.clock.Now()
+
+	repo.entries = append(repo.entries, &entry{event: *e, available: e.CreatedAt})
+
+	return nil
+}
+
+// Relay claims a batch of due events and publishes them. Like the Postgres implementation, only the oldest pending
+// event of an aggregate is claimed.
+func (repo *EventRepository) Relay(ctx context.Context, limit int, publish outbox.PublishFunc) (int, error) {
+	claimed := repo.claim(limit)
+
+	for _, en := range claimed {
+		e := en.event
+		err := publish(ctx, &e)
+
+		repo.mu.Lock()
+		en.claimed = false
+		en.event.Attempts++
+
+		if err != nil {
+			en.available = repo.clock.Now().Add(outbox.Backoff(en.event.Attempts))
+		} else {
+			now := repo.clock.Now()
+			en.published = &now
+		}
+		repo.mu.Unlock()
+	}
+
+	return len(claimed), nil
+}
+
+// Prune deletes the events published before a time
+func (repo *EventRepository) Prune(ctx context.Context, before time.Time) (int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	kept := repo.entries[:0]
+	for _, en := range repo.entries {
+		if en.published == nil || !en.published.Before(before) {
+			kept = append(kept, en)
+		}
+	}
+
+	n := len(repo.entries) - len(kept)
+	repo.entries = kept
+
+	return n, nil
+}
+
+// claim marks a batch of due events as claimed, so concurrent relays skip them.
+func (repo *EventRepository) claim(limit int) []*entry {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := repo.clock.Now()
+	pending := map[string]bool{}
+
+	var res []*entry
+
+	for _, en := range repo.entries {
+		if en.published != nil {
+			continue
+		}
+
+		aggregate := en.event.AggregateType + ":" + en.event.AggregateID
+		if pending[aggregate] {
+			continue
+		}
+
+		pending[aggregate] = true
+
+		if en.claimed || en.available.After(now) || len(res) == limit {
+			continue
+		}
+
+		en.claimed = true
+		res = append(res, en)
+	}
+
+	return res
+}
@@ -0,0 +1,175 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/secrets"
+)
+
+// timeout bounds a publish whose context has no deadline.
+const timeout = 10 * time.Second
+
+// Headers describing the event posted by Webhook.
+const (
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// Memory is a process-local outbox.Sink, collecting the events published to it for tests.
+type Memory struct {
+	mu     sync.Mutex
+	events []outbox.Event
+}
+
+// NewMemory returns an empty Memory sink.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish implements outbox.Sink.
+func (m *Memory) Publish(ctx context.Context, e *outbox.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, *e)
+
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (m *Memory) Events() []outbox.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]outbox.Event{}, m.events...)
+}
+
+// Webhook is an outbox.Sink posting each event as JSON to a URL, which acknowledges it with a 2xx status.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a Webhook posting to url with client, or with a default client when it is nil.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+
+	return &Webhook{url: url, client: client}
+}
+
+// Publish implements outbox.Sink.
+func (w *Webhook) Publish(ctx context.Context, e *outbox.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatInt(e.ID, 10))
+	req.Header.Set(EventTypeHeader, e.Type)
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	// Drain the body, so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("posting webhook: unexpected status %s", res.Status)
+	}
+
+	return nil
+}
+
+// Redis is an outbox.Sink adding each event to a Redis stream with XADD, as the fields of an entry. Connections are
+// pooled by the client, which dials them again after failures, authenticating with the password resolved anew.
+type Redis struct {
+	client *redis.Client
+	stream string
+}
+
+// NewRedis returns a Redis sink adding to stream on the server configured by cfg.
+func NewRedis(cfg config.RedisConfig, stream string) *Redis {
+	password := secrets.New(cfg.PasswordRef(), cfg.Password)
+
+	opts := &redis.Options{
+		Addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		DB:   cfg.DB,
+		// Resolved for every new connection, so a rotated password is picked up without a restart.
+		CredentialsProvider: func() (string, string) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			value, err := password.Value(ctx)
+			if err != nil {
+				logger.Named("outbox").Ctx(ctx).Warn("Couldn't resolve the redis password", zap.Error(err))
+			}
+
+			return "", value
+		},
+		// Publishes without a deadline of their own are bounded by the timeouts.
+		ContextTimeoutEnabled: true,
+		DialTimeout:           timeout,
+		ReadTimeout:           timeout,
+		WriteTimeout:          timeout,
+	}
+
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.InsecureSkipVerify}
+	}
+
+	return &Redis{client: redis.NewClient(opts), stream: stream}
+}
+
+// Publish implements outbox.Sink.
+func (r *Redis) Publish(ctx context.Context, e *outbox.Event) error {
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		Values: []string{
+			"id", strconv.FormatInt(e.ID, 10),
+			"type", e.Type,
+			"aggregate_type", e.AggregateType,
+			"aggregate_id", e.AggregateID,
+			"request_id", e.RequestID,
+			"created_at", e.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"payload", string(e.Payload),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("adding to redis stream: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the client's connections.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,219 @@
+package sink_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox/sink"
+)
+
+func event() *outbox.Event {
+	return &outbox.Event{
+		ID:            7,
+		Type:          "events.v1.UserRegistered",
+		AggregateType: outbox.AggregateUser,
+		AggregateID:   "42",
+		RequestID:     "req-1",
+		Payload:       []byte(`{"user":{"id":"42"}}`),
+		CreatedAt:     time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestMemory(t *testing.T) {
+	m := sink.NewMemory()
+
+	for i := 0; i < 2; i++ {
+		if err := m.Publish(context.Background(), event()); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	if events := m.Events(); len(events) != 2 || events[0].ID != 7 {
+		t.Fatalf("expected the 2 published events, got %v", events)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got *http.Request
+	var body []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	if err := sink.NewWebhook(srv.URL+"/events", nil).Publish(context.Background(), event()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected a JSON POST, got %s %s", got.Method, got.Header.Get("Content-Type"))
+	}
+
+	if got.Header.Get(sink.EventIDHeader) != "7" || got.Header.Get(sink.EventTypeHeader) != "events.v1.UserRegistered" {
+		t.Fatalf("expected the event headers, got %v", got.Header)
+	}
+
+	var e outbox.Event
+	if err := json.Unmarshal(body, &e); err != nil || e.ID != 7 || e.AggregateID != "42" {
+		t.Fatalf("expected the event as the body, got %s (%v)", body, err)
+	}
+
+	if err := sink.NewWebhook(srv.URL+"/failing", nil).Publish(context.Background(), event()); err == nil {
+		t.Fatal("expected a 503 to fail the publish")
+	}
+}
+
+// redis returns the config of a Redis sink connecting to s.
+func redis(s *miniredis.Miniredis) config.RedisConfig {
+	return config.RedisConfig{Host: s.Host(), Port: s.Port()}
+}
+
+func entries(t *testing.T, db *miniredis.RedisDB, stream string) []miniredis.StreamEntry {
+	t.Helper()
+
+	res, err := db.Stream(stream)
+	if err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+
+	return res
+}
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	s.RequireAuth("secret")
+
+	cfg := redis(s)
+	cfg.Password = "secret"
+	cfg.DB = 2
+
+	r := sink.NewRedis(cfg, "perspex:events")
+	t.Cleanup(func() { r.Close() })
+
+	if err := r.Publish(context.Background(), event()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	added := entries(t, s.DB(2), "perspex:events")
+	if len(added) != 1 {
+		t.Fatalf("expected an entry in the stream of db 2, got %v", added)
+	}
+
+	values := map[string]string{}
+	for i := 0; i+1 < len(added[0].Values); i += 2 {
+		values[added[0].Values[i]] = added[0].Values[i+1]
+	}
+
+	want := map[string]string{
+		"id":             "7",
+		"type":           "events.v1.UserRegistered",
+		"aggregate_type": outbox.AggregateUser,
+		"aggregate_id":   "42",
+		"request_id":     "req-1",
+		"created_at":     "2023-01-02T03:04:05Z",
+		"payload":        `{"user":{"id":"42"}}`,
+	}
+
+	for k, v := range want {
+		if values[k] != v {
+			t.Fatalf("expected %s to be %q, got %q", k, v, values[k])
+		}
+	}
+}
+
+func TestRedisAuthenticationFails(t *testing.T) {
+	s := miniredis.RunT(t)
+	s.RequireAuth("secret")
+
+	cfg := redis(s)
+	cfg.Password = "wrong"
+
+	r := sink.NewRedis(cfg, "perspex:events")
+	t.Cleanup(func() { r.Close() })
+
+	if err := r.Publish(context.Background(), event()); err == nil {
+		t.Fatal("expected a wrong password to fail the publish")
+	}
+}
+
+func TestRedisReconnects(t *testing.T) {
+	s := miniredis.RunT(t)
+
+	r := sink.NewRedis(redis(s), "perspex:events")
+	t.Cleanup(func() { r.Close() })
+
+	if err := r.Publish(context.Background(), event()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	s.Close()
+
+	if err := r.Publish(context.Background(), event()); err == nil {
+		t.Fatal("expected publishing to a stopped server to fail")
+	}
+
+	if err := s.Restart(); err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+
+	if err := r.Publish(context.Background(), event()); err != nil {
+		t.Fatalf("Publish after the server restarted: %v", err)
+	}
+
+	if added := entries(t, s.DB(0), "perspex:events"); len(added) != 2 {
+		t.Fatalf("expected 2 entries in the stream, got %d", len(added))
+	}
+}
+
+func TestRedisTLS(t *testing.T) {
+	// Borrow the certificate httptest serves with.
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	certificates := srv.TLS.Certificates
+	srv.Close()
+
+	s, err := miniredis.RunTLS(&tls.Config{Certificates: certificates})
+	if err != nil {
+		t.Fatalf("RunTLS: %v", err)
+	}
+
+	t.Cleanup(s.Close)
+
+	cfg := redis(s)
+
+	plain := sink.NewRedis(cfg, "perspex:events")
+	t.Cleanup(func() { plain.Close() })
+
+	if err := plain.Publish(context.Background(), event()); err == nil {
+		t.Fatal("expected publishing without TLS to fail")
+	}
+
+	cfg.TLS = true
+	cfg.InsecureSkipVerify = true
+
+	r := sink.NewRedis(cfg, "perspex:events")
+	t.Cleanup(func() { r.Close() })
+
+	if err := r.Publish(context.Background(), event()); err != nil {
+		t.Fatalf("Publish over TLS: %v", err)
+	}
+
+	if added := entries(t, s.DB(0), "perspex:events"); len(added) != 1 {
+		t.Fatalf("expected an entry in the stream, got %d", len(added))
+	}
+}
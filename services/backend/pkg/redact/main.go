@@ -0,0 +1,357 @@
+package redact
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	options "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/options/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+)
+
+// Redaction modes
+const (
+	ModeMask = "mask"
+	ModeHash = "hash"
+)
+
+// AllowAll, in RedactConfig.Allow, leaves every field as it is.
+const AllowAll = "*"
+
+// Policy decides which fields hold personal data and how their values are hidden. A field is personal data if its
+// key, or the last dot separated part of it, eg: `email` of `user.email`, is registered, compared case insensitively.
+type Policy struct {
+	mode  string
+	key   []byte
+	allow map[string]bool
+
+	mu     sync.RWMutex
+	fields map[string]bool
+}
+
+var (
+	mu   sync.RWMutex
+	std  *Policy
+	once sync.Once
+)
+
+// New returns a policy for cfg, registering every field tagged with the `(options.v1.pii)` option among the protos
+// linked into the binary.
+func New(cfg config.RedactConfig) *Policy {
+	p := &Policy{
+		mode:   cfg.Mode,
+		key:    []byte(cfg.Key),
+		allow:  map[string]bool{},
+		fields: map[string]bool{},
+	}
+
+	for _, name := range cfg.GetAllow() {
+		p.allow[strings.ToLower(name)] = true
+	}
+
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		messages(fd.Messages(), p.register)
+		return true
+	})
+
+	return p
+}
+
+// Configure replaces the policy used by Default.
+func Configure(cfg config.RedactConfig) {
+	p := New(cfg)
+
+	once.Do(func() {})
+
+	mu.Lock()
+	std = p
+	mu.Unlock()
+}
+
+// Default returns the configured policy, or else one masking the fields tagged in protos.
+func Default() *Policy {
+	once.Do(func() {
+		p := New(config.RedactConfig{Mode: ModeMask})
+
+		mu.Lock()
+		std = p
+		mu.Unlock()
+	})
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return std
+}
+
+// Register marks fields as personal data in the default policy, for those not described by protos.
+func Register(names ...string) {
+	p := Default()
+	for _, name := range names {
+		p.Register(name)
+	}
+}
+
+// Register marks a field as personal data.
+func (p *Policy) Register(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fields[strings.ToLower(name)] = true
+}
+
+// register marks a proto field as personal data by each of the names it is logged under.
+func (p *Policy) register(fd protoreflect.FieldDescriptor) {
+	p.Register(string(fd.Name()))
+	p.Register(fd.JSONName())
+	p.Register(string(fd.FullName()))
+}
+
+// PII reports whether the field with key holds personal data which is not allowed through.
+func (p *Policy) PII(key string) bool {
+	key = strings.ToLower(key)
+	last := key[strings.LastIndex(key, ".")+1:]
+
+	if p.allow[AllowAll] || p.allow[key] || p.allow[last] {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.fields[key] || p.fields[last]
+}
+
+// Value hides a value, masking or hashing it as configured.
+func (p *Policy) Value(v string) string {
+	if p.mode != ModeHash {
+		return config.Redacted
+	}
+
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(v))
+
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// String hides v if key names personal data.
+func (p *Policy) String(key string, v string) string {
+	if !p.PII(key) {
+		return v
+	}
+
+	return p.Value(v)
+}
+
+// Message returns a copy of m with the values of its personal data fields hidden, including those of nested messages.
+func (p *Policy) Message(m proto.Message) proto.Message {
+	if m == nil {
+		return nil
+	}
+
+	m = proto.Clone(m)
+	p.message(m.ProtoReflect())
+
+	return m
+}
+
+func (p *Policy) message(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		pii := p.PII(string(fd.FullName()))
+
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				switch {
+				case fd.Message() != nil:
+					p.message(list.Get(i).Message())
+				case pii && fd.Kind() == protoreflect.StringKind:
+					list.Set(i, protoreflect.ValueOfString(p.Value(list.Get(i).String())))
+				}
+			}
+		case fd.IsMap():
+			// Values are replaced once ranged over, as the map may not be changed while it is.
+			var replaced []protoreflect.MapKey
+
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch {
+				case fd.MapValue().Message() != nil:
+					p.message(mv.Message())
+				case pii && fd.MapValue().Kind() == protoreflect.StringKind:
+					replaced = append(replaced, k)
+				}
+
+				return true
+			})
+
+			for _, k := range replaced {
+				v.Map().Set(k, protoreflect.ValueOfString(p.Value(v.Map().Get(k).String())))
+			}
+		case fd.Message() != nil:
+			p.message(v.Message())
+		case pii && fd.Kind() == protoreflect.StringKind:
+			m.Set(fd, protoreflect.ValueOfString(p.Value(v.String())))
+		case pii:
+			m.Set(fd, fd.Default())
+		}
+
+		return true
+	})
+}
+
+// Payload returns a field logging m as JSON, with its personal data hidden by the default policy.
+func Payload(key string, m proto.Message) zap.Field {
+	return Default().payload(key, m)
+}
+
+func (p *Policy) payload(key string, m proto.Message) zap.Field {
+	b, err := protojson.Marshal(p.Message(m))
+	if err != nil {
+		return zap.String(key, fmt.Sprintf("<unencodable %T: %s>", m, err))
+	}
+
+	return zap.Any(key, json.RawMessage(b))
+}
+
+// Field hides the value of a zap field holding personal data. Proto messages are logged as their redacted JSON.
+func (p *Policy) Field(f zapcore.Field) zapcore.Field {
+	if m, ok := f.Interface.(proto.Message); ok {
+		return p.payload(f.Key, m)
+	}
+
+	if !p.PII(f.Key) {
+		return f
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	return zap.String(f.Key, p.Value(fmt.Sprint(enc.Fields[f.Key])))
+}
+
+func (p *Policy) zapFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = p.Field(f)
+	}
+
+	return redacted
+}
+
+// Attribute hides the value of a span attribute holding personal data.
+func (p *Policy) Attribute(kv attribute.KeyValue) attribute.KeyValue {
+	if !p.PII(string(kv.Key)) {
+		return kv
+	}
+
+	return kv.Key.String(p.Value(kv.Value.Emit()))
+}
+
+func (p *Policy) attributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		redacted[i] = p.Attribute(kv)
+	}
+
+	return redacted
+}
+
+// Core hides personal data in the fields logged through core, with the default policy as it is when each entry is
+// written.
+func Core(core zapcore.Core) zapcore.Core {
+	return &redactCore{Core: core}
+}
+
+type redactCore struct {
+	zapcore.Core
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(Default().zapFields(fields))}
+}
+
+func (c *redactCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+
+	return ce
+}
+
+func (c *redactCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(e, Default().zapFields(fields))
+}
+
+// Exporter hides personal data in the attributes of spans, and of their events, before exporting them.
+func Exporter(exporter sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &redactExporter{SpanExporter: exporter}
+}
+
+type redactExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (e *redactExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	p := Default()
+
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		redacted[i] = &span{ReadOnlySpan: s, policy: p}
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+// span overrides the attributes of a finished span, which are otherwise immutable.
+type span struct {
+	sdktrace.ReadOnlySpan
+	policy *Policy
+}
+
+func (s *span) Attributes() []attribute.KeyValue {
+	return s.policy.attributes(s.ReadOnlySpan.Attributes())
+}
+
+func (s *span) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+
+	redacted := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Attributes = s.policy.attributes(e.Attributes)
+		redacted[i] = e
+	}
+
+	return redacted
+}
+
+// messages calls f with every field tagged as personal data in msgs, and in the messages nested within them.
+func messages(msgs protoreflect.MessageDescriptors, f func(protoreflect.FieldDescriptor)) {
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+
+		fields := md.Fields()
+		for j := 0; j < fields.Len(); j++ {
+			fd := fields.Get(j)
+			if pii, ok := proto.GetExtension(fd.Options(), options.E_Pii).(bool); ok && pii {
+				f(fd)
+			}
+		}
+
+		messages(md.Messages(), f)
+	}
+}
@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// HandlerFunc constructs a Connect handler with the supplied options, returning the path it should be mounted on.
+// The generated `New<Service>Handler` constructors satisfy this signature once bound to their implementation.
+type HandlerFunc func(opts ...connect.HandlerOption) (string, http.Handler)
+
+// GatewayFunc registers REST gateway routes that proxy to the service over the supplied connection.
+// The generated `Register<Service>Handler` functions satisfy this signature.
+type GatewayFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// Service is a domain service that can be mounted by the router. Each domain package provides its own
+// implementation, so new services can be added without editing the router.
+type Service interface {
+	// Name returns the fully-qualified protobuf service name, used for gRPC reflection.
+	Name() string
+
+	// Handler returns the constructor for the service's Connect handler.
+	Handler() HandlerFunc
+
+	// Interceptors returns the interceptors the service requires, applied after the shared interceptors.
+	Interceptors() []connect.Interceptor
+
+	// Gateway returns the REST gateway registration for the service, or nil if it has none.
+	Gateway() GatewayFunc
+}
+
+// Registration is a static Service definition.
+type Registration struct {
+	ServiceName         string
+	HandlerFunc         HandlerFunc
+	ServiceInterceptors []connect.Interceptor
+	GatewayFunc         GatewayFunc
+}
+
+// Name implements Service.
+func (r *Registration) Name() string {
+	return r.ServiceName
+}
+
+// Handler implements Service.
+func (r *Registration) Handler() HandlerFunc {
+	return r.HandlerFunc
+}
+
+// Interceptors implements Service.
+func (r *Registration) Interceptors() []connect.Interceptor {
+	return r.ServiceInterceptors
+}
+
+// Gateway implements Service.
+func (r *Registration) Gateway() GatewayFunc {
+	return r.GatewayFunc
+}
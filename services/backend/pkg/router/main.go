@@ -2,57 +2,177 @@ package router
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 	grpcReflect "github.com/bufbuild/connect-grpcreflect-go"
 	otelconnect "github.com/bufbuild/connect-opentelemetry-go"
 	"github.com/dimiro1/health"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
-	usersConnect "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/authz"
 	"github.com/jmandel1027/perspex/services/backend/pkg/config"
-	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
-	transaction "github.com/jmandel1027/perspex/services/backend/pkg/middleware/postgres"
-	userService "github.com/jmandel1027/perspex/services/backend/pkg/user/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/access"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/admin"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/impersonate"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/recovery"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
 )
 
-// Route -- used for mounting all of our routes
-func Route(cfg *config.BackendConfig, dbs *postgres.DB) http.Handler {
+// Route -- used for mounting all of our routes, authenticating RPCs with authn and authorizing them against policy.
+// The gateway's connection back to the server is closed once ctx is done, so ctx should last as long as the server.
+func Route(ctx context.Context, cfg *config.BackendConfig, authn *auth.Interceptor, policy *authz.Policy, services ...registry.Service) http.Handler {
 	mux := http.NewServeMux()
 	api := http.NewServeMux()
-
-	users := userService.NewUserService()
-
-	reflector := grpcReflect.NewStaticReflector(
-		"user.v1.UserService",
+	gateway := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headers),
+		runtime.WithOutgoingHeaderMatcher(flags),
 	)
 
-	reader := func(ctx context.Context, req *transaction.Request) (*sql.DB, *sql.TxOptions, error) {
-		return transaction.Wrap(ctx, dbs.Reader, postgres.ReadOnlyTxOpts)
+	otelzap.L().Info("Scaffolding opts")
+	shared := []connect.Interceptor{
+		otelconnect.NewInterceptor(),
+		metrics.NewInterceptor(),
+		access.NewInterceptor(),
+		audit.NewInterceptor(),
+		recovery.NewInterceptor(),
+		authn,
+		impersonate.NewInterceptor(policy),
+		authz.NewInterceptor(policy, cfg.Authz.Mode),
+		admin.NewInterceptor(),
 	}
 
-	writer := func(ctx context.Context, req *transaction.Request) (*sql.DB, *sql.TxOptions, error) {
-		return transaction.Wrap(ctx, dbs.Writer, postgres.StdTxOpts)
+	names := make([]string, 0, len(services))
+	gateways := make([]registry.GatewayFunc, 0, len(services))
+
+	for _, svc := range services {
+		interceptors := make([]connect.Interceptor, 0, len(shared)+len(svc.Interceptors()))
+		interceptors = append(interceptors, shared...)
+		interceptors = append(interceptors, svc.Interceptors()...)
+
+		api.Handle(svc.Handler()(connect.WithInterceptors(interceptors...)))
+		names = append(names, svc.Name())
+
+		if gw := svc.Gateway(); gw != nil {
+			gateways = append(gateways, gw)
+		}
 	}
 
-	otelzap.L().Info("Scaffolding opts")
-	opts := connect.WithInterceptors(
-		otelconnect.NewInterceptor(),
-		transaction.New(reader),
-		transaction.New(writer),
-	)
+	reflector := grpcReflect.NewStaticReflector(names...)
 
 	api.Handle(grpcReflect.NewHandlerV1(reflector))
 	api.Handle(grpcReflect.NewHandlerV1Alpha(reflector))
-	api.Handle(usersConnect.NewUserServiceHandler(users, opts))
 
 	mux.Handle("/", api)
-	mux.Handle("/api/metrics", promhttp.Handler())
-	mux.Handle("/api/status", health.NewHandler())
+	mux.Handle("/api/metrics", access.Log(promhttp.Handler()))
+	mux.Handle("/api/status", access.Log(health.NewHandler()))
+
+	if cfg.Admin.Token != "" {
+		mux.Handle("/api/admin/log/level", access.Log(admin.Handler(cfg.Admin.Token, logger.LevelsHandler())))
+	}
+
+	if len(gateways) > 0 {
+		if err := Gateway(ctx, cfg, gateway, gateways...); err != nil {
+			otelzap.L().Warn("Gateway Registration Error", zap.Error(err))
+		} else {
+			mux.Handle("/v1/", access.Log(gateway))
+		}
+	}
+
+	return CORS(cfg.CORS).Handler(access.RequestID(recovery.Handler(mux)))
+}
+
+// Headers sent by the Connect, gRPC-Web and gateway clients, on top of those configured.
+var (
+	corsAllowedHeaders = []string{
+		"Accept", "Authorization", "Content-Type", "X-Request-Id", "X-User-Agent", impersonate.Header,
+		"Connect-Protocol-Version", "Connect-Timeout-Ms", "Connect-Accept-Encoding", "Connect-Content-Encoding",
+		"Grpc-Timeout", "Grpc-Accept-Encoding", "Grpc-Encoding", "X-Grpc-Web",
+	}
+
+	corsExposedHeaders = []string{
+		"X-Request-Id", impersonate.UserHeader, impersonate.ActorHeader, "Content-Encoding", "Connect-Accept-Encoding", "Connect-Content-Encoding",
+		"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin", "Grpc-Accept-Encoding", "Grpc-Encoding",
+	}
+)
+
+// CORS returns the CORS policy for cfg, see config.CORSConfig.
+func CORS(cfg config.CORSConfig) *cors.Cors {
+	opts := cors.Options{
+		AllowedOrigins:   cfg.GetAllowedOrigins(),
+		AllowedMethods:   cfg.GetAllowedMethods(),
+		AllowedHeaders:   append(append([]string{}, corsAllowedHeaders...), cfg.GetAllowedHeaders()...),
+		ExposedHeaders:   append(append([]string{}, corsExposedHeaders...), cfg.GetExposedHeaders()...),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(time.Duration(cfg.MaxAge).Seconds()),
+	}
+
+	// No origins would otherwise allow every one.
+	if len(opts.AllowedOrigins) == 0 {
+		opts.AllowOriginFunc = func(string) bool { return false }
+	}
+
+	return cors.New(opts)
+}
+
+// headers forwards the request ID and impersonation to the RPCs proxied by the gateway, besides the headers it
+// forwards by default.
+func headers(key string) (string, bool) {
+	switch http.CanonicalHeaderKey(key) {
+	case access.Header, impersonate.Header:
+		return key, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// flags returns the headers flagging impersonation as is, rather than prefixed like other response metadata.
+func flags(key string) (string, bool) {
+	switch name := http.CanonicalHeaderKey(key); name {
+	case impersonate.UserHeader, impersonate.ActorHeader:
+		return name, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// Gateway registers the REST gateway routes against a shared connection back to the server's own gRPC endpoint, which
+// is closed once ctx is done.
+func Gateway(ctx context.Context, cfg *config.BackendConfig, mux *runtime.ServeMux, gateways ...registry.GatewayFunc) error {
+	conn, err := grpc.DialContext(
+		ctx,
+		cfg.Host+":"+cfg.HttpPort,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, register := range gateways {
+		if err := register(ctx, mux, conn); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := conn.Close(); err != nil {
+			otelzap.L().Warn("Gateway Connection Close Error", zap.Error(err))
+		}
+	}()
 
-	return cors.AllowAll().Handler(mux)
+	return nil
 }
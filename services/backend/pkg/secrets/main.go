@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// Error strings
+var (
+	ErrUnknownProvider = errors.New("no secret provider is registered for the scheme")
+	ErrInvalidRef      = errors.New("secret references must look like `<scheme>://<name>`")
+)
+
+// Provider resolves secrets held by a store, eg: a mounted volume or a secrets manager. Name is the part of a
+// reference after `<scheme>://`.
+type Provider interface {
+	Resolve(ctx context.Context, name string) (string, error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Resolve calls f.
+func (f ProviderFunc) Resolve(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"file": &File{},
+		"env":  Env{},
+	}
+)
+
+// Register makes a provider available for references with the given scheme, replacing any already registered. It
+// is typically called from main, or an init function, before the config is loaded.
+func Register(scheme string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	providers[scheme] = p
+}
+
+// Resolve looks a reference up with the provider registered for its scheme, eg: `file:///var/run/secrets/password`.
+func Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, name, ok := strings.Cut(ref, "://")
+	if !ok || scheme == "" || name == "" {
+		return "", fmt.Errorf("%w, got %q", ErrInvalidRef, ref)
+	}
+
+	mu.RLock()
+	p, ok := providers[scheme]
+	mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownProvider, scheme)
+	}
+
+	return p.Resolve(ctx, name)
+}
+
+// Secret is a value resolved from a reference whenever it is read, so rotations are picked up without a restart.
+// Without a reference the fallback is used as is.
+type Secret struct {
+	ref      string
+	fallback string
+
+	mu   sync.Mutex
+	last string
+	seen bool
+}
+
+// New returns a Secret for ref, or for the fallback if ref is empty.
+func New(ref string, fallback string) *Secret {
+	return &Secret{ref: ref, fallback: fallback}
+}
+
+// Ref returns the reference the secret is resolved from, if any.
+func (s *Secret) Ref() string {
+	return s.ref
+}
+
+// Value resolves the secret, logging when it differs from the value last resolved.
+func (s *Secret) Value(ctx context.Context) (string, error) {
+	if s.ref == "" {
+		return s.fallback, nil
+	}
+
+	v, err := Resolve(ctx, s.ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving secret %s: %w", s.ref, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen && v != s.last {
+		otelzap.L().Ctx(ctx).Info("Secret rotated", zap.String("ref", s.ref))
+	}
+
+	s.last, s.seen = v, true
+
+	return v, nil
+}
+
+// File reads secrets from files, eg: Kubernetes secret volume mounts. A file is only re-read once its size or
+// modification time changes. Trailing newlines are trimmed.
+type File struct {
+	mu    sync.Mutex
+	cache map[string]fileEntry
+}
+
+type fileEntry struct {
+	size    int64
+	modTime time.Time
+	value   string
+}
+
+// Resolve reads the file at path.
+func (f *File) Resolve(ctx context.Context, path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if e, ok := f.cache[path]; ok && e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+		return e.value, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	if f.cache == nil {
+		f.cache = map[string]fileEntry{}
+	}
+
+	value := strings.TrimRight(string(b), "\r\n")
+	f.cache[path] = fileEntry{size: info.Size(), modTime: info.ModTime(), value: value}
+
+	return value, nil
+}
+
+// Env reads secrets from environment variables.
+type Env struct{}
+
+// Resolve reads the named variable, failing if it is unset.
+func (Env) Resolve(ctx context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return v, nil
+}
+
+// Static serves secrets from memory, eg: to stand in for a secrets manager when testing.
+type Static map[string]string
+
+// Resolve returns the named secret, failing if there is none.
+func (s Static) Resolve(ctx context.Context, name string) (string, error) {
+	v, ok := s[name]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", name)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,173 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/secrets"
+)
+
+// rotate writes value to path, then sets its modification time, which the File provider checks along with its size.
+func rotate(t *testing.T, path, value string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+		t.Fatalf("writing secret: %v", err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("setting modification time: %v", err)
+	}
+}
+
+func resolve(t *testing.T, p secrets.Provider, name string) string {
+	t.Helper()
+
+	v, err := p.Resolve(context.Background(), name)
+	if err != nil {
+		t.Fatalf("Resolve %s: %v", name, err)
+	}
+
+	return v
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rotate(t, path, "first\n", at)
+
+	f := &secrets.File{}
+	if v := resolve(t, f, path); v != "first" {
+		t.Fatalf("expected the trailing newline to be trimmed, got %q", v)
+	}
+
+	// Neither the size nor the modification time changed, so the cached value is served.
+	rotate(t, path, "other\n", at)
+
+	if v := resolve(t, f, path); v != "first" {
+		t.Fatalf("expected the cached value while the file looks unchanged, got %q", v)
+	}
+
+	rotate(t, path, "second\r\n", at.Add(time.Minute))
+
+	if v := resolve(t, f, path); v != "second" {
+		t.Fatalf("expected a newer file to be re-read, got %q", v)
+	}
+
+	rotate(t, path, "third-and-longer", at.Add(time.Minute))
+
+	if v := resolve(t, f, path); v != "third-and-longer" {
+		t.Fatalf("expected a resized file to be re-read, got %q", v)
+	}
+
+	if _, err := f.Resolve(context.Background(), filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing file to fail, got %v", err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("PERSPEX_TEST_SECRET", "from-env")
+
+	if v := resolve(t, secrets.Env{}, "PERSPEX_TEST_SECRET"); v != "from-env" {
+		t.Fatalf("expected the variable's value, got %q", v)
+	}
+
+	if _, err := (secrets.Env{}).Resolve(context.Background(), "PERSPEX_TEST_UNSET_SECRET"); err == nil {
+		t.Fatal("expected an unset variable to fail")
+	}
+}
+
+func TestStatic(t *testing.T) {
+	s := secrets.Static{"db": "pass"}
+
+	if v := resolve(t, s, "db"); v != "pass" {
+		t.Fatalf("expected the held secret, got %q", v)
+	}
+
+	if _, err := s.Resolve(context.Background(), "redis"); err == nil {
+		t.Fatal("expected a missing secret to fail")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	secrets.Register("test", secrets.Static{"db/password": "from-static"})
+	secrets.Register("calls", secrets.ProviderFunc(func(ctx context.Context, name string) (string, error) {
+		return "called with " + name, nil
+	}))
+
+	t.Setenv("PERSPEX_TEST_SECRET", "from-env")
+
+	cases := map[string]struct {
+		ref  string
+		want string
+		err  error
+	}{
+		"Registered":      {ref: "test://db/password", want: "from-static"},
+		"ProviderFunc":    {ref: "calls://anything", want: "called with anything"},
+		"Builtin":         {ref: "env://PERSPEX_TEST_SECRET", want: "from-env"},
+		"UnknownProvider": {ref: "vault://db/password", err: secrets.ErrUnknownProvider},
+		"NoScheme":        {ref: "db/password", err: secrets.ErrInvalidRef},
+		"NoName":          {ref: "test://", err: secrets.ErrInvalidRef},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			v, err := secrets.Resolve(context.Background(), c.ref)
+			if !errors.Is(err, c.err) || v != c.want {
+				t.Fatalf("expected %q (%v), got %q (%v)", c.want, c.err, v, err)
+			}
+		})
+	}
+}
+
+func TestSecretValue(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	t.Cleanup(otelzap.ReplaceGlobals(otelzap.New(zap.New(core))))
+
+	held := secrets.Static{"password": "first"}
+	secrets.Register("rotating", held)
+
+	s := secrets.New("rotating://password", "fallback")
+
+	for _, want := range []string{"first", "first"} {
+		if v, err := s.Value(context.Background()); err != nil || v != want {
+			t.Fatalf("expected %q, got %q (%v)", want, v, err)
+		}
+	}
+
+	if n := logs.FilterMessage("Secret rotated").Len(); n != 0 {
+		t.Fatalf("expected no rotation to be logged while the value is unchanged, got %d", n)
+	}
+
+	held["password"] = "second"
+
+	if v, err := s.Value(context.Background()); err != nil || v != "second" {
+		t.Fatalf("expected the rotated value, got %q (%v)", v, err)
+	}
+
+	rotated := logs.FilterMessage("Secret rotated").All()
+	if len(rotated) != 1 || rotated[0].ContextMap()["ref"] != "rotating://password" {
+		t.Fatalf("expected the rotation to be logged once with its reference, got %v", rotated)
+	}
+
+	if v, err := secrets.New("", "fallback").Value(context.Background()); err != nil || v != "fallback" {
+		t.Fatalf("expected the fallback without a reference, got %q (%v)", v, err)
+	}
+
+	delete(held, "password")
+
+	if _, err := s.Value(context.Background()); err == nil {
+		t.Fatal("expected a secret that no longer resolves to fail")
+	}
+}
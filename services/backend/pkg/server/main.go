@@ -8,54 +8,225 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 	"go.uber.org/zap"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	apikeyRepository "github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository"
+	apikeyMemory "github.com/jmandel1027/perspex/services/backend/pkg/apikey/repository/memory"
+	apikeyService "github.com/jmandel1027/perspex/services/backend/pkg/apikey/service"
+	auditRepository "github.com/jmandel1027/perspex/services/backend/pkg/audit/repository"
+	auditMemory "github.com/jmandel1027/perspex/services/backend/pkg/audit/repository/memory"
+	auditService "github.com/jmandel1027/perspex/services/backend/pkg/audit/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/authz"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
 	config "github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/migrate"
 	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
 	"github.com/jmandel1027/perspex/services/backend/pkg/logger"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	transaction "github.com/jmandel1027/perspex/services/backend/pkg/middleware/postgres"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	orgMemory "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository/memory"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	outboxRepository "github.com/jmandel1027/perspex/services/backend/pkg/outbox/repository"
+	outboxMemory "github.com/jmandel1027/perspex/services/backend/pkg/outbox/repository/memory"
+	outboxSink "github.com/jmandel1027/perspex/services/backend/pkg/outbox/sink"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
 	"github.com/jmandel1027/perspex/services/backend/pkg/router"
+	"github.com/jmandel1027/perspex/services/backend/pkg/telemetry"
+	userRepository "github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	userMemory "github.com/jmandel1027/perspex/services/backend/pkg/user/repository/memory"
+	userService "github.com/jmandel1027/perspex/services/backend/pkg/user/service"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook"
+	webhookRepository "github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+	webhookMemory "github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository/memory"
+	webhookService "github.com/jmandel1027/perspex/services/backend/pkg/webhook/service"
 )
 
-func Serve() {
-
-	cfg, err := config.New()
-	if err != nil {
-		otelzap.L().Warn("Config Error: %s", zap.Error(err))
-	}
-
+// Serve builds the services selected by cfg and serves them until interrupted.
+func Serve(cfg config.BackendConfig) {
 	z := logger.New(cfg)
 	defer z.Sync()
 
 	undo := logger.ReplaceGlobals(z)
 	defer undo()
 
-	dbs, err := postgres.Open(&cfg)
+	flush, err := telemetry.Setup(context.Background(), cfg.Telemetry)
+	if err != nil {
+		otelzap.L().Warn("Telemetry Setup Error", zap.Error(err))
+	}
+
+	clk := clock.New()
+
+	var dbs *postgres.DB
+	var members orgRepository.IMembershipRepository
+	var people userRepository.IUserRepository
+	var keys apikeyRepository.IAPIKeyRepository
+	var published outboxRepository.IEventRepository
+	var hooks webhookRepository.IWebhookRepository
+	var hub *watch.Hub
+	var services []registry.Service
+
+	switch cfg.Storage {
+	case config.StorageMemory:
+		otelzap.L().Info("Using in-memory storage, data will not be persisted")
+
+		events := auditMemory.NewEventRepository(clk)
+		published = outboxMemory.NewEventRepository(clk)
+		members = orgMemory.NewMembershipRepository(events, published)
+		repo := userMemory.NewUserRepository(clk, events, published, members)
+		people = repo
+		hub = watch.NewHub(repo, logger.Named("watch"), clk, cfg.Watch)
+		users := userService.NewUserService(repo, hub, members, logger.Named("user"))
+		keys = apikeyMemory.NewAPIKeyRepository(clk, events)
+		apikeys := apikeyService.NewApiKeyService(keys, members, clk, logger.Named("apikey"))
+		hooks = webhookMemory.NewWebhookRepository(clk)
+
+		services = append(services,
+			userService.Register(users),
+			apikeyService.Register(apikeys),
+			auditService.Register(auditService.NewAuditService(events, logger.Named("audit"))),
+			webhookService.Register(webhookService.NewWebhookService(hooks, members, logger.Named("webhook"), cfg.Webhooks)),
+		)
+	default:
+		// Connections are dialled lazily, so Open only fails on settings that could never connect.
+		dbs, err = postgres.Open(&cfg)
+		if err != nil {
+			otelzap.L().Fatal("Postgres Connection Error", zap.Error(err))
+		}
+
+		Instrument(dbs)
+
+		if cfg.AutoMigrate {
+			Migrate(dbs)
+		}
+
+		events := auditRepository.NewEventRepository(dbs, logger.Named("audit"), clk)
+		published = outboxRepository.NewEventRepository(dbs, logger.Named("outbox"), clk)
+		members = orgRepository.NewMembershipRepository(dbs, logger.Named("organization"), events, published)
+		repo := userRepository.NewUserRepository(&cfg, dbs, logger.Named("user"), cache.NewNop(), clk, events, published)
+		people = repo
+		hub = watch.NewHub(repo, logger.Named("watch"), clk, cfg.Watch)
+		users := userService.NewUserService(repo, hub, members, logger.Named("user"))
+		keys = apikeyRepository.NewAPIKeyRepository(dbs, logger.Named("apikey"), clk, events)
+		apikeys := apikeyService.NewApiKeyService(keys, members, clk, logger.Named("apikey"))
+		hooks = webhookRepository.NewWebhookRepository(dbs, logger.Named("webhook"), clk)
+
+		services = append(services,
+			userService.Register(users, transaction.Interceptors(dbs)...),
+			apikeyService.Register(apikeys, transaction.Interceptors(dbs)...),
+			auditService.Register(auditService.NewAuditService(events, logger.Named("audit")), transaction.Interceptors(dbs)...),
+			webhookService.Register(webhookService.NewWebhookService(hooks, members, logger.Named("webhook"), cfg.Webhooks), transaction.Interceptors(dbs)...),
+		)
+	}
+
+	// A broken policy could let callers through, so the backend refuses to serve without a sound one.
+	policy, err := authz.Load(cfg.Authz, members, people)
 	if err != nil {
-		otelzap.L().Warn("Postgres Connection Error: %s", zap.Error(err))
+		otelzap.L().Fatal("Authorization Policy Error", zap.Error(err))
 	}
 
-	go HTTP(&cfg, dbs)
+	// Further authenticators, such as for JWTs, slot in alongside API keys.
+	authn := auth.New(policy.Authenticate(auth.First(apikeyService.Authenticate(keys, clk, logger.Named("apikey")))))
+
+	Relay(&cfg, published, hooks, members, clk)
+	Deliver(&cfg, hooks, clk)
+
+	go hub.Run(context.Background())
+
+	go HTTP(&cfg, dbs, flush, authn, policy, services...)
 
 	select {}
 }
 
-// HTTP server
-func HTTP(cfg *config.BackendConfig, dbs *postgres.DB) {
+// Instrument exports the stats of the writer and reader pools to Prometheus.
+func Instrument(dbs *postgres.DB) {
+	if err := metrics.RegisterPool(prometheus.DefaultRegisterer, "writer", dbs.Writer, dbs.WriterPool); err != nil {
+		otelzap.L().Warn("Writer Metrics Registration Error", zap.Error(err))
+	}
+
+	if err := metrics.RegisterPool(prometheus.DefaultRegisterer, "reader", dbs.Reader, dbs.ReaderPool); err != nil {
+		otelzap.L().Warn("Reader Metrics Registration Error", zap.Error(err))
+	}
+}
+
+// Relay relays domain events from the outbox in the background, to the sink selected by cfg and, while webhooks are
+// enabled, to the endpoints of the organizations they concern. It does not run when there is nowhere to relay them.
+// Events are published at least once, so those in flight when the process exits are published again by the next
+// relay.
+func Relay(cfg *config.BackendConfig, source outbox.Source, hooks webhookRepository.IWebhookRepository, members webhook.Memberships, clk clock.Clock) {
+	var sinks []outbox.Sink
+
+	switch cfg.Outbox.Sink {
+	case "redis":
+		sinks = append(sinks, outboxSink.NewRedis(cfg.Redis, cfg.Outbox.Stream))
+	case "webhook":
+		sinks = append(sinks, outboxSink.NewWebhook(cfg.Outbox.WebhookURL, nil))
+	}
+
+	if cfg.Webhooks.Enabled {
+		sinks = append(sinks, webhook.NewDispatcher(hooks, members))
+	}
+
+	if len(sinks) == 0 {
+		return
+	}
+
+	relay := outbox.NewRelay(source, outbox.Sinks(sinks...), logger.Named("outbox"), clk, cfg.Outbox)
+
+	go relay.Run(context.Background())
+}
+
+// Deliver delivers webhooks in the background, unless they are disabled.
+func Deliver(cfg *config.BackendConfig, hooks webhookRepository.IWebhookRepository, clk clock.Clock) {
+	if !cfg.Webhooks.Enabled {
+		return
+	}
+
+	worker := webhook.NewWorker(hooks, nil, logger.Named("webhook"), clk, cfg.Webhooks)
+
+	go worker.Run(context.Background())
+}
+
+// Migrate applies pending migrations before serving, exiting if they fail.
+func Migrate(dbs *postgres.DB) {
+	ctx := context.Background()
+
+	migrator, err := migrate.Embedded(dbs.Writer)
+	if err != nil {
+		otelzap.L().Ctx(ctx).Fatal("Loading Migrations Failed:", zap.Error(err))
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		otelzap.L().Ctx(ctx).Fatal("Migration Failed:", zap.Error(err))
+	}
+
+	otelzap.L().Ctx(ctx).Info("Migrations Applied")
+}
+
+// HTTP server, flushing telemetry once it has shut down.
+func HTTP(cfg *config.BackendConfig, dbs *postgres.DB, flush telemetry.ShutdownFunc, authn *auth.Interceptor, policy *authz.Policy, services ...registry.Service) {
 	ctx := context.Background()
 
 	otelzap.L().Ctx(ctx).Info("Scaffolded global logger")
 
-	rtr := router.Route(cfg, dbs)
+	// Serving lasts until the server has shut down, and with it the gateway's connection back to the server.
+	serving, stop := context.WithCancel(context.Background())
+
+	rtr := router.Route(serving, cfg, authn, policy, services...)
 
 	srv := &http.Server{
 		Addr:           cfg.Host + ":" + cfg.HttpPort,
-		WriteTimeout:   time.Minute * 5,
-		ReadTimeout:    time.Minute * 5,
-		IdleTimeout:    time.Minute * 5,
-		MaxHeaderBytes: 8 * 1024, // 8KiB
+		WriteTimeout:   time.Duration(cfg.HTTP.WriteTimeout),
+		ReadTimeout:    time.Duration(cfg.HTTP.ReadTimeout),
+		IdleTimeout:    time.Duration(cfg.HTTP.IdleTimeout),
+		MaxHeaderBytes: int(cfg.HTTP.MaxHeaderBytes),
 		Handler:        h2c.NewHandler(rtr, &http2.Server{}),
 	}
 
@@ -72,7 +243,7 @@ func HTTP(cfg *config.BackendConfig, dbs *postgres.DB) {
 		}
 	}()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60000*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.HTTP.ShutdownTimeout))
 
 	// when the shutoff signal is received, the lock will release
 	// and anything below done will run.
@@ -80,12 +251,24 @@ func HTTP(cfg *config.BackendConfig, dbs *postgres.DB) {
 
 	otelzap.L().Ctx(ctx).Info("Backend Server Stopped")
 
+	// Deferred calls run last in first out, so the process exits only once everything below is closed and flushed.
+	defer os.Exit(0)
+
 	defer func() {
 		// Here is where we'd safely close out any connections
 		// eg: redis, etc. Except for Postgres, we need to allow that package to manage
 		// It's own lifecycle.
-		dbs.Writer.Close()
-		dbs.Reader.Close()
+		stop()
+
+		if dbs != nil {
+			dbs.Close()
+		}
+
+		if flush != nil {
+			if err := flush(ctx); err != nil {
+				otelzap.L().Warn("Telemetry Flush Error", zap.Error(err))
+			}
+		}
 
 		cancel()
 	}()
@@ -95,6 +278,4 @@ func HTTP(cfg *config.BackendConfig, dbs *postgres.DB) {
 	}
 
 	otelzap.L().Ctx(ctx).Info("Server Exited Properly")
-
-	defer os.Exit(0)
 }
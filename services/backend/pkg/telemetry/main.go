@@ -0,0 +1,275 @@
+package telemetry
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/metrics"
+	"github.com/jmandel1027/perspex/services/backend/pkg/redact"
+)
+
+// Name identifies the backend's own instrumentation.
+const Name = "github.com/jmandel1027/perspex/services/backend"
+
+// ShutdownFunc flushes and stops the exporters.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup installs the global tracer and meter providers and the W3C trace context propagator, exporting as cfg
+// selects. The returned function must be called before exiting, so buffered spans and metrics are not lost.
+func Setup(ctx context.Context, cfg config.TelemetryConfig) (ShutdownFunc, error) {
+	res, err := Resource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var shutdowns []ShutdownFunc
+
+	// Every provider is shut down, returning the first error.
+	shutdown := func(ctx context.Context) error {
+		var first error
+		for _, f := range shutdowns {
+			if err := f(ctx); err != nil && first == nil {
+				first = err
+			}
+		}
+
+		return first
+	}
+
+	spans, err := traceExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	if spans != nil {
+		opts = append(opts, sdktrace.WithBatcher(redact.Exporter(spans)))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	shutdowns = append(shutdowns, tp.Shutdown)
+
+	readings, err := metricExporter(ctx, cfg)
+	if err != nil {
+		shutdown(ctx)
+		return nil, err
+	}
+
+	if readings != nil {
+		mp := sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(readings, sdkmetric.WithInterval(time.Duration(cfg.MetricInterval)))),
+		)
+
+		shutdowns = append(shutdowns, mp.Shutdown)
+		global.SetMeterProvider(mp)
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return shutdown, nil
+}
+
+// Resource describes the running backend: its service name and version, and the pod it runs in, if known. The
+// attributes in `OTEL_RESOURCE_ATTRIBUTES` are included too.
+func Resource(ctx context.Context, cfg config.TelemetryConfig) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+		semconv.ServiceVersionKey.String(Version(cfg)),
+	}
+
+	for key, v := range map[attribute.Key]string{
+		semconv.K8SPodNameKey:       cfg.PodName,
+		semconv.K8SNamespaceNameKey: cfg.PodNamespace,
+		semconv.K8SNodeNameKey:      cfg.NodeName,
+	} {
+		if v != "" {
+			attrs = append(attrs, key.String(v))
+		}
+	}
+
+	return resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+	)
+}
+
+// Version returns the configured service version, or else the VCS revision the binary was built from.
+func Version(cfg config.TelemetryConfig) string {
+	if cfg.ServiceVersion != "" {
+		return cfg.ServiceVersion
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+
+	return info.Main.Version
+}
+
+func traceExporter(ctx context.Context, cfg config.TelemetryConfig) (sdktrace.SpanExporter, error) {
+	headers, err := cfg.GetHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.TracesExporter {
+	case "console":
+		return stdouttrace.New()
+	case "otlp":
+		if cfg.Protocol == "http/protobuf" {
+			opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(headers)}
+			if cfg.Endpoint != "" {
+				opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+			}
+
+			if cfg.Insecure {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+
+			return otlptracehttp.New(ctx, opts...)
+		}
+
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(headers)}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, nil
+	}
+}
+
+func metricExporter(ctx context.Context, cfg config.TelemetryConfig) (sdkmetric.Exporter, error) {
+	if cfg.MetricsExporter != "otlp" {
+		return nil, nil
+	}
+
+	headers, err := cfg.GetHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Protocol == "http/protobuf" {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithHeaders(headers)}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithHeaders(headers)}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+	}
+
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// Tracer returns the backend's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+// QueryTracer records every statement run on a connection as a span, see pgx.ConnConfig.Tracer. Statements are only
+// traced within an existing trace, such as an RPC's, so the pool's own housekeeping does not start traces of its own.
+type QueryTracer struct{}
+
+type spanKey struct{}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	table, operation := metrics.Statement(data.SQL)
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBNameKey.String(conn.Config().Database),
+		semconv.DBStatementKey.String(data.SQL),
+		semconv.DBOperationKey.String(operation),
+		semconv.NetPeerNameKey.String(conn.Config().Host),
+	}
+
+	name := strings.ToUpper(operation)
+	if table != "" {
+		attrs = append(attrs, semconv.DBSQLTableKey.String(table))
+		name += " " + table
+	}
+
+	ctx, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	End(span, data.Err)
+}
+
+// End ends span, recording err if it is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,166 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	connect "github.com/bufbuild/connect-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/harness"
+	"github.com/jmandel1027/perspex/services/backend/pkg/telemetry"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(t.Name())
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	telemetry.End(ok, nil)
+
+	_, failed := tracer.Start(context.Background(), "failed")
+	telemetry.End(failed, errors.New("connection reset"))
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 ended spans, got %d", len(ended))
+	}
+
+	if ended[0].Status().Code != codes.Unset {
+		t.Fatalf("expected a span ended without error to have no status, got %v", ended[0].Status())
+	}
+
+	if status := ended[1].Status(); status.Code != codes.Error || status.Description != "connection reset" {
+		t.Fatalf("expected a span ended with an error to have an error status, got %v", status)
+	}
+
+	if events := ended[1].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("expected the error to be recorded, got %v", events)
+	}
+}
+
+func TestResource(t *testing.T) {
+	res, err := telemetry.Resource(context.Background(), config.TelemetryConfig{
+		ServiceName:    "perspex-test",
+		ServiceVersion: "1.2.3",
+		PodName:        "backend-0",
+	})
+	if err != nil {
+		t.Fatalf("Resource: %v", err)
+	}
+
+	want := map[string]string{
+		string(semconv.ServiceNameKey):    "perspex-test",
+		string(semconv.ServiceVersionKey): "1.2.3",
+		string(semconv.K8SPodNameKey):     "backend-0",
+	}
+
+	for _, kv := range res.Attributes() {
+		if v, ok := want[string(kv.Key)]; ok {
+			if kv.Value.AsString() != v {
+				t.Fatalf("expected %s to be %q, got %q", kv.Key, v, kv.Value.AsString())
+			}
+
+			delete(want, string(kv.Key))
+		}
+	}
+
+	if len(want) > 0 {
+		t.Fatalf("expected the resource to have %v", want)
+	}
+}
+
+func TestSetupSamples(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	for _, ratio := range []float64{0, 1} {
+		shutdown, err := telemetry.Setup(context.Background(), config.TelemetryConfig{
+			TracesExporter:  "none",
+			MetricsExporter: "none",
+			SampleRatio:     ratio,
+			ServiceName:     "perspex-test",
+		})
+		if err != nil {
+			t.Fatalf("Setup: %v", err)
+		}
+
+		_, span := otel.Tracer(t.Name()).Start(context.Background(), "root")
+		span.End()
+
+		if sampled := span.SpanContext().IsSampled(); sampled != (ratio == 1) {
+			t.Fatalf("sample ratio %v: expected sampled to be %v", ratio, ratio == 1)
+		}
+
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+	}
+}
+
+// TestSpanTree checks that the statements of an RPC, and the beginning and end of its transaction, are traced as
+// children of the RPC's span.
+func TestSpanTree(t *testing.T) {
+	pg := harness.Require(t)
+	spans := harness.Spans(t)
+	h := pg.New(t)
+
+	_, err := h.Users.RegisterUser(context.Background(), connect.NewRequest(&users.RegisterUserRequest{
+		User: &users.User{Email: "traced@perspex.us", FirstName: "Jane", LastName: "Doe"},
+	}))
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+
+	var rpc tracetest.SpanStub
+	for _, s := range spans.GetSpans() {
+		if s.SpanKind == trace.SpanKindServer && strings.HasSuffix(s.Name, "/RegisterUser") {
+			rpc = s
+		}
+	}
+
+	if !rpc.SpanContext.IsValid() {
+		t.Fatalf("expected a span for the RPC, got %v", names(spans.GetSpans()))
+	}
+
+	children := map[string]bool{}
+	for _, s := range spans.GetSpans() {
+		if s.Parent.SpanID() == rpc.SpanContext.SpanID() && s.SpanKind == trace.SpanKindClient {
+			children[s.Name] = true
+		}
+	}
+
+	var inserted bool
+	for name := range children {
+		inserted = inserted || (strings.HasPrefix(name, "INSERT ") && strings.HasSuffix(name, "users"))
+	}
+
+	if !children["BEGIN"] || !inserted || !children["COMMIT"] {
+		t.Fatalf("expected BEGIN, INSERT users and COMMIT spans under the RPC's, got %v", names(spans.GetSpans()))
+	}
+}
+
+func names(spans tracetest.SpanStubs) []string {
+	res := make([]string, len(spans))
+	for i, s := range spans {
+		res[i] = s.Name
+	}
+
+	return res
+}
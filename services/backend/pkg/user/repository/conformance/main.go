@@ -0,0 +1,326 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
+)
+
+// Factory returns a new, empty repository for a single subtest.
+type Factory func(t *testing.T) repository.IUserRepository
+
+// Run checks that the repositories built by newRepo honour the repository.IUserRepository contract. Every
+// implementation is expected to pass it, so callers can rely on identical behaviour across storage backends.
+func Run(t *testing.T, newRepo Factory) {
+	t.Run("CreateAssignsIDs", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		first := create(t, repo, "first@perspex.us")
+		second := create(t, repo, "second@perspex.us")
+
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Fatalf("expected increasing ids, got %d then %d", first.ID, second.ID)
+		}
+
+		if first.CreatedAt.IsZero() || first.UpdatedAt.IsZero() {
+			t.Fatalf("expected timestamps to be set, got %+v", first)
+		}
+
+		found, err := repo.FindUserById(ctx, first.ID)
+		if err != nil {
+			t.Fatalf("FindUserById: %v", err)
+		}
+
+		if found == nil || found.Email != first.Email {
+			t.Fatalf("expected to find %q, got %+v", first.Email, found)
+		}
+	})
+
+	t.Run("UniqueEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		existing := create(t, repo, "taken@perspex.us")
+		create(t, repo, "other@perspex.us")
+
+		_, err := repo.CreateUser(ctx, user("taken@perspex.us"))
+		if !errors.Is(err, repository.ErrEmailTaken) {
+			t.Fatalf("CreateUser with a taken email: expected ErrEmailTaken, got %v", err)
+		}
+
+		changed := *existing
+		changed.Email = "other@perspex.us"
+
+		_, err = repo.UpdateUser(ctx, &changed)
+		if !errors.Is(err, repository.ErrEmailTaken) {
+			t.Fatalf("UpdateUser to a taken email: expected ErrEmailTaken, got %v", err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		found, err := repo.FindUserById(ctx, 4242)
+		if err != nil || found != nil {
+			t.Fatalf("FindUserById for a missing user: expected nil, nil, got %+v, %v", found, err)
+		}
+
+		missing := user("missing@perspex.us")
+		missing.ID = 4242
+
+		_, err = repo.UpdateUser(ctx, missing)
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			t.Fatalf("UpdateUser for a missing user: expected ErrUserNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		existing := create(t, repo, "before@perspex.us")
+
+		// Finding the user first lets implementations that cache users show that they evict them on update.
+		if _, err := repo.FindUserById(ctx, existing.ID); err != nil {
+			t.Fatalf("FindUserById: %v", err)
+		}
+
+		changed := *existing
+		changed.Email = "after@perspex.us"
+		changed.LastName = "Changed"
+
+		if _, err := repo.UpdateUser(ctx, &changed); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		found, err := repo.FindUserById(ctx, existing.ID)
+		if err != nil {
+			t.Fatalf("FindUserById: %v", err)
+		}
+
+		if found == nil || found.Email != "after@perspex.us" || found.LastName != "Changed" {
+			t.Fatalf("expected the update to be persisted, got %+v", found)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		existing := create(t, repo, "deleted@perspex.us")
+		kept := create(t, repo, "kept@perspex.us")
+
+		// Finding the user first lets implementations that cache users show that they evict them on deletion.
+		if _, err := repo.FindUserById(ctx, existing.ID); err != nil {
+			t.Fatalf("FindUserById: %v", err)
+		}
+
+		deleted, err := repo.DeleteUser(ctx, existing.ID)
+		if err != nil || deleted.Email != existing.Email {
+			t.Fatalf("expected the deleted user, got %+v (%v)", deleted, err)
+		}
+
+		if found, err := repo.FindUserById(ctx, existing.ID); err != nil || found != nil {
+			t.Fatalf("expected the user to be gone, got %+v (%v)", found, err)
+		}
+
+		if _, err := repo.DeleteUser(ctx, existing.ID); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Fatalf("DeleteUser for a missing user: expected ErrUserNotFound, got %v", err)
+		}
+
+		if n, err := repo.CountUsers(ctx); err != nil || n != 1 {
+			t.Fatalf("expected the other user to remain, got %d (%v)", n, err)
+		}
+
+		// The email is free again.
+		create(t, repo, existing.Email)
+
+		source, ok := repo.(watch.Source)
+		if !ok {
+			return
+		}
+
+		changes, err := source.Changes(ctx, 0, 10)
+		if err != nil || len(changes) != 4 {
+			t.Fatalf("expected the 3 creations and the deletion, got %v (%v)", changes, err)
+		}
+
+		if c := changes[2]; c.UserID != existing.ID || c.Operation != watch.OperationDeleted || c.User != nil {
+			t.Fatalf("expected the deletion without the user, got %+v", c)
+		}
+
+		if changes[1].UserID != kept.ID || changes[1].User == nil {
+			t.Fatalf("expected the other user as it stands, got %+v", changes[1])
+		}
+	})
+
+	t.Run("FindUsersByIds", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		a := create(t, repo, "a@perspex.us")
+		b := create(t, repo, "b@perspex.us")
+		c := create(t, repo, "c@perspex.us")
+
+		found, err := repo.FindUsersByIds(ctx, []int64{c.ID, 4242, a.ID})
+		if err != nil {
+			t.Fatalf("FindUsersByIds: %v", err)
+		}
+
+		expectIDs(t, found, a.ID, c.ID)
+
+		found, err = repo.FindUsersByIds(ctx, []int64{b.ID})
+		if err != nil {
+			t.Fatalf("FindUsersByIds: %v", err)
+		}
+
+		expectIDs(t, found, b.ID)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := make([]int64, 5)
+		for i := range ids {
+			ids[i] = create(t, repo, fmt.Sprintf("page-%d@perspex.us", i)).ID
+		}
+
+		pages := []struct {
+			name string
+			page repository.Page
+			want []int64
+		}{
+			{"First", repository.Page{Limit: 2}, ids[:2]},
+			{"After", repository.Page{After: ids[1], Limit: 2}, ids[2:4]},
+			{"AfterPastEnd", repository.Page{After: ids[3], Limit: 3}, ids[4:]},
+			{"Last", repository.Page{Limit: 2, Backward: true}, ids[3:]},
+			{"Before", repository.Page{Before: ids[3], Limit: 2, Backward: true}, ids[1:3]},
+			{"Between", repository.Page{After: ids[0], Before: ids[4], Limit: 5}, ids[1:4]},
+			{"Empty", repository.Page{After: ids[4], Limit: 2}, nil},
+		}
+
+		for _, tc := range pages {
+			found, err := repo.FindUsersPage(ctx, tc.page)
+			if err != nil {
+				t.Fatalf("%s: FindUsersPage: %v", tc.name, err)
+			}
+
+			expectIDs(t, found, tc.want...)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			create(t, repo, fmt.Sprintf("count-%d@perspex.us", i))
+		}
+
+		n, err := repo.CountUsers(ctx)
+		if err != nil {
+			t.Fatalf("CountUsers: %v", err)
+		}
+
+		if n != 3 {
+			t.Fatalf("expected 3 users, got %d", n)
+		}
+	})
+
+	t.Run("Changes", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		source, ok := repo.(watch.Source)
+		if !ok {
+			t.Skip("the repository records no changes")
+		}
+
+		first := create(t, repo, "first@perspex.us")
+		second := create(t, repo, "second@perspex.us")
+
+		renamed := *first
+		renamed.FirstName = "Renamed"
+
+		if _, err := repo.UpdateUser(ctx, &renamed); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		changes, err := source.Changes(ctx, 0, 10)
+		if err != nil {
+			t.Fatalf("Changes: %v", err)
+		}
+
+		if len(changes) != 3 || changes[0].UserID != first.ID || changes[1].UserID != second.ID || changes[2].Operation != watch.OperationUpdated {
+			t.Fatalf("expected the 2 creations then the update, got %v", changes)
+		}
+
+		if changes[0].User == nil || changes[0].User.FirstName != "Renamed" {
+			t.Fatalf("expected the user as it now stands, got %+v", changes[0].User)
+		}
+
+		resumed, err := source.Changes(ctx, changes[0].ID, 1)
+		if err != nil || len(resumed) != 1 || resumed[0].ID != changes[1].ID {
+			t.Fatalf("expected to resume with the second change, got %v (%v)", resumed, err)
+		}
+
+		newest, err := source.Newest(ctx)
+		if err != nil || newest != changes[2].ID {
+			t.Fatalf("expected the update to be the newest change, got %d (%v)", newest, err)
+		}
+
+		// The newest change outlives the retention, so that watchers idle since can still resume.
+		if n, err := source.Prune(ctx, time.Now().Add(time.Hour)); err != nil || n != 2 {
+			t.Fatalf("expected 2 changes to be pruned, got %d (%v)", n, err)
+		}
+
+		if _, err := source.Changes(ctx, changes[0].ID, 10); !errors.Is(err, watch.ErrCursorExpired) {
+			t.Fatalf("expected a pruned cursor to have expired, got %v", err)
+		}
+
+		if rest, err := source.Changes(ctx, newest, 10); err != nil || len(rest) != 0 {
+			t.Fatalf("expected no change after the newest, got %v (%v)", rest, err)
+		}
+	})
+}
+
+func user(email string) *models.User {
+	return &models.User{
+		Email:     email,
+		FirstName: "Perspex",
+		LastName:  "User",
+	}
+}
+
+func create(t *testing.T, repo repository.IUserRepository, email string) *models.User {
+	t.Helper()
+
+	record, err := repo.CreateUser(context.Background(), user(email))
+	if err != nil {
+		t.Fatalf("CreateUser(%q): %v", email, err)
+	}
+
+	return record
+}
+
+func expectIDs(t *testing.T, found []*models.User, want ...int64) {
+	t.Helper()
+
+	got := make([]int64, len(found))
+	for i, u := range found {
+		got[i] = u.ID
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected users %v, got %v", want, got)
+	}
+}
@@ -3,21 +3,61 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	events "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
 	"github.com/jmandel1027/perspex/services/backend/pkg/config"
 	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
 )
 
+// userCacheTTL is how long a user record may be served from the cache.
+const userCacheTTL = time.Minute
+
+// uniqueViolation is the Postgres error code raised when a unique index rejects a write.
+const uniqueViolation = "23505"
+
+// Error strings shared by every IUserRepository implementation.
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email is already registered")
+)
+
+// Page selects a window of users ordered by ascending ID. After and Before are exclusive cursors, ignored when zero.
+// Forward pages hold the first Limit users of the window, backward pages the last Limit users.
+type Page struct {
+	After    int64
+	Before   int64
+	Limit    int
+	Backward bool
+}
+
 // UserRepository --
 type UserRepository struct {
-	cfg *config.BackendConfig
+	cfg    *config.BackendConfig
+	dbs    *postgres.DB
+	log    *otelzap.Logger
+	cache  cache.Cache
+	clock  clock.Clock
+	audit  audit.Recorder
+	outbox outbox.Writer
 }
 
 // IUserRepository is interface for MaterialRepository
@@ -25,28 +65,61 @@ type IUserRepository interface {
 	CreateUser(ctx context.Context, record *models.User) (res *models.User, err error)
 	FindUserById(ctx context.Context, id int64) (res *models.User, err error)
 	FindUsersByIds(ctx context.Context, ids []int64) (res []*models.User, err error)
+	FindUsersPage(ctx context.Context, page Page) (res []*models.User, err error)
+	CountUsers(ctx context.Context) (res int64, err error)
 	UpdateUser(ctx context.Context, record *models.User) (res *models.User, err error)
+	DeleteUser(ctx context.Context, id int64) (res *models.User, err error)
 }
 
-// NewUserRepository Creates a new Material repo instance
-func NewUserRepository() *UserRepository {
-	cfg, _ := config.New()
+var (
+	_ IUserRepository = (*UserRepository)(nil)
+	_ watch.Source    = (*UserRepository)(nil)
+)
+
+// NewUserRepository Creates a new User repo instance
+func NewUserRepository(
+	cfg *config.BackendConfig,
+	dbs *postgres.DB,
+	log *otelzap.Logger,
+	c cache.Cache,
+	clk clock.Clock,
+	rec audit.Recorder,
+	out outbox.Writer,
+) *UserRepository {
 	return &UserRepository{
-		&cfg,
+		cfg:    cfg,
+		dbs:    dbs,
+		log:    log,
+		cache:  c,
+		clock:  clk,
+		audit:  rec,
+		outbox: out,
 	}
 }
 
 // CreateUser register's a new user
 func (repo *UserRepository) CreateUser(ctx context.Context, record *models.User) (res *models.User, err error) {
-	err = postgres.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
-		if err = record.Insert(ctx, tx, boil.Infer()); err != nil {
+	now := repo.clock.Now()
+	record.CreatedAt = now
+	record.UpdatedAt = now
+
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		if err = record.Insert(boil.SkipTimestamps(ctx), tx, boil.Infer()); err != nil {
 			warning := fmt.Sprintf("Couldn't register user: %s", err)
-			otelzap.L().Ctx(ctx).Error(warning)
-			return errors.New(warning)
+			repo.log.Ctx(ctx).Error(warning)
+			if isUniqueViolation(err) {
+				return fmt.Errorf("Couldn't register user: %w", ErrEmailTaken)
+			}
+
+			return fmt.Errorf("Couldn't register user: %w", err)
 		}
 
 		res = record
-		return nil
+		if err := repo.record(ctx, tx, record.ID, nil, record); err != nil {
+			return err
+		}
+
+		return repo.publish(ctx, tx, record.ID, &events.UserRegistered{User: Proto(record)})
 	})
 
 	return
@@ -54,40 +127,106 @@ func (repo *UserRepository) CreateUser(ctx context.Context, record *models.User)
 
 // FindUserById register's a new user
 func (repo *UserRepository) FindUserById(ctx context.Context, id int64) (res *models.User, err error) {
-	err = postgres.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
-		otelzap.L().Ctx(ctx).Info("attempting to fetch user")
+	if res = repo.cached(ctx, id); res != nil {
+		return res, nil
+	}
+
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		repo.log.Ctx(ctx).Info("attempting to fetch user")
 		res, err = models.Users(models.UserWhere.ID.EQ(id)).One(ctx, tx)
 		if err != nil && err == sql.ErrNoRows {
-			otelzap.L().Ctx(ctx).Info("no user found")
+			repo.log.Ctx(ctx).Info("no user found")
 			return nil
 		}
 
 		if err != nil {
 			warning := fmt.Sprintf("Couldn't retrieve user: %s", err)
-			otelzap.L().Ctx(ctx).Error(warning)
-			return errors.New(warning)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't retrieve user: %w", err)
 		}
 
 		return nil
 	})
 
-	otelzap.L().Ctx(ctx).Info("done attempting to find user")
+	if err == nil && res != nil {
+		repo.store(ctx, res)
+	}
+
+	repo.log.Ctx(ctx).Info("done attempting to find user")
 
 	return
 }
 
 // FindUsersByIds finds users by ids
 func (repo *UserRepository) FindUsersByIds(ctx context.Context, ids []int64) (res []*models.User, err error) {
-	err = postgres.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
-		res, err = models.Users(qm.Where("id = ANY ($1)", ids)).All(ctx, tx)
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		res, err = models.Users(qm.Where("id = ANY ($1)", ids), qm.OrderBy(models.UserColumns.ID)).All(ctx, tx)
 		if err != nil && err == sql.ErrNoRows {
 			return nil
 		}
 
 		if err != nil {
 			warning := fmt.Sprintf("Couldn't retrieve users: %s", err)
-			otelzap.L().Ctx(ctx).Error(warning)
-			return errors.New(warning)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't retrieve users: %w", err)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// FindUsersPage finds a page of users ordered by ascending id
+func (repo *UserRepository) FindUsersPage(ctx context.Context, page Page) (res []*models.User, err error) {
+	mods := []qm.QueryMod{qm.Limit(page.Limit)}
+
+	if page.After > 0 {
+		mods = append(mods, models.UserWhere.ID.GT(page.After))
+	}
+
+	if page.Before > 0 {
+		mods = append(mods, models.UserWhere.ID.LT(page.Before))
+	}
+
+	if page.Backward {
+		mods = append(mods, qm.OrderBy(models.UserColumns.ID+" DESC"))
+	} else {
+		mods = append(mods, qm.OrderBy(models.UserColumns.ID))
+	}
+
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		res, err = models.Users(mods...).All(ctx, tx)
+		if err != nil && err == sql.ErrNoRows {
+			return nil
+		}
+
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't retrieve users page: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't retrieve users page: %w", err)
+		}
+
+		return nil
+	})
+
+	if page.Backward {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+
+	return
+}
+
+// CountUsers counts every user
+func (repo *UserRepository) CountUsers(ctx context.Context) (res int64, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		res, err = models.Users().Count(ctx, tx)
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't count users: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't count users: %w", err)
 		}
 
 		return nil
@@ -98,16 +237,339 @@ func (repo *UserRepository) FindUsersByIds(ctx context.Context, ids []int64) (re
 
 // UpdateUser modifies a user
 func (repo *UserRepository) UpdateUser(ctx context.Context, record *models.User) (res *models.User, err error) {
-	err = postgres.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
-		if _, err = record.Update(ctx, tx, boil.Infer()); err != nil {
+	record.UpdatedAt = repo.clock.Now()
+
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		before, err := models.Users(models.UserWhere.ID.EQ(record.ID), qm.For("UPDATE")).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't update user: %w", ErrUserNotFound)
+		}
+
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't update user: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't update user: %w", err)
+		}
+
+		// Callers only supply the fields they modify.
+		record.CreatedAt = before.CreatedAt
+
+		rows, err := record.Update(boil.SkipTimestamps(ctx), tx, boil.Infer())
+		if err != nil {
 			warning := fmt.Sprintf("Couldn't update user: %s", err)
-			otelzap.L().Ctx(ctx).Error(warning)
-			return errors.New(warning)
+			repo.log.Ctx(ctx).Error(warning)
+			if isUniqueViolation(err) {
+				return fmt.Errorf("Couldn't update user: %w", ErrEmailTaken)
+			}
+
+			return fmt.Errorf("Couldn't update user: %w", err)
+		}
+
+		if rows == 0 {
+			return fmt.Errorf("Couldn't update user: %w", ErrUserNotFound)
 		}
 
 		res = record
+		if err := repo.record(ctx, tx, record.ID, before, record); err != nil {
+			return err
+		}
+
+		// Evicting before the commit would let a concurrent read cache the row as it was, until it expires.
+		tx.AfterCommit(func() { repo.evict(ctx, record.ID) })
+
+		return repo.publish(ctx, tx, record.ID, &events.UserModified{User: Proto(record)})
+	})
+
+	return
+}
+
+// DeleteUser deletes a user, with their memberships and API keys
+func (repo *UserRepository) DeleteUser(ctx context.Context, id int64) (res *models.User, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		before, err := models.Users(models.UserWhere.ID.EQ(id), qm.For("UPDATE")).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't delete user: %w", ErrUserNotFound)
+		}
+
+		if err != nil {
+			warning := fmt.Sprintf("Couldn't delete user: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't delete user: %w", err)
+		}
+
+		if _, err := before.Delete(ctx, tx); err != nil {
+			warning := fmt.Sprintf("Couldn't delete user: %s", err)
+			repo.log.Ctx(ctx).Error(warning)
+			return fmt.Errorf("Couldn't delete user: %w", err)
+		}
+
+		res = before
+		if err := repo.record(ctx, tx, id, before, nil); err != nil {
+			return err
+		}
+
+		tx.AfterCommit(func() { repo.evict(ctx, id) })
+
+		return repo.publish(ctx, tx, id, &events.UserDeleted{UserId: id})
+	})
+
+	return
+}
+
+// record records the audit event of the user with the id changing from before to after in tx, the transaction that
+// changed it.
+func (repo *UserRepository) record(ctx context.Context, tx *postgres.Tx, id int64, before *models.User, after *models.User) error {
+	e, err := audit.NewEvent(ctx, audit.EntityUser, id, before, after)
+	if err == nil {
+		err = repo.audit.Record(ctx, tx, e)
+	}
+
+	if err != nil {
+		warning := fmt.Sprintf("Couldn't audit user: %s", err)
+		repo.log.Ctx(ctx).Error(warning)
+		return fmt.Errorf("Couldn't audit user: %w", err)
+	}
+
+	return nil
+}
+
+// publish writes the domain event of a user changing to the outbox in tx, the transaction that changed it.
+func (repo *UserRepository) publish(ctx context.Context, tx *postgres.Tx, id int64, msg proto.Message) error {
+	e, err := outbox.NewEvent(ctx, outbox.AggregateUser, id, msg)
+	if err == nil {
+		err = repo.outbox.Write(ctx, tx, e)
+	}
+
+	if err != nil {
+		warning := fmt.Sprintf("Couldn't publish user event: %s", err)
+		repo.log.Ctx(ctx).Error(warning)
+		return fmt.Errorf("Couldn't publish user event: %w", err)
+	}
+
+	return nil
+}
+
+// Proto returns the user as carried by domain events.
+func Proto(record *models.User) *users.User {
+	return &users.User{
+		Id:        record.ID,
+		Email:     record.Email,
+		FirstName: record.FirstName,
+		LastName:  record.LastName,
+		CreatedAt: timestamppb.New(record.CreatedAt),
+		UpdatedAt: timestamppb.New(record.UpdatedAt),
+	}
+}
+
+// settled selects the changes whose transactions are older than every one still running, which no change can be
+// inserted before anymore.
+const settled = `xid < pg_snapshot_xmin(pg_current_snapshot())`
+
+// newest selects the ID of the last settled change.
+const newest = `
+SELECT id
+FROM user_changes
+WHERE ` + settled + `
+ORDER BY xid DESC, id DESC
+LIMIT 1`
+
+// Changes finds up to limit changes after the cursor, oldest first, with the users they were made to. They are read
+// from the writer, which notifies of them, as the reader may not have caught up yet.
+func (repo *UserRepository) Changes(ctx context.Context, after int64, limit int) (res []*watch.Change, err error) {
+	// The transaction IDs are only compared, never read.
+	mods := []qm.QueryMod{
+		qm.Select(
+			models.UserChangeColumns.ID, models.UserChangeColumns.UserID, models.UserChangeColumns.Operation,
+			models.UserChangeColumns.OrganizationIds, models.UserChangeColumns.ChangedAt,
+		),
+		qm.Where(settled),
+		qm.OrderBy("xid, id"),
+		qm.Limit(limit),
+	}
+
+	if after != 0 {
+		mods = append(mods, qm.Where("(xid, id) > ((SELECT xid FROM user_changes WHERE id = ?), ?)", after, after))
+	}
+
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		if after != 0 {
+			retained, err := models.UserChanges(models.UserChangeWhere.ID.EQ(after)).Exists(ctx, tx)
+			if err != nil {
+				return repo.failed(ctx, "retrieve user changes", err)
+			}
+
+			if !retained {
+				return watch.ErrCursorExpired
+			}
+		}
+
+		found, err := models.UserChanges(mods...).All(ctx, tx)
+		if err != nil || len(found) == 0 {
+			return repo.failed(ctx, "retrieve user changes", err)
+		}
+
+		ids := make([]int64, len(found))
+		for i, m := range found {
+			ids[i] = m.UserID
+			res = append(res, &watch.Change{
+				ID:              m.ID,
+				UserID:          m.UserID,
+				Operation:       m.Operation,
+				OrganizationIDs: m.OrganizationIds,
+				ChangedAt:       m.ChangedAt,
+			})
+		}
+
+		changed, err := models.Users(qm.Where("id = ANY ($1)", ids)).All(ctx, tx)
+		if err != nil && err != sql.ErrNoRows {
+			return repo.failed(ctx, "retrieve user changes", err)
+		}
+
+		byID := make(map[int64]*models.User, len(changed))
+		for _, u := range changed {
+			byID[u.ID] = u
+		}
+
+		for _, c := range res {
+			if c.Operation != watch.OperationDeleted {
+				c.User = byID[c.UserID]
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Newest finds the ID of the last change readable
+func (repo *UserRepository) Newest(ctx context.Context) (id int64, err error) {
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		m, err := models.UserChanges(
+			qm.Select(models.UserChangeColumns.ID),
+			qm.Where(settled),
+			qm.OrderBy("xid DESC, id DESC"),
+		).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "retrieve the newest user change", err)
+		}
+
+		id = m.ID
 		return nil
 	})
 
 	return
 }
+
+// Listen listens for changes on a connection of its own to the writer, which only notifies its own sessions.
+func (repo *UserRepository) Listen(ctx context.Context, wake func()) error {
+	conn, err := repo.dbs.WriterPool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("Couldn't listen for user changes: %w", err)
+	}
+
+	defer func() {
+		// Only a connection that no longer listens may be reused.
+		if _, err := conn.Exec(context.Background(), "UNLISTEN *"); err != nil {
+			conn.Conn().Close(context.Background())
+		}
+
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN user_changes"); err != nil {
+		return fmt.Errorf("Couldn't listen for user changes: %w", err)
+	}
+
+	wake()
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("Couldn't listen for user changes: %w", err)
+		}
+
+		wake()
+	}
+}
+
+// Prune deletes the changes made before a time but the newest
+func (repo *UserRepository) Prune(ctx context.Context, before time.Time) (n int, err error) {
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		rows, err := models.UserChanges(
+			models.UserChangeWhere.ChangedAt.LT(before),
+			qm.Where("id IS DISTINCT FROM ("+newest+")"),
+		).DeleteAll(ctx, tx)
+		n = int(rows)
+
+		return repo.failed(ctx, "prune user changes", err)
+	})
+
+	return
+}
+
+// failed logs and wraps an unexpected error.
+func (repo *UserRepository) failed(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	warning := fmt.Sprintf("Couldn't %s: %s", action, err)
+	repo.log.Ctx(ctx).Error(warning)
+
+	return fmt.Errorf("Couldn't %s: %w", action, err)
+}
+
+// cached returns the cached user for the id, or nil on a miss.
+func (repo *UserRepository) cached(ctx context.Context, id int64) *models.User {
+	b, ok, err := repo.cache.Get(ctx, cacheKey(id))
+	if err != nil {
+		repo.log.Ctx(ctx).Warn("Couldn't read cached user", zap.Error(err))
+		return nil
+	}
+
+	if !ok {
+		return nil
+	}
+
+	var record models.User
+	if err := json.Unmarshal(b, &record); err != nil {
+		repo.log.Ctx(ctx).Warn("Couldn't decode cached user", zap.Error(err))
+		return nil
+	}
+
+	return &record
+}
+
+// evict removes the cached user, logging rather than failing the call on error.
+func (repo *UserRepository) evict(ctx context.Context, id int64) {
+	if err := repo.cache.Delete(ctx, cacheKey(id)); err != nil {
+		repo.log.Ctx(ctx).Warn("Couldn't evict cached user", zap.Error(err))
+	}
+}
+
+// store caches the user, logging rather than failing the call on error.
+func (repo *UserRepository) store(ctx context.Context, record *models.User) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		repo.log.Ctx(ctx).Warn("Couldn't encode user for caching", zap.Error(err))
+		return
+	}
+
+	if err := repo.cache.Set(ctx, cacheKey(record.ID), b, userCacheTTL); err != nil {
+		repo.log.Ctx(ctx).Warn("Couldn't cache user", zap.Error(err))
+	}
+}
+
+// isUniqueViolation reports whether err was caused by a unique index rejecting a write.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func cacheKey(id int64) string {
+	return fmt.Sprintf("users:%d", id)
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/cache"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/harness"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository/conformance"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+// TestConformance runs the suite against a fresh database per subtest, caching users so that stale entries fail it.
+func TestConformance(t *testing.T) {
+	pg := harness.Require(t)
+
+	conformance.Run(t, func(t *testing.T) repository.IUserRepository {
+		cfg, err := config.Defaults()
+		if err != nil {
+			t.Fatalf("loading config: %v", err)
+		}
+
+		cfg.WriterPG = pg.Database(t)
+		cfg.ReaderPG = cfg.WriterPG
+
+		dbs, err := postgres.Open(&cfg)
+		if err != nil {
+			t.Fatalf("opening database: %v", err)
+		}
+
+		t.Cleanup(dbs.Close)
+
+		clk := clock.New()
+
+		return repository.NewUserRepository(&cfg, dbs, otelzap.New(zap.NewNop()), cache.NewMemory(clk), clk, audit.Nop(), outbox.Nop())
+	})
+}
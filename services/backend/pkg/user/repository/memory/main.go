@@ -0,0 +1,371 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	events "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
+)
+
+// UserRepository is an in-memory repository.IUserRepository. It mirrors the Postgres implementation's semantics,
+// so it can stand in for it when running without a database. Writes are not transactional.
+type UserRepository struct {
+	mu        sync.RWMutex
+	clock     clock.Clock
+	audit     audit.Recorder
+	outbox    outbox.Writer
+	members   watch.Memberships
+	seq       int64
+	users     map[int64]models.User
+	changes   []watch.Change
+	changeSeq int64
+	listeners map[int]func()
+	listenSeq int
+}
+
+var (
+	_ repository.IUserRepository = (*UserRepository)(nil)
+	_ watch.Source               = (*UserRepository)(nil)
+)
+
+// NewUserRepository Creates a new, empty in-memory User repo instance. Changes are recorded with the organizations
+// members finds for the user.
+func NewUserRepository(clk clock.Clock, rec audit.Recorder, out outbox.Writer, members watch.Memberships) *UserRepository {
+	return &UserRepository{
+		clock:     clk,
+		audit:     rec,
+		outbox:    out,
+		members:   members,
+		users:     make(map[int64]models.User),
+		listeners: make(map[int]func()),
+	}
+}
+
+// CreateUser register's a new user
+func (repo *UserRepository) CreateUser(ctx context.Context, record *models.User) (*models.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.emailTaken(record.Email, 0) {
+		return nil, fmt.Errorf("Couldn't register user: %w", repository.ErrEmailTaken)
+	}
+
+	if record.ID == 0 {
+		repo.seq++
+		record.ID = repo.seq
+	} else if _, ok := repo.users[record.ID]; ok {
+		return nil, fmt.Errorf("Couldn't register user: duplicate id %d", record.ID)
+	}
+
+	now := repo.clock.Now()
+	record.CreatedAt = now
+	record.UpdatedAt = now
+
+	if err := repo.record(ctx, record.ID, nil, record); err != nil {
+		return nil, err
+	}
+
+	if err := repo.publish(ctx, record.ID, &events.UserRegistered{User: repository.Proto(record)}); err != nil {
+		return nil, err
+	}
+
+	if err := repo.change(ctx, record.ID, watch.OperationCreated); err != nil {
+		return nil, err
+	}
+
+	repo.users[record.ID] = *record
+
+	return record, nil
+}
+
+// FindUserById finds a user by id, returning nil if there is none
+func (repo *UserRepository) FindUserById(ctx context.Context, id int64) (*models.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	u, ok := repo.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &u, nil
+}
+
+// FindUsersByIds finds users by ids, ordered by ascending id
+func (repo *UserRepository) FindUsersByIds(ctx context.Context, ids []int64) ([]*models.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	res := make([]*models.User, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+
+	for _, id := range ids {
+		u, ok := repo.users[id]
+		if !ok || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		res = append(res, &u)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+// FindUsersPage finds a page of users ordered by ascending id
+func (repo *UserRepository) FindUsersPage(ctx context.Context, page repository.Page) ([]*models.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	window := make([]*models.User, 0, len(repo.users))
+
+	for id := range repo.users {
+		if page.After > 0 && id <= page.After {
+			continue
+		}
+
+		if page.Before > 0 && id >= page.Before {
+			continue
+		}
+
+		u := repo.users[id]
+		window = append(window, &u)
+	}
+
+	sort.Slice(window, func(i, j int) bool { return window[i].ID < window[j].ID })
+
+	limit := page.Limit
+	if limit < 0 {
+		limit = 0
+	}
+
+	if limit > len(window) {
+		limit = len(window)
+	}
+
+	if page.Backward {
+		return window[len(window)-limit:], nil
+	}
+
+	return window[:limit], nil
+}
+
+// CountUsers counts every user
+func (repo *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return int64(len(repo.users)), nil
+}
+
+// UpdateUser modifies a user
+func (repo *UserRepository) UpdateUser(ctx context.Context, record *models.User) (*models.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	before, ok := repo.users[record.ID]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't update user: %w", repository.ErrUserNotFound)
+	}
+
+	if repo.emailTaken(record.Email, record.ID) {
+		return nil, fmt.Errorf("Couldn't update user: %w", repository.ErrEmailTaken)
+	}
+
+	record.CreatedAt = before.CreatedAt
+	record.UpdatedAt = repo.clock.Now()
+
+	if err := repo.record(ctx, record.ID, &before, record); err != nil {
+		return nil, err
+	}
+
+	if err := repo.publish(ctx, record.ID, &events.UserModified{User: repository.Proto(record)}); err != nil {
+		return nil, err
+	}
+
+	if err := repo.change(ctx, record.ID, watch.OperationUpdated); err != nil {
+		return nil, err
+	}
+
+	repo.users[record.ID] = *record
+
+	return record, nil
+}
+
+// DeleteUser deletes a user
+func (repo *UserRepository) DeleteUser(ctx context.Context, id int64) (*models.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	before, ok := repo.users[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't delete user: %w", repository.ErrUserNotFound)
+	}
+
+	if err := repo.record(ctx, id, &before, nil); err != nil {
+		return nil, err
+	}
+
+	if err := repo.publish(ctx, id, &events.UserDeleted{UserId: id}); err != nil {
+		return nil, err
+	}
+
+	if err := repo.change(ctx, id, watch.OperationDeleted); err != nil {
+		return nil, err
+	}
+
+	delete(repo.users, id)
+
+	return &before, nil
+}
+
+// record records the audit event of the user with the id changing from before to after.
+func (repo *UserRepository) record(ctx context.Context, id int64, before *models.User, after *models.User) error {
+	e, err := audit.NewEvent(ctx, audit.EntityUser, id, before, after)
+	if err == nil {
+		err = repo.audit.Record(ctx, nil, e)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Couldn't audit user: %w", err)
+	}
+
+	return nil
+}
+
+// publish writes the domain event of a user changing to the outbox.
+func (repo *UserRepository) publish(ctx context.Context, id int64, msg proto.Message) error {
+	e, err := outbox.NewEvent(ctx, outbox.AggregateUser, id, msg)
+	if err == nil {
+		err = repo.outbox.Write(ctx, nil, e)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Couldn't publish user event: %w", err)
+	}
+
+	return nil
+}
+
+// Changes finds up to limit changes after the cursor, oldest first, with the users they were made to
+func (repo *UserRepository) Changes(ctx context.Context, after int64, limit int) ([]*watch.Change, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	i := sort.Search(len(repo.changes), func(i int) bool { return repo.changes[i].ID > after })
+	if after != 0 && (i == 0 || repo.changes[i-1].ID != after) {
+		return nil, watch.ErrCursorExpired
+	}
+
+	var res []*watch.Change
+	for ; i < len(repo.changes) && len(res) < limit; i++ {
+		c := repo.changes[i]
+		c.OrganizationIDs = append([]int64(nil), c.OrganizationIDs...)
+
+		if u, ok := repo.users[c.UserID]; ok && c.Operation != watch.OperationDeleted {
+			c.User = &u
+		}
+
+		res = append(res, &c)
+	}
+
+	return res, nil
+}
+
+// Newest finds the ID of the newest change
+func (repo *UserRepository) Newest(ctx context.Context) (int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if len(repo.changes) == 0 {
+		return 0, nil
+	}
+
+	return repo.changes[len(repo.changes)-1].ID, nil
+}
+
+// Listen calls wake on every change until ctx is done
+func (repo *UserRepository) Listen(ctx context.Context, wake func()) error {
+	repo.mu.Lock()
+	repo.listenSeq++
+	id := repo.listenSeq
+	repo.listeners[id] = wake
+	repo.mu.Unlock()
+
+	defer func() {
+		repo.mu.Lock()
+		delete(repo.listeners, id)
+		repo.mu.Unlock()
+	}()
+
+	wake()
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// Prune deletes the changes made before a time but the newest
+func (repo *UserRepository) Prune(ctx context.Context, before time.Time) (int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	n := sort.Search(len(repo.changes), func(i int) bool { return !repo.changes[i].ChangedAt.Before(before) })
+	if n == len(repo.changes) && n > 0 {
+		n--
+	}
+	repo.changes = append([]watch.Change(nil), repo.changes[n:]...)
+
+	return n, nil
+}
+
+// change records a change to a user and wakes the listeners, which only read it once the lock is released.
+func (repo *UserRepository) change(ctx context.Context, id int64, operation string) error {
+	var orgIDs []int64
+
+	if repo.members != nil {
+		var err error
+		if orgIDs, err = repo.members.Organizations(ctx, id); err != nil {
+			return fmt.Errorf("Couldn't record user change: %w", err)
+		}
+	}
+
+	repo.changeSeq++
+	repo.changes = append(repo.changes, watch.Change{
+		ID:              repo.changeSeq,
+		UserID:          id,
+		Operation:       operation,
+		OrganizationIDs: orgIDs,
+		ChangedAt:       repo.clock.Now(),
+	})
+
+	for _, wake := range repo.listeners {
+		wake()
+	}
+
+	return nil
+}
+
+// emailTaken reports whether a user other than the one with the given id holds the email. Like the Postgres unique
+// index, the comparison is case-sensitive.
+func (repo *UserRepository) emailTaken(email string, id int64) bool {
+	for _, u := range repo.users {
+		if u.Email == email && u.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,18 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/audit"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository/conformance"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository/memory"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) repository.IUserRepository {
+		return memory.NewUserRepository(clock.New(), audit.Nop(), outbox.Nop(), nil)
+	})
+}
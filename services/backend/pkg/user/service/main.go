@@ -2,20 +2,25 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	connect "github.com/bufbuild/connect-go"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
 	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
 
 	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1/usersconnect"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
 	"github.com/jmandel1027/perspex/services/backend/pkg/user/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/user/watch"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 )
 
@@ -23,32 +28,61 @@ import (
 type IUserService interface {
 	DeleteUser(ctx context.Context, rec *connect.Request[users.DeleteUserRequest]) (*connect.Response[users.DeleteUserResponse], error)
 	ModifyUser(ctx context.Context, rec *connect.Request[users.ModifyUserRequest]) (*connect.Response[users.ModifyUserResponse], error)
-	RegisterUser(ctx context.Context, rec *connect.Request[users.RegisterUserRequest]) (*connect.Response[users.RegisterUserRequest], error)
+	RegisterUser(ctx context.Context, rec *connect.Request[users.RegisterUserRequest]) (*connect.Response[users.RegisterUserResponse], error)
 	RetrieveUser(ctx context.Context, rec *connect.Request[users.RetrieveUserRequest]) (*connect.Response[users.RetrieveUserResponse], error)
 	RetrieveUsers(ctx context.Context, rec *connect.Request[users.RetrieveUsersRequest]) (*connect.Response[users.RetrieveUsersResponse], error)
 	RetrieveUsersPage(ctx context.Context, rec *connect.Request[users.RetrieveUsersPageRequest]) (*connect.Response[users.RetrieveUsersPageResponse], error)
+	WatchUsers(ctx context.Context, rec *connect.Request[users.WatchUsersRequest], stream *connect.ServerStream[users.WatchUsersResponse]) error
 }
 
 // UserService structs
 type UserService struct {
-	mu   *sync.RWMutex
-	repo *repository.UserRepository
+	mu      *sync.RWMutex
+	repo    repository.IUserRepository
+	hub     *watch.Hub
+	members orgRepository.IMembershipRepository
+	log     *otelzap.Logger
 	usersconnect.UnimplementedUserServiceHandler
 }
 
-// NewUserService for connecting to the repository
-func NewUserService() *UserService {
-	repo := repository.NewUserRepository()
+// NewUserService for connecting to the repository. Watchers are woken by hub and authorized by members.
+func NewUserService(repo repository.IUserRepository, hub *watch.Hub, members orgRepository.IMembershipRepository, log *otelzap.Logger) *UserService {
 	service := &UserService{
-		mu:   &sync.RWMutex{},
-		repo: repo,
+		mu:      &sync.RWMutex{},
+		repo:    repo,
+		hub:     hub,
+		members: members,
+		log:     log,
 	}
 
 	return service
 }
 
+// Register returns the registry.Service for mounting the service behind the supplied interceptors, typically the
+// transaction interceptors of its backing database.
+func Register(svc usersconnect.UserServiceHandler, interceptors ...connect.Interceptor) registry.Service {
+	return &registry.Registration{
+		ServiceName: usersconnect.UserServiceName,
+		HandlerFunc: func(opts ...connect.HandlerOption) (string, http.Handler) {
+			return usersconnect.NewUserServiceHandler(svc, opts...)
+		},
+		ServiceInterceptors: interceptors,
+		GatewayFunc:         users.RegisterUserServiceHandler,
+	}
+}
+
+// DeleteUser deletes a user by ID
 func (svc *UserService) DeleteUser(ctx context.Context, rec *connect.Request[users.DeleteUserRequest]) (*connect.Response[users.DeleteUserResponse], error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	record, err := svc.repo.DeleteUser(ctx, rec.Msg.GetUser().GetId())
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error deleting user: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	return connect.NewResponse(&users.DeleteUserResponse{User: repository.Proto(record)}), nil
 }
 
 // ModidifyUser
@@ -64,8 +98,8 @@ func (svc *UserService) ModifyUser(ctx context.Context, rec *connect.Request[use
 
 	record, err := svc.repo.UpdateUser(ctx, u)
 	if err != nil {
-		otelzap.Ctx(ctx).Error("Error modifying user: ", zap.Error(err))
-		return nil, err
+		svc.log.Ctx(ctx).Error("Error modifying user: ", zap.Error(err))
+		return nil, toConnectError(err)
 	}
 
 	res := connect.NewResponse(&users.ModifyUserResponse{
@@ -97,8 +131,8 @@ func (svc *UserService) RegisterUser(ctx context.Context, rec *connect.Request[u
 
 	record, err := svc.repo.CreateUser(ctx, u)
 	if err != nil {
-		otelzap.Ctx(ctx).Error("Error retrieving user: ", zap.Error(err))
-		return nil, connect.NewError(connect.CodeInternal, err)
+		svc.log.Ctx(ctx).Error("Error retrieving user: ", zap.Error(err))
+		return nil, toConnectError(err)
 	}
 
 	res := connect.NewResponse(&users.RegisterUserResponse{
@@ -124,12 +158,12 @@ func (svc *UserService) RetrieveUser(ctx context.Context, rec *connect.Request[u
 
 	record, err := svc.repo.FindUserById(ctx, rec.Msg.Id)
 	if err != nil {
-		otelzap.Ctx(ctx).Error("Error retrieving user", zap.Error(err))
+		svc.log.Ctx(ctx).Error("Error retrieving user", zap.Error(err))
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	if record == nil {
-		otelzap.Ctx(ctx).Error("Error retrieving user", zap.Error(err))
+		svc.log.Ctx(ctx).Error("Error retrieving user", zap.Error(err))
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("user not found"))
 	}
 
@@ -155,7 +189,7 @@ func (svc *UserService) RetrieveUsers(ctx context.Context, rec *connect.Request[
 
 	records, err := svc.repo.FindUsersByIds(ctx, rec.Msg.Ids)
 	if err != nil {
-		otelzap.Ctx(ctx).Error("Error retrieving users: ", zap.Error(err))
+		svc.log.Ctx(ctx).Error("Error retrieving users: ", zap.Error(err))
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
@@ -185,6 +219,158 @@ func (svc *UserService) RetrieveUsers(ctx context.Context, rec *connect.Request[
 	return res, nil
 }
 
+// RetrieveUsersPage fetches the cursors for a page of users
 func (svc *UserService) RetrieveUsersPage(ctx context.Context, rec *connect.Request[users.RetrieveUsersPageRequest]) (*connect.Response[users.RetrieveUsersPageResponse], error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RetrieveUsersPage not implemented")
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	backward := rec.Msg.Direction == users.Direction_DIRECTION_BACKWARD
+
+	size := rec.Msg.First
+	if backward {
+		size = rec.Msg.Last
+	}
+
+	if size <= 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("page size must be positive"))
+	}
+
+	// Fetch one extra user to learn whether another page follows in the requested direction.
+	records, err := svc.repo.FindUsersPage(ctx, repository.Page{
+		After:    rec.Msg.After,
+		Before:   rec.Msg.Before,
+		Limit:    int(size) + 1,
+		Backward: backward,
+	})
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error retrieving users page: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	total, err := svc.repo.CountUsers(ctx)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error counting users: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	more := len(records) > int(size)
+	if more && backward {
+		records = records[1:]
+	} else if more {
+		records = records[:size]
+	}
+
+	page := &users.RetrieveUsersPageResponse{
+		TotalCount:      total,
+		HasNextPage:     (!backward && more) || (backward && rec.Msg.Before > 0),
+		HasPreviousPage: (backward && more) || (!backward && rec.Msg.After > 0),
+	}
+
+	if len(records) > 0 {
+		page.StartCursor = records[0].ID
+		page.EndCursor = records[len(records)-1].ID
+	}
+
+	return connect.NewResponse(page), nil
+}
+
+// WatchUsers streams the changes to users, until the caller disconnects
+func (svc *UserService) WatchUsers(ctx context.Context, rec *connect.Request[users.WatchUsersRequest], stream *connect.ServerStream[users.WatchUsersResponse]) error {
+	if rec.Msg.After < 0 {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("after must not be negative"))
+	}
+
+	if err := svc.watches(ctx, rec.Msg.OrganizationId); err != nil {
+		return err
+	}
+
+	send := func(c *watch.Change) error {
+		change := &users.UserChange{
+			Cursor:    c.ID,
+			Operation: toOperation(c.Operation),
+			UserId:    c.UserID,
+			ChangedAt: timestamppb.New(c.ChangedAt),
+		}
+
+		if c.User != nil {
+			change.User = repository.Proto(c.User)
+		}
+
+		return stream.Send(&users.WatchUsersResponse{Event: &users.WatchUsersResponse_Change{Change: change}})
+	}
+
+	heartbeat := func(cursor int64, at time.Time) error {
+		return stream.Send(&users.WatchUsersResponse{
+			Event: &users.WatchUsersResponse_Heartbeat{
+				Heartbeat: &users.Heartbeat{Cursor: cursor, SentAt: timestamppb.New(at)},
+			},
+		})
+	}
+
+	err := svc.hub.Watch(ctx, rec.Msg.After, watch.Filter{OrganizationID: rec.Msg.OrganizationId}, send, heartbeat)
+	switch {
+	case err == nil, ctx.Err() != nil:
+		return nil
+	case errors.Is(err, watch.ErrCursorExpired):
+		return connect.NewError(connect.CodeOutOfRange, err)
+	case connect.CodeOf(err) != connect.CodeUnknown:
+		// Sending failed, as the caller went away.
+		return err
+	default:
+		svc.log.Ctx(ctx).Error("Error watching users: ", zap.Error(err))
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+// watches checks that the caller may watch the users of an organization, or every user when orgID is zero.
+func (svc *UserService) watches(ctx context.Context, orgID int64) error {
+	id, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return auth.Errorf("watching users requires an authenticated caller")
+	}
+
+	if id.Admin {
+		return nil
+	}
+
+	if orgID == 0 || id.UserID == 0 {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("only admins may watch every user"))
+	}
+
+	role, err := svc.members.Role(ctx, orgID, id.UserID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error checking organization role: ", zap.Error(err))
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	if role != orgRepository.RoleAdmin {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("only organization admins may watch its users"))
+	}
+
+	return nil
+}
+
+func toOperation(operation string) users.Operation {
+	switch operation {
+	case watch.OperationCreated:
+		return users.Operation_OPERATION_CREATED
+	case watch.OperationUpdated:
+		return users.Operation_OPERATION_UPDATED
+	case watch.OperationDeleted:
+		return users.Operation_OPERATION_DELETED
+	default:
+		return users.Operation_OPERATION_UNSPECIFIED
+	}
+}
+
+// toConnectError maps repository errors onto connect error codes.
+func toConnectError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, repository.ErrEmailTaken):
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
 }
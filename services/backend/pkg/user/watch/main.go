@@ -0,0 +1,268 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+)
+
+// Operations of changes
+const (
+	OperationCreated = "created"
+	OperationUpdated = "updated"
+	OperationDeleted = "deleted"
+)
+
+// ErrCursorExpired is returned for a cursor whose change is no longer retained.
+var ErrCursorExpired = errors.New("cursor has expired, watch again without one")
+
+// Change is a row of the `user_changes` table, a change to a user. OrganizationIDs are those the user was a member of
+// as of the change, and User is the user as it now stands, nil once deleted.
+type Change struct {
+	ID              int64
+	UserID          int64
+	Operation       string
+	OrganizationIDs []int64
+	User            *models.User
+	ChangedAt       time.Time
+}
+
+// Source holds the changes to users, in an order no change is inserted into once read. Cursors are the IDs of changes,
+// which need not increase in that order.
+type Source interface {
+	// Changes finds up to limit changes after the change with the cursor's ID, or after none when zero, failing with
+	// ErrCursorExpired once that change has been pruned.
+	Changes(ctx context.Context, after int64, limit int) ([]*Change, error)
+	// Newest finds the ID of the last change readable, zero when there is none.
+	Newest(ctx context.Context) (int64, error)
+	// Listen calls wake once it is listening, then whenever changes are committed, until ctx is done or it stops
+	// listening, which it reports with an error.
+	Listen(ctx context.Context, wake func()) error
+	// Prune deletes the changes made before a time but the newest, which watchers may still resume from, returning how
+	// many it deleted.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// Memberships finds the organizations of users, which changes to them are filtered on.
+type Memberships interface {
+	Organizations(ctx context.Context, userID int64) ([]int64, error)
+}
+
+// Filter selects the changes sent to a watcher, every change when zero.
+type Filter struct {
+	OrganizationID int64
+}
+
+// Matches reports whether the filter selects a change.
+func (f Filter) Matches(c *Change) bool {
+	if f.OrganizationID == 0 {
+		return true
+	}
+
+	for _, id := range c.OrganizationIDs {
+		if id == f.OrganizationID {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	// batch bounds the changes read at once.
+	batch = 100
+
+	// Listening is retried after a delay doubling from minBackoff up to maxBackoff.
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+
+	// pruneEvery is how often changes older than the retention are deleted.
+	pruneEvery = time.Hour
+)
+
+// Hub shares a single listener on a Source between every watcher of the process.
+type Hub struct {
+	source      Source
+	log         *otelzap.Logger
+	clock       clock.Clock
+	cfg         config.WatchConfig
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewHub returns a Hub over source, configured by cfg.
+func NewHub(source Source, log *otelzap.Logger, clk clock.Clock, cfg config.WatchConfig) *Hub {
+	return &Hub{
+		source:      source,
+		log:         log,
+		clock:       clk,
+		cfg:         cfg,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Run listens for changes until ctx is done, waking the watchers on each, and deletes those older than the retention.
+// Watchers are woken whenever listening resumes too, as changes may have been missed in the meantime.
+func (h *Hub) Run(ctx context.Context) {
+	h.log.Ctx(ctx).Info("User watch hub started")
+
+	go h.prune(ctx)
+
+	backoff := minBackoff
+
+	for {
+		start := h.clock.Now()
+
+		err := h.source.Listen(ctx, h.wake)
+		if ctx.Err() != nil {
+			h.log.Ctx(ctx).Info("User watch hub stopped")
+			return
+		}
+
+		if h.clock.Now().Sub(start) > maxBackoff {
+			backoff = minBackoff
+		}
+
+		h.log.Ctx(ctx).Warn("Stopped listening for user changes", zap.Duration("retry_in", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// Watch sends the changes after a cursor selected by the filter, then those committed from then on, until ctx is done
+// or sending fails. When the cursor is zero, only the changes committed once watching are sent. A heartbeat carrying
+// the cursor of the last change read, sent or not, is sent whenever nothing has been for the configured interval.
+func (h *Hub) Watch(ctx context.Context, after int64, filter Filter, send func(*Change) error, heartbeat func(cursor int64, at time.Time) error) error {
+	// Subscribe first, so that no change committed from now on goes unnoticed.
+	wake, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	cursor := after
+	if after == 0 {
+		newest, err := h.source.Newest(ctx)
+		if err != nil {
+			return err
+		}
+
+		cursor = newest
+	}
+
+	timer := time.NewTimer(time.Duration(h.cfg.Heartbeat))
+	defer timer.Stop()
+
+	for {
+		for {
+			changes, err := h.source.Changes(ctx, cursor, batch)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range changes {
+				cursor = c.ID
+
+				if !filter.Matches(c) {
+					continue
+				}
+
+				if err := send(c); err != nil {
+					return err
+				}
+
+				reset(timer, time.Duration(h.cfg.Heartbeat))
+			}
+
+			if len(changes) < batch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+		case <-timer.C:
+			if err := heartbeat(cursor, h.clock.Now()); err != nil {
+				return err
+			}
+
+			timer.Reset(time.Duration(h.cfg.Heartbeat))
+		}
+	}
+}
+
+// subscribe registers a watcher, returning the channel it is woken on and a function unregistering it.
+func (h *Hub) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// wake wakes every watcher, without waiting for those already awake.
+func (h *Hub) wake() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// prune deletes the changes older than the retention every pruneEvery, until ctx is done.
+func (h *Hub) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneEvery)
+	defer ticker.Stop()
+
+	for {
+		n, err := h.source.Prune(ctx, h.clock.Now().Add(-time.Duration(h.cfg.Retention)))
+		if err != nil && ctx.Err() == nil {
+			h.log.Ctx(ctx).Error("Error pruning user changes: ", zap.Error(err))
+		} else if n > 0 {
+			h.log.Ctx(ctx).Info("Pruned user changes", zap.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reset restarts a timer that may have fired.
+func reset(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+
+	timer.Reset(d)
+}
@@ -0,0 +1,382 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	outboxSink "github.com/jmandel1027/perspex/services/backend/pkg/outbox/sink"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+
+	events "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+)
+
+// Headers of every delivery, alongside the event headers of outboxSink.Webhook.
+const (
+	SignatureHeader = "X-Perspex-Signature"
+	DeliveryHeader  = "X-Perspex-Delivery"
+)
+
+// ErrInvalidSignature is returned by Verify for a request that was not signed with the secret, or too long ago.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrForbiddenAddress is returned when dialing an endpoint that resolves to an address of the internal network, which
+// organizations could otherwise reach through the webhooks they register.
+var ErrForbiddenAddress = errors.New("webhook endpoint resolves to a loopback, private or link-local address")
+
+// Sign returns the signature header of a body sent at t, as `t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">`.
+// Signing the timestamp with the body lets receivers reject replayed requests.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+
+	return "t=" + ts + ",v1=" + digest(secret, ts, body)
+}
+
+// Verify checks the signature header of a body received at now, which must have been signed within tolerance of it.
+// Receivers written in Go can use it as is.
+func Verify(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts string
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing timestamp", ErrInvalidSignature)
+	}
+
+	if d := now.Sub(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("%w: timestamp outside of tolerance", ErrInvalidSignature)
+	}
+
+	expected := digest(secret, ts, body)
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func digest(secret string, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Retry delays double from minBackoff on every failed attempt, up to maxBackoff, so that the default 12 attempts span
+// about a day.
+const (
+	minBackoff = 30 * time.Second
+	maxBackoff = 6 * time.Hour
+)
+
+// Backoff returns how long to wait before attempting a delivery again once it has failed attempts times.
+func Backoff(attempts int) time.Duration {
+	d := minBackoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > maxBackoff {
+		return maxBackoff
+	}
+
+	return d
+}
+
+// Forbidden reports whether ip is a loopback, private, link-local, multicast or unspecified address, which endpoints
+// may not resolve to.
+func Forbidden(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// ForbiddenHost reports whether the host of an endpoint's URL is known to be forbidden without resolving it: localhost
+// or a Forbidden IP. Names that resolve to forbidden addresses are only rejected when dialed.
+func ForbiddenHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip, err := netip.ParseAddr(strings.Trim(host, "[]"))
+
+	return err == nil && Forbidden(ip)
+}
+
+// control rejects connections to Forbidden addresses. It runs once the name of the endpoint is resolved, so names
+// can't be pointed at the internal network after the endpoint was registered.
+func control(network string, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil || Forbidden(ap.Addr()) {
+		return fmt.Errorf("dialing %s: %w", address, ErrForbiddenAddress)
+	}
+
+	return nil
+}
+
+// NewClient returns the client deliveries are posted with, as cfg configures it. It neither follows redirects nor
+// goes through proxies, and dials Forbidden addresses only if cfg allows private endpoints.
+func NewClient(cfg config.WebhooksConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !cfg.AllowPrivate {
+		dialer.Control = control
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// Memberships finds the organizations of users, which receive the events about them.
+type Memberships interface {
+	Organizations(ctx context.Context, userID int64) ([]int64, error)
+}
+
+// Dispatcher is an outbox.Sink queueing deliveries of events to the endpoints of the organizations they concern. As
+// the relay may publish an event more than once, it is only queued once per endpoint.
+type Dispatcher struct {
+	repo    repository.IWebhookRepository
+	members Memberships
+}
+
+var _ outbox.Sink = (*Dispatcher)(nil)
+
+// NewDispatcher returns a Dispatcher queueing deliveries in repo.
+func NewDispatcher(repo repository.IWebhookRepository, members Memberships) *Dispatcher {
+	return &Dispatcher{repo: repo, members: members}
+}
+
+// Publish implements outbox.Sink.
+func (d *Dispatcher) Publish(ctx context.Context, e *outbox.Event) error {
+	orgIDs, err := d.organizations(ctx, e)
+	if err != nil || len(orgIDs) == 0 {
+		return err
+	}
+
+	_, err = d.repo.Enqueue(ctx, orgIDs, e)
+
+	return err
+}
+
+// organizations returns the organizations an event concerns. Those of a user are the ones they are a member of when
+// the event is relayed, so a deleted user, having none, concerns no organization.
+func (d *Dispatcher) organizations(ctx context.Context, e *outbox.Event) ([]int64, error) {
+	msg, err := e.Message()
+	if err != nil {
+		return nil, err
+	}
+
+	switch m := msg.(type) {
+	case *events.OrganizationMemberAdded:
+		return []int64{m.GetOrganizationId()}, nil
+	case *events.OrganizationMemberRoleChanged:
+		return []int64{m.GetOrganizationId()}, nil
+	case *events.UserRegistered:
+		return d.members.Organizations(ctx, m.GetUser().GetId())
+	case *events.UserModified:
+		return d.members.Organizations(ctx, m.GetUser().GetId())
+	case *events.UserDeleted:
+		return d.members.Organizations(ctx, m.GetUserId())
+	default:
+		return nil, nil
+	}
+}
+
+// Worker posts due deliveries to their endpoints, recording each attempt.
+type Worker struct {
+	repo   repository.IWebhookRepository
+	client *http.Client
+	log    *otelzap.Logger
+	clock  clock.Clock
+	cfg    config.WebhooksConfig
+	retry  repository.Retry
+}
+
+// NewWorker returns a Worker attempting deliveries as cfg configures it. A nil client is replaced by NewClient, which
+// keeps endpoints from reaching the internal network.
+func NewWorker(repo repository.IWebhookRepository, client *http.Client, log *otelzap.Logger, clk clock.Clock, cfg config.WebhooksConfig) *Worker {
+	if client == nil {
+		client = NewClient(cfg)
+	}
+
+	return &Worker{
+		repo:   repo,
+		client: client,
+		log:    log,
+		clock:  clk,
+		cfg:    cfg,
+		retry:  repository.Retry{MaxAttempts: cfg.MaxAttempts, DisableAfter: cfg.DisableAfter, Backoff: Backoff},
+	}
+}
+
+// Run attempts deliveries until ctx is done. A full batch is followed by the next straight away, otherwise the worker
+// waits for the interval.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Ctx(ctx).Info("Webhook worker started",
+		zap.Duration("interval", time.Duration(w.cfg.Interval)),
+		zap.Int("batch", w.cfg.BatchSize),
+	)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Ctx(ctx).Info("Webhook worker stopped")
+			return
+		case <-timer.C:
+		}
+
+		n, err := w.deliver(ctx)
+		if err != nil {
+			w.log.Ctx(ctx).Error("Error delivering webhooks: ", zap.Error(err))
+		}
+
+		if err == nil && n == w.cfg.BatchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(time.Duration(w.cfg.Interval))
+		}
+	}
+}
+
+// Flush attempts batches until none are due, returning how many deliveries were attempted.
+func (w *Worker) Flush(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		n, err := w.deliver(ctx)
+		total += n
+
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+}
+
+// deliver attempts a batch of deliveries at once. They are leased for twice the timeout, so that no other worker
+// claims them while their attempts are in flight.
+func (w *Worker) deliver(ctx context.Context) (int, error) {
+	pending, err := w.repo.Claim(ctx, w.cfg.BatchSize, 2*time.Duration(w.cfg.Timeout))
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range pending {
+		wg.Add(1)
+
+		go func(p repository.Pending) {
+			defer wg.Done()
+			w.record(ctx, p, w.attempt(ctx, p))
+		}(p)
+	}
+
+	wg.Wait()
+
+	return len(pending), nil
+}
+
+// attempt posts a delivery to its endpoint.
+func (w *Worker) attempt(ctx context.Context, p repository.Pending) repository.Attempt {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(w.cfg.Timeout))
+	defer cancel()
+
+	start := w.clock.Now()
+	a := repository.Attempt{AttemptedAt: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint.URL, bytes.NewReader(p.Delivery.Payload))
+	if err != nil {
+		a.Error = err.Error()
+		return a
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(p.Endpoint.Secret, start, p.Delivery.Payload))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(p.Delivery.ID, 10))
+	req.Header.Set(outboxSink.EventIDHeader, strconv.FormatInt(p.Delivery.EventID, 10))
+	req.Header.Set(outboxSink.EventTypeHeader, p.Delivery.EventType)
+
+	res, err := w.client.Do(req)
+	a.Duration = w.clock.Now().Sub(start)
+
+	if err != nil {
+		a.Error = err.Error()
+		return a
+	}
+
+	defer res.Body.Close()
+
+	// The body is drained, up to a limit, so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	a.StatusCode = res.StatusCode
+
+	return a
+}
+
+// record stores the outcome of an attempt, logging failures.
+func (w *Worker) record(ctx context.Context, p repository.Pending, a repository.Attempt) {
+	fields := []zap.Field{
+		zap.Int64("delivery_id", p.Delivery.ID),
+		zap.Int64("endpoint_id", p.Endpoint.ID),
+		zap.String("type", p.Delivery.EventType),
+		zap.Int("status_code", a.StatusCode),
+		zap.Duration("duration", a.Duration),
+	}
+
+	if !a.OK() {
+		w.log.Ctx(ctx).Warn("Couldn't deliver webhook", append(fields, zap.String("error", a.Error))...)
+	}
+
+	disabled, err := w.repo.RecordAttempt(ctx, p.Delivery.ID, a, w.retry)
+	if err != nil {
+		w.log.Ctx(ctx).Error("Couldn't record webhook attempt", append(fields, zap.Error(err))...)
+		return
+	}
+
+	if disabled {
+		w.log.Ctx(ctx).Warn("Disabled failing webhook endpoint", append(fields, zap.Int("failures", w.cfg.DisableAfter))...)
+	}
+}
@@ -0,0 +1,487 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	events "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+	users "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/users/v1"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	outboxSink "github.com/jmandel1027/perspex/services/backend/pkg/outbox/sink"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository/memory"
+)
+
+const secret = "whsec_test"
+
+// ticker is a clock that only moves when told to.
+type ticker struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ticker) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ticker) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// members puts every user in organization 1.
+type members struct{}
+
+func (members) Organizations(ctx context.Context, userID int64) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+// received is a request to the receiver, read in full.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is an endpoint answering with the statuses it is given in turn, then 200s.
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []received
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.requests = append(r.requests, received{header: req.Header, body: body})
+
+		if len(r.statuses) > 0 {
+			w.WriteHeader(r.statuses[0])
+			r.statuses = r.statuses[1:]
+		}
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) received() []received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]received{}, r.requests...)
+}
+
+// fixture is an endpoint of organization 1 pointing at a receiver, with a worker delivering to it.
+type fixture struct {
+	clock    *ticker
+	repo     *memory.WebhookRepository
+	endpoint *repository.Endpoint
+	worker   *webhook.Worker
+	dispatch *webhook.Dispatcher
+}
+
+func setup(t *testing.T, url string, configure func(cfg *config.WebhooksConfig)) *fixture {
+	t.Helper()
+
+	clk := &ticker{now: time.Unix(1672531200, 0)}
+	repo := memory.NewWebhookRepository(clk)
+
+	endpoint, err := repo.CreateEndpoint(context.Background(), &repository.Endpoint{OrganizationID: 1, URL: url, Secret: secret})
+	if err != nil {
+		t.Fatalf("CreateEndpoint: %v", err)
+	}
+
+	// Receivers listen on loopback, which endpoints may only point to if private addresses are allowed.
+	cfg := config.WebhooksConfig{BatchSize: 10, Timeout: config.Duration(time.Second), MaxAttempts: 12, DisableAfter: 50, AllowPrivate: true}
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	return &fixture{
+		clock:    clk,
+		repo:     repo,
+		endpoint: endpoint,
+		worker:   webhook.NewWorker(repo, nil, otelzap.New(zap.NewNop()), clk, cfg),
+		dispatch: webhook.NewDispatcher(repo, members{}),
+	}
+}
+
+// publish dispatches an event about user 42 to the organization's endpoints.
+func (f *fixture) publish(t *testing.T, id int64) {
+	t.Helper()
+
+	e, err := outbox.NewEvent(context.Background(), outbox.AggregateUser, 42, &events.UserRegistered{User: &users.User{Id: 42}})
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+
+	e.ID = id
+
+	if err := f.dispatch.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func (f *fixture) flush(t *testing.T) int {
+	t.Helper()
+
+	n, err := f.worker.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	return n
+}
+
+func (f *fixture) deliveries(t *testing.T) []*repository.Delivery {
+	t.Helper()
+
+	res, err := f.repo.FindDeliveries(context.Background(), f.endpoint.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("FindDeliveries: %v", err)
+	}
+
+	return res
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1672531200, 0)
+	body := []byte(`{"id":1}`)
+	header := webhook.Sign(secret, now, body)
+
+	if err := webhook.Verify(secret, header, body, now.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	cases := map[string]struct {
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+	}{
+		"WrongSecret":      {secret: "other", header: header, body: body, now: now},
+		"TamperedBody":     {secret: secret, header: header, body: []byte(`{"id":2}`), now: now},
+		"TooOld":           {secret: secret, header: header, body: body, now: now.Add(6 * time.Minute)},
+		"TooNew":           {secret: secret, header: header, body: body, now: now.Add(-6 * time.Minute)},
+		"MissingTimestamp": {secret: secret, header: "v1=" + header[len(header)-64:], body: body, now: now},
+		"MissingSignature": {secret: secret, header: "t=" + strconv.FormatInt(now.Unix(), 10), body: body, now: now},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			err := webhook.Verify(c.secret, c.header, c.body, c.now, 5*time.Minute)
+			if !errors.Is(err, webhook.ErrInvalidSignature) {
+				t.Fatalf("expected an invalid signature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		20: 6 * time.Hour,
+	}
+
+	for attempts, want := range cases {
+		if got := webhook.Backoff(attempts); got != want {
+			t.Fatalf("Backoff(%d): expected %s, got %s", attempts, want, got)
+		}
+	}
+}
+
+func TestWorkerDelivers(t *testing.T) {
+	r := newReceiver(t)
+	f := setup(t, r.URL, nil)
+
+	// The relay may publish an event more than once, it is only delivered once.
+	f.publish(t, 7)
+	f.publish(t, 7)
+
+	if n := f.flush(t); n != 1 {
+		t.Fatalf("expected 1 delivery to be attempted, got %d", n)
+	}
+
+	requests := r.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected the receiver to get 1 request, got %d", len(requests))
+	}
+
+	req := requests[0]
+	if err := webhook.Verify(secret, req.header.Get(webhook.SignatureHeader), req.body, f.clock.Now(), time.Minute); err != nil {
+		t.Fatalf("expected the delivery to be signed with the endpoint's secret: %v", err)
+	}
+
+	if req.header.Get(outboxSink.EventIDHeader) != "7" || req.header.Get(outboxSink.EventTypeHeader) != "events.v1.UserRegistered" {
+		t.Fatalf("expected the event headers, got %v", req.header)
+	}
+
+	deliveries := f.deliveries(t)
+	if len(deliveries) != 1 || deliveries[0].Status != repository.StatusDelivered {
+		t.Fatalf("expected a delivered delivery, got %+v", deliveries)
+	}
+
+	if got := req.header.Get(webhook.DeliveryHeader); got != strconv.FormatInt(deliveries[0].ID, 10) {
+		t.Fatalf("expected the delivery header to be %d, got %s", deliveries[0].ID, got)
+	}
+
+	if attempts := deliveries[0].Attempts; len(attempts) != 1 || attempts[0].StatusCode != http.StatusOK {
+		t.Fatalf("expected a successful attempt, got %+v", attempts)
+	}
+}
+
+func TestWorkerRetries(t *testing.T) {
+	r := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	f := setup(t, r.URL, nil)
+
+	f.publish(t, 1)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if n := f.flush(t); n != 1 {
+			t.Fatalf("attempt %d: expected the delivery to be attempted, got %d", attempt, n)
+		}
+
+		d := f.deliveries(t)[0]
+		if d.Status != repository.StatusPending || !d.NextAttemptAt.Equal(f.clock.Now().Add(webhook.Backoff(attempt))) {
+			t.Fatalf("attempt %d: expected the delivery to be retried after %s, got %+v", attempt, webhook.Backoff(attempt), d)
+		}
+
+		if n := f.flush(t); n != 0 {
+			t.Fatalf("attempt %d: expected the delivery to wait for its backoff, got %d attempted", attempt, n)
+		}
+
+		f.clock.Add(webhook.Backoff(attempt))
+	}
+
+	if n := f.flush(t); n != 1 {
+		t.Fatalf("expected the delivery to be attempted once its backoff passed, got %d", n)
+	}
+
+	if d := f.deliveries(t)[0]; d.Status != repository.StatusDelivered || len(d.Attempts) != 3 {
+		t.Fatalf("expected the delivery to succeed on its third attempt, got %+v", d)
+	}
+}
+
+func TestWorkerGivesUp(t *testing.T) {
+	r := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	f := setup(t, r.URL, func(cfg *config.WebhooksConfig) { cfg.MaxAttempts = 2 })
+
+	f.publish(t, 1)
+
+	f.flush(t)
+	f.clock.Add(webhook.Backoff(1))
+	f.flush(t)
+	f.clock.Add(webhook.Backoff(2))
+
+	if n := f.flush(t); n != 0 {
+		t.Fatalf("expected no attempt after MaxAttempts, got %d", n)
+	}
+
+	if d := f.deliveries(t)[0]; d.Status != repository.StatusFailed || len(d.Attempts) != 2 {
+		t.Fatalf("expected the delivery to fail after 2 attempts, got %+v", d)
+	}
+}
+
+func TestWorkerDisablesFailingEndpoints(t *testing.T) {
+	r := newReceiver(t, http.StatusGone, http.StatusGone, http.StatusGone)
+	f := setup(t, r.URL, func(cfg *config.WebhooksConfig) {
+		cfg.BatchSize = 2
+		cfg.DisableAfter = 2
+	})
+
+	f.publish(t, 1)
+	f.publish(t, 2)
+	f.publish(t, 3)
+
+	// The first batch fails, disabling the endpoint before the third delivery is claimed.
+	if n := f.flush(t); n != 2 {
+		t.Fatalf("expected 2 deliveries to be attempted, got %d", n)
+	}
+
+	endpoint, err := f.repo.FindEndpointByID(context.Background(), f.endpoint.ID)
+	if err != nil {
+		t.Fatalf("FindEndpointByID: %v", err)
+	}
+
+	if endpoint.DisabledAt == nil || endpoint.Failures != 2 {
+		t.Fatalf("expected the endpoint to be disabled after 2 failures, got %+v", endpoint)
+	}
+
+	f.clock.Add(time.Hour)
+
+	if n := f.flush(t); n != 0 {
+		t.Fatalf("expected no delivery to a disabled endpoint, got %d", n)
+	}
+
+	if _, err := f.repo.EnableEndpoint(context.Background(), f.endpoint.ID); err != nil {
+		t.Fatalf("EnableEndpoint: %v", err)
+	}
+
+	if n := f.flush(t); n != 3 {
+		t.Fatalf("expected the pending deliveries to resume once enabled, got %d", n)
+	}
+}
+
+func TestWorkerTimesOut(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	f := setup(t, srv.URL, func(cfg *config.WebhooksConfig) { cfg.Timeout = config.Duration(50 * time.Millisecond) })
+
+	f.publish(t, 1)
+	f.flush(t)
+
+	d := f.deliveries(t)[0]
+	if len(d.Attempts) != 1 || d.Attempts[0].Error == "" || d.Attempts[0].StatusCode != 0 {
+		t.Fatalf("expected an attempt without a response, got %+v", d.Attempts)
+	}
+
+	if d.Status != repository.StatusPending {
+		t.Fatalf("expected a timed out delivery to be retried, got %s", d.Status)
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	r := newReceiver(t)
+
+	redirect := httptest.NewServer(http.RedirectHandler(r.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+
+	f := setup(t, redirect.URL, nil)
+
+	f.publish(t, 1)
+	f.flush(t)
+
+	if d := f.deliveries(t)[0]; d.Status != repository.StatusPending || d.Attempts[0].StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirect to fail the attempt, got %+v", d)
+	}
+
+	if len(r.received()) != 0 {
+		t.Fatal("expected the redirect not to be followed")
+	}
+}
+
+func TestWorkerRejectsPrivateAddresses(t *testing.T) {
+	r := newReceiver(t)
+	f := setup(t, r.URL, func(cfg *config.WebhooksConfig) { cfg.AllowPrivate = false })
+
+	f.publish(t, 1)
+	f.flush(t)
+
+	d := f.deliveries(t)[0]
+	if len(d.Attempts) != 1 || !strings.Contains(d.Attempts[0].Error, webhook.ErrForbiddenAddress.Error()) {
+		t.Fatalf("expected the attempt to be refused before dialing, got %+v", d.Attempts)
+	}
+
+	if len(r.received()) != 0 {
+		t.Fatal("expected nothing to reach the loopback receiver")
+	}
+}
+
+func TestForbiddenHost(t *testing.T) {
+	cases := map[string]bool{
+		"localhost":              true,
+		"LOCALHOST.":             true,
+		"api.localhost":          true,
+		"127.0.0.1":              true,
+		"127.1.2.3":              true,
+		"::1":                    true,
+		"[::1]":                  true,
+		"0.0.0.0":                true,
+		"::":                     true,
+		"10.0.0.1":               true,
+		"172.16.5.4":             true,
+		"192.168.1.1":            true,
+		"169.254.169.254":        true,
+		"fe80::1":                true,
+		"fd00::1":                true,
+		"::ffff:127.0.0.1":       true,
+		"::ffff:169.254.169.254": true,
+		"224.0.0.1":              true,
+		"8.8.8.8":                false,
+		"172.32.0.1":             false,
+		"2606:4700::1111":        false,
+		"hooks.example.com":      false,
+		"localhost.example.com":  false,
+	}
+
+	for host, want := range cases {
+		if got := webhook.ForbiddenHost(host); got != want {
+			t.Fatalf("%s: expected forbidden to be %v, got %v", host, want, got)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := newReceiver(t)
+	f := setup(t, r.URL, nil)
+
+	f.publish(t, 1)
+	f.flush(t)
+
+	original := f.deliveries(t)[0]
+
+	replay, err := f.repo.Replay(context.Background(), original.ID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	if replay.ReplayOf != original.ID || replay.Status != repository.StatusPending {
+		t.Fatalf("expected a pending copy of delivery %d, got %+v", original.ID, replay)
+	}
+
+	if n := f.flush(t); n != 1 {
+		t.Fatalf("expected the replay to be attempted, got %d", n)
+	}
+
+	requests := r.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected the receiver to get the event twice, got %d", len(requests))
+	}
+
+	if requests[1].header.Get(webhook.DeliveryHeader) != strconv.FormatInt(replay.ID, 10) || string(requests[1].body) != string(requests[0].body) {
+		t.Fatalf("expected the replay to resend the payload as delivery %d, got %v", replay.ID, requests[1].header)
+	}
+
+	// A replayed event is still only enqueued once by the relay.
+	f.publish(t, 1)
+
+	if n := f.flush(t); n != 0 {
+		t.Fatalf("expected a republished event not to be delivered again, got %d", n)
+	}
+}
@@ -0,0 +1,574 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+)
+
+// Statuses of deliveries
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Error strings shared by every IWebhookRepository implementation.
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// Endpoint is a row of the `webhook_endpoints` table, a URL an organization receives its events on.
+type Endpoint struct {
+	ID             int64      `json:"id"`
+	OrganizationID int64      `json:"organization_id"`
+	URL            string     `json:"url"`
+	Description    string     `json:"description"`
+	EventTypes     []string   `json:"event_types"`
+	Secret         string     `json:"-"`
+	Failures       int        `json:"failures"`
+	DisabledAt     *time.Time `json:"disabled_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Accepts reports whether the endpoint receives events of the type, every type being received when none are listed.
+func (e *Endpoint) Accepts(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is a row of the `webhook_deliveries` table, an event sent to an endpoint. Payload is the request body.
+type Delivery struct {
+	ID            int64
+	EndpointID    int64
+	EventID       int64
+	EventType     string
+	Payload       json.RawMessage
+	ReplayOf      int64
+	Status        string
+	Attempts      []Attempt
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+}
+
+// Attempt is a row of the `webhook_attempts` table, recording a try at delivering. StatusCode is zero when no
+// response was received.
+type Attempt struct {
+	ID          int64
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+// OK reports whether the endpoint acknowledged the delivery.
+func (a *Attempt) OK() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode <= 299
+}
+
+// Pending is a delivery claimed for an attempt, with the endpoint it goes to.
+type Pending struct {
+	Delivery *Delivery
+	Endpoint *Endpoint
+}
+
+// Retry decides what becomes of deliveries and their endpoints after a failed attempt. A delivery is given up on once
+// it has failed MaxAttempts times, and an endpoint disabled once DisableAfter attempts in a row have failed.
+type Retry struct {
+	MaxAttempts  int
+	DisableAfter int
+	Backoff      func(attempts int) time.Duration
+}
+
+// IWebhookRepository is the interface for webhook endpoints and their deliveries.
+type IWebhookRepository interface {
+	CreateEndpoint(ctx context.Context, record *Endpoint) (*Endpoint, error)
+	FindEndpointByID(ctx context.Context, id int64) (*Endpoint, error)
+	FindEndpointsByOrganization(ctx context.Context, orgID int64) ([]*Endpoint, error)
+	EnableEndpoint(ctx context.Context, id int64) (*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id int64) error
+
+	// Enqueue creates a delivery of the event to each enabled endpoint of the organizations that accepts it, unless
+	// one exists already, returning how many it created.
+	Enqueue(ctx context.Context, orgIDs []int64, event *outbox.Event) (int, error)
+	FindDeliveryByID(ctx context.Context, id int64) (*Delivery, error)
+	FindDeliveries(ctx context.Context, endpointID int64, after int64, limit int) ([]*Delivery, error)
+	Replay(ctx context.Context, id int64) (*Delivery, error)
+
+	// Claim leases up to limit due deliveries to enabled endpoints, postponing their next attempt by lease so that
+	// other workers pass over them. A delivery whose attempt is never recorded is attempted again once it expires.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Pending, error)
+	// RecordAttempt records an attempt at a claimed delivery, and its outcome for the delivery and endpoint. It
+	// reports whether the endpoint was disabled.
+	RecordAttempt(ctx context.Context, deliveryID int64, attempt Attempt, retry Retry) (bool, error)
+}
+
+// WebhookRepository --
+type WebhookRepository struct {
+	dbs   *postgres.DB
+	log   *otelzap.Logger
+	clock clock.Clock
+}
+
+var _ IWebhookRepository = (*WebhookRepository)(nil)
+
+// NewWebhookRepository Creates a new webhook repo instance
+func NewWebhookRepository(dbs *postgres.DB, log *otelzap.Logger, clk clock.Clock) *WebhookRepository {
+	return &WebhookRepository{dbs: dbs, log: log, clock: clk}
+}
+
+// enqueue creates the deliveries of an event, skipping the endpoints it was delivered to already. The conflict target
+// is a partial index, which the upserts of the models cannot name.
+const enqueue = `
+INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, next_attempt_at, created_at)
+SELECT id, $2, $3, $4, $5, $5
+FROM webhook_endpoints
+WHERE organization_id = ANY ($1) AND disabled_at IS NULL AND (event_types = '{}' OR $3 = ANY (event_types))
+ON CONFLICT (endpoint_id, event_id) WHERE replay_of IS NULL DO NOTHING`
+
+// CreateEndpoint stores a new endpoint
+func (repo *WebhookRepository) CreateEndpoint(ctx context.Context, record *Endpoint) (res *Endpoint, err error) {
+	now := repo.clock.Now()
+
+	m := &models.WebhookEndpoint{
+		OrganizationID: record.OrganizationID,
+		URL:            record.URL,
+		Description:    record.Description,
+		EventTypes:     eventTypes(record.EventTypes),
+		Secret:         record.Secret,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		if err := m.Insert(boil.SkipTimestamps(ctx), tx, boil.Infer()); err != nil {
+			return repo.failed(ctx, "create webhook endpoint", err)
+		}
+
+		res = endpoint(m)
+		return nil
+	})
+
+	return
+}
+
+// FindEndpointByID finds an endpoint by id
+func (repo *WebhookRepository) FindEndpointByID(ctx context.Context, id int64) (res *Endpoint, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		m, err := models.FindWebhookEndpoint(ctx, tx, id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't find webhook endpoint: %w", ErrEndpointNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "find webhook endpoint", err)
+		}
+
+		res = endpoint(m)
+		return nil
+	})
+
+	return
+}
+
+// FindEndpointsByOrganization finds the endpoints of an organization, oldest first
+func (repo *WebhookRepository) FindEndpointsByOrganization(ctx context.Context, orgID int64) (res []*Endpoint, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		endpoints, err := models.WebhookEndpoints(
+			models.WebhookEndpointWhere.OrganizationID.EQ(orgID),
+			qm.OrderBy(models.WebhookEndpointColumns.ID),
+		).All(ctx, tx)
+		if err != nil {
+			return repo.failed(ctx, "list webhook endpoints", err)
+		}
+
+		for _, m := range endpoints {
+			res = append(res, endpoint(m))
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// EnableEndpoint re-enables an endpoint, resuming its pending deliveries
+func (repo *WebhookRepository) EnableEndpoint(ctx context.Context, id int64) (res *Endpoint, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		m, err := models.WebhookEndpoints(models.WebhookEndpointWhere.ID.EQ(id), qm.For("UPDATE")).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't enable webhook endpoint: %w", ErrEndpointNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "enable webhook endpoint", err)
+		}
+
+		m.DisabledAt = null.Time{}
+		m.Failures = 0
+		m.UpdatedAt = repo.clock.Now()
+
+		columns := boil.Whitelist(
+			models.WebhookEndpointColumns.DisabledAt, models.WebhookEndpointColumns.Failures, models.WebhookEndpointColumns.UpdatedAt,
+		)
+
+		if _, err := m.Update(boil.SkipTimestamps(ctx), tx, columns); err != nil {
+			return repo.failed(ctx, "enable webhook endpoint", err)
+		}
+
+		res = endpoint(m)
+		return nil
+	})
+
+	return
+}
+
+// DeleteEndpoint deletes an endpoint with its deliveries
+func (repo *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	return repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		n, err := models.WebhookEndpoints(models.WebhookEndpointWhere.ID.EQ(id)).DeleteAll(ctx, tx)
+		if err != nil {
+			return repo.failed(ctx, "delete webhook endpoint", err)
+		}
+
+		if n == 0 {
+			return fmt.Errorf("Couldn't delete webhook endpoint: %w", ErrEndpointNotFound)
+		}
+
+		return nil
+	})
+}
+
+// Enqueue creates the deliveries of an event
+func (repo *WebhookRepository) Enqueue(ctx context.Context, orgIDs []int64, e *outbox.Event) (n int, err error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return 0, repo.failed(ctx, "enqueue webhook deliveries", err)
+	}
+
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		res, err := tx.ExecContext(ctx, enqueue, orgIDs, e.ID, e.Type, payload, repo.clock.Now())
+		if err != nil {
+			return repo.failed(ctx, "enqueue webhook deliveries", err)
+		}
+
+		rows, err := res.RowsAffected()
+		n = int(rows)
+
+		return repo.failed(ctx, "enqueue webhook deliveries", err)
+	})
+
+	return
+}
+
+// FindDeliveryByID finds a delivery by id, with its attempts
+func (repo *WebhookRepository) FindDeliveryByID(ctx context.Context, id int64) (res *Delivery, err error) {
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		m, err := models.WebhookDeliveries(models.WebhookDeliveryWhere.ID.EQ(id), withAttempts).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't find webhook delivery: %w", ErrDeliveryNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "find webhook delivery", err)
+		}
+
+		res = delivery(m)
+		return nil
+	})
+
+	return
+}
+
+// FindDeliveries finds a page of the deliveries to an endpoint, newest first, with their attempts
+func (repo *WebhookRepository) FindDeliveries(ctx context.Context, endpointID int64, after int64, limit int) (res []*Delivery, err error) {
+	mods := []qm.QueryMod{
+		models.WebhookDeliveryWhere.EndpointID.EQ(endpointID),
+		withAttempts,
+		qm.OrderBy(models.WebhookDeliveryColumns.ID + " DESC"),
+		qm.Limit(limit),
+	}
+
+	if after != 0 {
+		mods = append(mods, models.WebhookDeliveryWhere.ID.LT(after))
+	}
+
+	err = repo.dbs.InTx(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *postgres.Tx) error {
+		deliveries, err := models.WebhookDeliveries(mods...).All(ctx, tx)
+		if err != nil {
+			return repo.failed(ctx, "list webhook deliveries", err)
+		}
+
+		for _, m := range deliveries {
+			res = append(res, delivery(m))
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Replay copies a delivery as a new pending one
+func (repo *WebhookRepository) Replay(ctx context.Context, id int64) (res *Delivery, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		original, err := models.FindWebhookDelivery(ctx, tx, id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't replay webhook delivery: %w", ErrDeliveryNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "replay webhook delivery", err)
+		}
+
+		now := repo.clock.Now()
+
+		m := &models.WebhookDelivery{
+			EndpointID:    original.EndpointID,
+			EventID:       original.EventID,
+			EventType:     original.EventType,
+			Payload:       original.Payload,
+			ReplayOf:      null.Int64From(original.ID),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+
+		if err := m.Insert(boil.SkipTimestamps(ctx), tx, boil.Infer()); err != nil {
+			return repo.failed(ctx, "replay webhook delivery", err)
+		}
+
+		res = delivery(m)
+		return nil
+	})
+
+	return
+}
+
+// Claim leases a batch of due deliveries, skipping those locked by other workers
+func (repo *WebhookRepository) Claim(ctx context.Context, limit int, lease time.Duration) (res []Pending, err error) {
+	now := repo.clock.Now()
+
+	err = repo.dbs.InTx(ctx, postgres.CommittedTxOpts, func(tx *postgres.Tx) error {
+		deliveries, err := models.WebhookDeliveries(
+			qm.InnerJoin("webhook_endpoints endpoint ON endpoint.id = webhook_deliveries.endpoint_id"),
+			models.WebhookDeliveryWhere.Status.EQ(StatusPending),
+			models.WebhookDeliveryWhere.NextAttemptAt.LTE(now),
+			qm.Where("endpoint.disabled_at IS NULL"),
+			qm.OrderBy(models.WebhookDeliveryTableColumns.NextAttemptAt+", "+models.WebhookDeliveryTableColumns.ID),
+			qm.Limit(limit),
+			qm.For("UPDATE OF webhook_deliveries SKIP LOCKED"),
+			qm.Load(models.WebhookDeliveryRels.Endpoint),
+		).All(ctx, tx)
+		if err != nil || len(deliveries) == 0 {
+			return repo.failed(ctx, "claim webhook deliveries", err)
+		}
+
+		until := now.Add(lease)
+
+		if _, err := deliveries.UpdateAll(ctx, tx, models.M{models.WebhookDeliveryColumns.NextAttemptAt: until}); err != nil {
+			return repo.failed(ctx, "claim webhook deliveries", err)
+		}
+
+		for _, m := range deliveries {
+			m.NextAttemptAt = until
+			res = append(res, Pending{Delivery: delivery(m), Endpoint: endpoint(m.R.Endpoint)})
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// RecordAttempt records an attempt at a delivery, and its outcome
+func (repo *WebhookRepository) RecordAttempt(ctx context.Context, deliveryID int64, a Attempt, retry Retry) (disabled bool, err error) {
+	err = repo.dbs.InTx(ctx, postgres.StdTxOpts, func(tx *postgres.Tx) error {
+		d, err := models.WebhookDeliveries(models.WebhookDeliveryWhere.ID.EQ(deliveryID), qm.For("UPDATE")).One(ctx, tx)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Couldn't record webhook attempt: %w", ErrDeliveryNotFound)
+		}
+
+		if err != nil {
+			return repo.failed(ctx, "record webhook attempt", err)
+		}
+
+		attempt := &models.WebhookAttempt{
+			DeliveryID:  deliveryID,
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMS:  a.Duration.Milliseconds(),
+			AttemptedAt: a.AttemptedAt,
+		}
+
+		if err := attempt.Insert(ctx, tx, boil.Infer()); err != nil {
+			return repo.failed(ctx, "record webhook attempt", err)
+		}
+
+		var delivered *time.Time
+
+		d.Attempts++
+		d.Status, d.NextAttemptAt, delivered = Outcome(a, d.Attempts, retry)
+		d.DeliveredAt = null.TimeFromPtr(delivered)
+
+		columns := boil.Whitelist(
+			models.WebhookDeliveryColumns.Status, models.WebhookDeliveryColumns.Attempts,
+			models.WebhookDeliveryColumns.NextAttemptAt, models.WebhookDeliveryColumns.DeliveredAt,
+		)
+
+		if _, err := d.Update(ctx, tx, columns); err != nil {
+			return repo.failed(ctx, "record webhook attempt", err)
+		}
+
+		disabled, err = repo.attempted(ctx, tx, d.EndpointID, a, retry)
+
+		return repo.failed(ctx, "record webhook attempt", err)
+	})
+
+	return
+}
+
+// attempted records the outcome of an attempt for the endpoint it was made to: a success forgets its failures, while a
+// failure is counted, disabling the endpoint once it reaches the threshold. It reports whether it did.
+func (repo *WebhookRepository) attempted(ctx context.Context, tx *postgres.Tx, endpointID int64, a Attempt, retry Retry) (bool, error) {
+	e, err := models.WebhookEndpoints(models.WebhookEndpointWhere.ID.EQ(endpointID), qm.For("UPDATE")).One(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	if a.OK() {
+		if e.Failures == 0 {
+			return false, nil
+		}
+
+		e.Failures = 0
+		_, err = e.Update(ctx, tx, boil.Whitelist(models.WebhookEndpointColumns.Failures))
+
+		return false, err
+	}
+
+	e.Failures++
+	e.UpdatedAt = a.AttemptedAt
+
+	if e.Failures >= retry.DisableAfter && !e.DisabledAt.Valid {
+		e.DisabledAt = null.TimeFrom(a.AttemptedAt)
+	}
+
+	columns := boil.Whitelist(
+		models.WebhookEndpointColumns.Failures, models.WebhookEndpointColumns.DisabledAt, models.WebhookEndpointColumns.UpdatedAt,
+	)
+
+	_, err = e.Update(boil.SkipTimestamps(ctx), tx, columns)
+
+	return e.Failures == retry.DisableAfter, err
+}
+
+// Outcome returns the status of a delivery after an attempt, the attempts-th, with when it is next attempted and when
+// it was delivered, if it was.
+func Outcome(a Attempt, attempts int, retry Retry) (status string, next time.Time, delivered *time.Time) {
+	switch {
+	case a.OK():
+		at := a.AttemptedAt
+		return StatusDelivered, a.AttemptedAt, &at
+	case attempts >= retry.MaxAttempts:
+		return StatusFailed, a.AttemptedAt, nil
+	default:
+		return StatusPending, a.AttemptedAt.Add(retry.Backoff(attempts)), nil
+	}
+}
+
+// withAttempts loads the attempts of deliveries, oldest first.
+var withAttempts = qm.Load(models.WebhookDeliveryRels.DeliveryWebhookAttempts, qm.OrderBy(models.WebhookAttemptColumns.ID))
+
+// failed logs and wraps an unexpected error.
+func (repo *WebhookRepository) failed(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	warning := fmt.Sprintf("Couldn't %s: %s", action, err)
+	repo.log.Ctx(ctx).Error(warning)
+
+	return fmt.Errorf("Couldn't %s: %w", action, err)
+}
+
+// endpoint converts the model of an endpoint.
+func endpoint(m *models.WebhookEndpoint) *Endpoint {
+	return &Endpoint{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		URL:            m.URL,
+		Description:    m.Description,
+		EventTypes:     m.EventTypes,
+		Secret:         m.Secret,
+		Failures:       m.Failures,
+		DisabledAt:     m.DisabledAt.Ptr(),
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// delivery converts the model of a delivery, with the attempts loaded with it.
+func delivery(m *models.WebhookDelivery) *Delivery {
+	d := &Delivery{
+		ID:            m.ID,
+		EndpointID:    m.EndpointID,
+		EventID:       m.EventID,
+		EventType:     m.EventType,
+		Payload:       json.RawMessage(m.Payload),
+		ReplayOf:      m.ReplayOf.Int64,
+		Status:        m.Status,
+		NextAttemptAt: m.NextAttemptAt,
+		DeliveredAt:   m.DeliveredAt.Ptr(),
+		CreatedAt:     m.CreatedAt,
+	}
+
+	if m.R == nil {
+		return d
+	}
+
+	for _, a := range m.R.DeliveryWebhookAttempts {
+		d.Attempts = append(d.Attempts, Attempt{
+			ID:          a.ID,
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			Duration:    time.Duration(a.DurationMS) * time.Millisecond,
+			AttemptedAt: a.AttemptedAt,
+		})
+	}
+
+	return d
+}
+
+// eventTypes returns the types as an array that is never NULL.
+func eventTypes(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+
+	return types
+}
@@ -0,0 +1,359 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.uber.org/zap"
+
+	"github.com/jmandel1027/perspex/schemas/perspex/pkg/models"
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/database/postgres"
+	"github.com/jmandel1027/perspex/services/backend/pkg/harness"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+// ticker is a clock that only moves when told to.
+type ticker struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ticker) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ticker) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// retry gives up on deliveries after 2 failed attempts and disables endpoints after 3 in a row, a minute apart.
+var retry = repository.Retry{
+	MaxAttempts:  2,
+	DisableAfter: 3,
+	Backoff:      func(int) time.Duration { return time.Minute },
+}
+
+// fixture is a repository over a fresh database, with an organization to register endpoints for.
+type fixture struct {
+	clock *ticker
+	repo  *repository.WebhookRepository
+	orgID int64
+}
+
+func setup(t *testing.T) *fixture {
+	t.Helper()
+
+	pg := harness.Require(t)
+
+	cfg, err := config.Defaults()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	cfg.WriterPG = pg.Database(t)
+	cfg.ReaderPG = cfg.WriterPG
+
+	dbs, err := postgres.Open(&cfg)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	t.Cleanup(dbs.Close)
+
+	org := &models.Organization{Name: "Perspex"}
+	if err := org.Insert(context.Background(), dbs.Writer, boil.Infer()); err != nil {
+		t.Fatalf("inserting organization: %v", err)
+	}
+
+	clk := &ticker{now: time.Unix(1672531200, 0).UTC()}
+
+	return &fixture{
+		clock: clk,
+		repo:  repository.NewWebhookRepository(dbs, otelzap.New(zap.NewNop()), clk),
+		orgID: org.ID,
+	}
+}
+
+func (f *fixture) endpoint(t *testing.T, eventTypes ...string) *repository.Endpoint {
+	t.Helper()
+
+	e, err := f.repo.CreateEndpoint(context.Background(), &repository.Endpoint{
+		OrganizationID: f.orgID,
+		URL:            "https://hooks.example.com/perspex",
+		EventTypes:     eventTypes,
+		Secret:         "whsec_test",
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint: %v", err)
+	}
+
+	return e
+}
+
+func (f *fixture) enqueue(t *testing.T, eventID int64, eventType string) int {
+	t.Helper()
+
+	n, err := f.repo.Enqueue(context.Background(), []int64{f.orgID}, &outbox.Event{
+		ID:            eventID,
+		AggregateType: outbox.AggregateUser,
+		AggregateID:   "42",
+		Type:          eventType,
+		Payload:       []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	return n
+}
+
+func (f *fixture) claim(t *testing.T, limit int) []repository.Pending {
+	t.Helper()
+
+	pending, err := f.repo.Claim(context.Background(), limit, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	return pending
+}
+
+// attempt records an attempt answered with status, now.
+func (f *fixture) attempt(t *testing.T, deliveryID int64, status int) bool {
+	t.Helper()
+
+	disabled, err := f.repo.RecordAttempt(context.Background(), deliveryID, repository.Attempt{
+		StatusCode:  status,
+		Duration:    25 * time.Millisecond,
+		AttemptedAt: f.clock.Now(),
+	}, retry)
+	if err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+
+	return disabled
+}
+
+func (f *fixture) delivery(t *testing.T, id int64) *repository.Delivery {
+	t.Helper()
+
+	d, err := f.repo.FindDeliveryByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FindDeliveryByID: %v", err)
+	}
+
+	return d
+}
+
+func TestEnqueue(t *testing.T) {
+	f := setup(t)
+
+	all := f.endpoint(t)
+	f.endpoint(t, "events.v1.UserDeleted")
+
+	if n := f.enqueue(t, 1, "events.v1.UserRegistered"); n != 1 {
+		t.Fatalf("expected the event to be queued for the endpoint accepting it only, got %d", n)
+	}
+
+	if n := f.enqueue(t, 1, "events.v1.UserRegistered"); n != 0 {
+		t.Fatalf("expected an event relayed again not to be queued twice, got %d", n)
+	}
+
+	if n := f.enqueue(t, 2, "events.v1.UserDeleted"); n != 2 {
+		t.Fatalf("expected the event to be queued for both endpoints, got %d", n)
+	}
+
+	deliveries, err := f.repo.FindDeliveries(context.Background(), all.ID, 0, 1)
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventID != 2 {
+		t.Fatalf("expected the newest delivery first, got %v (%v)", deliveries, err)
+	}
+
+	deliveries, err = f.repo.FindDeliveries(context.Background(), all.ID, deliveries[0].ID, 10)
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventID != 1 {
+		t.Fatalf("expected the next page to hold the older delivery, got %v (%v)", deliveries, err)
+	}
+}
+
+func TestClaimLeases(t *testing.T) {
+	f := setup(t)
+
+	e := f.endpoint(t)
+	f.enqueue(t, 1, "events.v1.UserRegistered")
+
+	pending := f.claim(t, 10)
+	if len(pending) != 1 || pending[0].Endpoint.ID != e.ID || pending[0].Endpoint.Secret != "whsec_test" {
+		t.Fatalf("expected the delivery with its endpoint, got %+v", pending)
+	}
+
+	if got := f.claim(t, 10); len(got) != 0 {
+		t.Fatalf("expected a leased delivery to be passed over, got %d", len(got))
+	}
+
+	f.clock.Add(time.Minute)
+
+	if got := f.claim(t, 10); len(got) != 1 || got[0].Delivery.ID != pending[0].Delivery.ID {
+		t.Fatalf("expected the delivery to be claimed again once its lease expired, got %+v", got)
+	}
+}
+
+func TestClaimConcurrently(t *testing.T) {
+	f := setup(t)
+
+	f.endpoint(t)
+
+	for id := int64(1); id <= 20; id++ {
+		f.enqueue(t, id, "events.v1.UserRegistered")
+	}
+
+	var wg sync.WaitGroup
+	claimed := make([][]repository.Pending, 4)
+
+	for i := range claimed {
+		i := i
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pending, err := f.repo.Claim(context.Background(), 5, time.Minute)
+			if err != nil {
+				t.Errorf("Claim: %v", err)
+			}
+
+			claimed[i] = pending
+		}()
+	}
+
+	wg.Wait()
+
+	seen := map[int64]bool{}
+	for _, pending := range claimed {
+		for _, p := range pending {
+			if seen[p.Delivery.ID] {
+				t.Fatalf("expected every delivery to be claimed by a single worker, got %d twice", p.Delivery.ID)
+			}
+
+			seen[p.Delivery.ID] = true
+		}
+	}
+
+	if len(seen) != 20 {
+		t.Fatalf("expected the 20 deliveries to be claimed, got %d", len(seen))
+	}
+}
+
+func TestRecordAttempt(t *testing.T) {
+	f := setup(t)
+
+	e := f.endpoint(t)
+	f.enqueue(t, 1, "events.v1.UserRegistered")
+
+	id := f.claim(t, 1)[0].Delivery.ID
+
+	if disabled := f.attempt(t, id, 500); disabled {
+		t.Fatal("expected a single failure not to disable the endpoint")
+	}
+
+	d := f.delivery(t, id)
+	if d.Status != repository.StatusPending || len(d.Attempts) != 1 || d.Attempts[0].StatusCode != 500 ||
+		d.Attempts[0].Duration != 25*time.Millisecond || !d.NextAttemptAt.Equal(f.clock.Now().Add(time.Minute)) {
+		t.Fatalf("expected a pending delivery retried after the backoff, got %+v", d)
+	}
+
+	if got := f.claim(t, 1); len(got) != 0 {
+		t.Fatalf("expected the delivery to wait for its backoff, got %+v", got)
+	}
+
+	f.clock.Add(time.Minute)
+	f.claim(t, 1)
+	f.attempt(t, id, 502)
+
+	if d := f.delivery(t, id); d.Status != repository.StatusFailed || len(d.Attempts) != 2 {
+		t.Fatalf("expected the delivery to be given up on after its last attempt, got %+v", d)
+	}
+
+	f.clock.Add(time.Hour)
+
+	if got := f.claim(t, 1); len(got) != 0 {
+		t.Fatalf("expected a failed delivery not to be claimed, got %+v", got)
+	}
+
+	replay, err := f.repo.Replay(context.Background(), id)
+	if err != nil || replay.ReplayOf != id || replay.Status != repository.StatusPending {
+		t.Fatalf("expected a pending replay of the delivery, got %+v (%v)", replay, err)
+	}
+
+	f.claim(t, 1)
+	f.attempt(t, replay.ID, 204)
+
+	if d := f.delivery(t, replay.ID); d.Status != repository.StatusDelivered || d.DeliveredAt == nil || !d.DeliveredAt.Equal(f.clock.Now()) {
+		t.Fatalf("expected the replay to be delivered, got %+v", d)
+	}
+
+	if endpoint, err := f.repo.FindEndpointByID(context.Background(), e.ID); err != nil || endpoint.Failures != 0 {
+		t.Fatalf("expected a success to forget the endpoint's failures, got %+v (%v)", endpoint, err)
+	}
+
+	_, err = f.repo.RecordAttempt(context.Background(), replay.ID+100, repository.Attempt{AttemptedAt: f.clock.Now()}, retry)
+	if !errors.Is(err, repository.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestRecordAttemptDisablesEndpoint(t *testing.T) {
+	f := setup(t)
+
+	e := f.endpoint(t)
+
+	for id := int64(1); id <= 3; id++ {
+		f.enqueue(t, id, "events.v1.UserRegistered")
+	}
+
+	var disabled bool
+	for _, p := range f.claim(t, 3) {
+		disabled = f.attempt(t, p.Delivery.ID, 0)
+	}
+
+	if !disabled {
+		t.Fatal("expected the endpoint to be disabled by its third failure in a row")
+	}
+
+	if endpoint, err := f.repo.FindEndpointByID(context.Background(), e.ID); err != nil || endpoint.DisabledAt == nil || endpoint.Failures != 3 {
+		t.Fatalf("expected the endpoint to be disabled, got %+v (%v)", endpoint, err)
+	}
+
+	f.clock.Add(time.Hour)
+
+	if n := f.enqueue(t, 4, "events.v1.UserRegistered"); n != 0 {
+		t.Fatalf("expected no delivery to be queued for a disabled endpoint, got %d", n)
+	}
+
+	if got := f.claim(t, 10); len(got) != 0 {
+		t.Fatalf("expected the deliveries of a disabled endpoint not to be claimed, got %d", len(got))
+	}
+
+	if _, err := f.repo.EnableEndpoint(context.Background(), e.ID); err != nil {
+		t.Fatalf("EnableEndpoint: %v", err)
+	}
+
+	if got := f.claim(t, 10); len(got) != 3 {
+		t.Fatalf("expected the pending deliveries to resume once enabled, got %d", len(got))
+	}
+}
@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/clock"
+	"github.com/jmandel1027/perspex/services/backend/pkg/outbox"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+)
+
+// WebhookRepository is an in-memory repository.IWebhookRepository, for running without a database.
+type WebhookRepository struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	endpoints  map[int64]*repository.Endpoint
+	deliveries map[int64]*repository.Delivery
+	nextID     int64
+}
+
+var _ repository.IWebhookRepository = (*WebhookRepository)(nil)
+
+// NewWebhookRepository Creates a new, empty in-memory webhook repo instance
+func NewWebhookRepository(clk clock.Clock) *WebhookRepository {
+	return &WebhookRepository{
+		clock:      clk,
+		endpoints:  make(map[int64]*repository.Endpoint),
+		deliveries: make(map[int64]*repository.Delivery),
+	}
+}
+
+// CreateEndpoint stores a new endpoint
+func (repo *WebhookRepository) CreateEndpoint(ctx context.Context, record *repository.Endpoint) (*repository.Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.nextID++
+	now := repo.clock.Now()
+
+	e := *record
+	e.ID = repo.nextID
+	e.EventTypes = append([]string{}, record.EventTypes...)
+	e.Failures = 0
+	e.DisabledAt = nil
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	repo.endpoints[e.ID] = &e
+
+	return copyEndpoint(&e), nil
+}
+
+// FindEndpointByID finds an endpoint by id
+func (repo *WebhookRepository) FindEndpointByID(ctx context.Context, id int64) (*repository.Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.endpoints[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't find webhook endpoint: %w", repository.ErrEndpointNotFound)
+	}
+
+	return copyEndpoint(e), nil
+}
+
+// FindEndpointsByOrganization finds the endpoints of an organization, oldest first
+func (repo *WebhookRepository) FindEndpointsByOrganization(ctx context.Context, orgID int64) ([]*repository.Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var res []*repository.Endpoint
+
+	for _, e := range repo.endpoints {
+		if e.OrganizationID == orgID {
+			res = append(res, copyEndpoint(e))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+// EnableEndpoint re-enables an endpoint, resuming its pending deliveries
+func (repo *WebhookRepository) EnableEndpoint(ctx context.Context, id int64) (*repository.Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.endpoints[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't enable webhook endpoint: %w", repository.ErrEndpointNotFound)
+	}
+
+	e.DisabledAt = nil
+	e.Failures = 0
+	e.UpdatedAt = repo.clock.Now()
+
+	return copyEndpoint(e), nil
+}
+
+// DeleteEndpoint deletes an endpoint with its deliveries
+func (repo *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.endpoints[id]; !ok {
+		return fmt.Errorf("Couldn't delete webhook endpoint: %w", repository.ErrEndpointNotFound)
+	}
+
+	delete(repo.endpoints, id)
+
+	for deliveryID, d := range repo.deliveries {
+		if d.EndpointID == id {
+			delete(repo.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// Enqueue creates the deliveries of an event
+func (repo *WebhookRepository) Enqueue(ctx context.Context, orgIDs []int64, e *outbox.Event) (int, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return 0, fmt.Errorf("Couldn't enqueue webhook deliveries: %w", err)
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	orgs := make(map[int64]bool, len(orgIDs))
+	for _, id := range orgIDs {
+		orgs[id] = true
+	}
+
+	delivered := make(map[int64]bool)
+	for _, d := range repo.deliveries {
+		if d.EventID == e.ID && d.ReplayOf == 0 {
+			delivered[d.EndpointID] = true
+		}
+	}
+
+	var endpoints []*repository.Endpoint
+	for _, endpoint := range repo.endpoints {
+		if orgs[endpoint.OrganizationID] && endpoint.DisabledAt == nil && endpoint.Accepts(e.Type) && !delivered[endpoint.ID] {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+
+	now := repo.clock.Now()
+
+	for _, endpoint := range endpoints {
+		repo.nextID++
+		repo.deliveries[repo.nextID] = &repository.Delivery{
+			ID:            repo.nextID,
+			EndpointID:    endpoint.ID,
+			EventID:       e.ID,
+			EventType:     e.Type,
+			Payload:       payload,
+			Status:        repository.StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+
+	return len(endpoints), nil
+}
+
+// FindDeliveryByID finds a delivery by id, with its attempts
+func (repo *WebhookRepository) FindDeliveryByID(ctx context.Context, id int64) (*repository.Delivery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	d, ok := repo.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't find webhook delivery: %w", repository.ErrDeliveryNotFound)
+	}
+
+	return copyDelivery(d), nil
+}
+
+// FindDeliveries finds a page of the deliveries to an endpoint, newest first, with their attempts
+func (repo *WebhookRepository) FindDeliveries(ctx context.Context, endpointID int64, after int64, limit int) ([]*repository.Delivery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var res []*repository.Delivery
+
+	for _, d := range repo.deliveries {
+		if d.EndpointID == endpointID && (after == 0 || d.ID < after) {
+			res = append(res, copyDelivery(d))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID > res[j].ID })
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// Replay copies a delivery as a new pending one
+func (repo *WebhookRepository) Replay(ctx context.Context, id int64) (*repository.Delivery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	original, ok := repo.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("Couldn't replay webhook delivery: %w", repository.ErrDeliveryNotFound)
+	}
+
+	now := repo.clock.Now()
+	repo.nextID++
+
+	d := &repository.Delivery{
+		ID:            repo.nextID,
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		ReplayOf:      original.ID,
+		Status:        repository.StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	repo.deliveries[d.ID] = d
+
+	return copyDelivery(d), nil
+}
+
+// Claim leases a batch of due deliveries
+func (repo *WebhookRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]repository.Pending, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := repo.clock.Now()
+
+	var due []*repository.Delivery
+	for _, d := range repo.deliveries {
+		e := repo.endpoints[d.EndpointID]
+		if d.Status == repository.StatusPending && !d.NextAttemptAt.After(now) && e.DisabledAt == nil {
+			due = append(due, d)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+
+		return due[i].ID < due[j].ID
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	res := make([]repository.Pending, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = now.Add(lease)
+
+		pending := copyDelivery(d)
+		pending.Attempts = nil
+
+		res = append(res, repository.Pending{Delivery: pending, Endpoint: copyEndpoint(repo.endpoints[d.EndpointID])})
+	}
+
+	return res, nil
+}
+
+// RecordAttempt records an attempt at a delivery, and its outcome
+func (repo *WebhookRepository) RecordAttempt(ctx context.Context, deliveryID int64, a repository.Attempt, retry repository.Retry) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	d, ok := repo.deliveries[deliveryID]
+	if !ok {
+		return false, fmt.Errorf("Couldn't record webhook attempt: %w", repository.ErrDeliveryNotFound)
+	}
+
+	repo.nextID++
+	a.ID = repo.nextID
+	d.Attempts = append(d.Attempts, a)
+	d.Status, d.NextAttemptAt, d.DeliveredAt = repository.Outcome(a, len(d.Attempts), retry)
+
+	e := repo.endpoints[d.EndpointID]
+	if a.OK() {
+		e.Failures = 0
+		return false, nil
+	}
+
+	e.Failures++
+	e.UpdatedAt = a.AttemptedAt
+
+	if e.Failures >= retry.DisableAfter && e.DisabledAt == nil {
+		at := a.AttemptedAt
+		e.DisabledAt = &at
+	}
+
+	return e.Failures == retry.DisableAfter, nil
+}
+
+func copyEndpoint(e *repository.Endpoint) *repository.Endpoint {
+	res := *e
+	res.EventTypes = append([]string(nil), e.EventTypes...)
+
+	return &res
+}
+
+func copyDelivery(d *repository.Delivery) *repository.Delivery {
+	res := *d
+	res.Attempts = append([]repository.Attempt(nil), d.Attempts...)
+
+	return &res
+}
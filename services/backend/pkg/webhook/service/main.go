@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	connect "github.com/bufbuild/connect-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// events registers the types endpoints may filter on.
+	_ "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/events/v1"
+	webhooks "github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/webhooks/v1"
+	"github.com/jmandel1027/perspex/schemas/proto/goproto/pkg/webhooks/v1/webhooksconnect"
+
+	"github.com/jmandel1027/perspex/services/backend/pkg/config"
+	"github.com/jmandel1027/perspex/services/backend/pkg/middleware/auth"
+	orgRepository "github.com/jmandel1027/perspex/services/backend/pkg/organization/repository"
+	"github.com/jmandel1027/perspex/services/backend/pkg/registry"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook"
+	"github.com/jmandel1027/perspex/services/backend/pkg/webhook/repository"
+)
+
+// Secret is the prefix of every endpoint secret, which reads `whsec_<hex>`.
+const Secret = "whsec_"
+
+const (
+	secretBytes = 32
+
+	// eventsPackage holds the event types endpoints may filter on.
+	eventsPackage = "events.v1"
+
+	maxPageSize = 100
+)
+
+// WebhookService structs
+type WebhookService struct {
+	repo    repository.IWebhookRepository
+	members orgRepository.IMembershipRepository
+	log     *otelzap.Logger
+	cfg     config.WebhooksConfig
+	webhooksconnect.UnimplementedWebhookServiceHandler
+}
+
+// NewWebhookService for connecting to the repository, accepting the endpoints cfg allows
+func NewWebhookService(repo repository.IWebhookRepository, members orgRepository.IMembershipRepository, log *otelzap.Logger, cfg config.WebhooksConfig) *WebhookService {
+	return &WebhookService{
+		repo:    repo,
+		members: members,
+		log:     log,
+		cfg:     cfg,
+	}
+}
+
+// Register returns the registry.Service for mounting the service behind the supplied interceptors, typically the
+// transaction interceptors of its backing database.
+func Register(svc webhooksconnect.WebhookServiceHandler, interceptors ...connect.Interceptor) registry.Service {
+	return &registry.Registration{
+		ServiceName: webhooksconnect.WebhookServiceName,
+		HandlerFunc: func(opts ...connect.HandlerOption) (string, http.Handler) {
+			return webhooksconnect.NewWebhookServiceHandler(svc, opts...)
+		},
+		ServiceInterceptors: interceptors,
+		GatewayFunc:         webhooks.RegisterWebhookServiceHandler,
+	}
+}
+
+// CreateEndpoint registers an endpoint of an organization, returning its secret
+func (svc *WebhookService) CreateEndpoint(ctx context.Context, rec *connect.Request[webhooks.CreateEndpointRequest]) (*connect.Response[webhooks.CreateEndpointResponse], error) {
+	if err := svc.administers(ctx, rec.Msg.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(rec.Msg.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("url must be an http or https URL"))
+	}
+
+	// Names resolving to such addresses are rejected when deliveries dial them, see webhook.NewClient.
+	if !svc.cfg.AllowPrivate && webhook.ForbiddenHost(u.Hostname()) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("url must not point to a loopback, private or link-local address"))
+	}
+
+	if err := validateEventTypes(rec.Msg.EventTypes); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	record, err := svc.repo.CreateEndpoint(ctx, &repository.Endpoint{
+		OrganizationID: rec.Msg.OrganizationId,
+		URL:            rec.Msg.Url,
+		Description:    rec.Msg.Description,
+		EventTypes:     rec.Msg.EventTypes,
+		Secret:         generate(),
+	})
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error creating webhook endpoint: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Created webhook endpoint",
+		zap.Int64("endpoint_id", record.ID),
+		zap.Int64("organization_id", record.OrganizationID),
+	)
+
+	return connect.NewResponse(&webhooks.CreateEndpointResponse{
+		Endpoint: toProto(record),
+		Secret:   record.Secret,
+	}), nil
+}
+
+// ListEndpoints lists the endpoints of an organization
+func (svc *WebhookService) ListEndpoints(ctx context.Context, rec *connect.Request[webhooks.ListEndpointsRequest]) (*connect.Response[webhooks.ListEndpointsResponse], error) {
+	if err := svc.administers(ctx, rec.Msg.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	records, err := svc.repo.FindEndpointsByOrganization(ctx, rec.Msg.OrganizationId)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error listing webhook endpoints: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	endpoints := make([]*webhooks.Endpoint, 0, len(records))
+	for _, record := range records {
+		endpoints = append(endpoints, toProto(record))
+	}
+
+	return connect.NewResponse(&webhooks.ListEndpointsResponse{Endpoints: endpoints}), nil
+}
+
+// EnableEndpoint resumes deliveries to an endpoint disabled for failing
+func (svc *WebhookService) EnableEndpoint(ctx context.Context, rec *connect.Request[webhooks.EnableEndpointRequest]) (*connect.Response[webhooks.EnableEndpointResponse], error) {
+	e, err := svc.managed(ctx, rec.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := svc.repo.EnableEndpoint(ctx, e.ID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error enabling webhook endpoint: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Enabled webhook endpoint", zap.Int64("endpoint_id", record.ID))
+
+	return connect.NewResponse(&webhooks.EnableEndpointResponse{Endpoint: toProto(record)}), nil
+}
+
+// DeleteEndpoint deletes an endpoint with its deliveries
+func (svc *WebhookService) DeleteEndpoint(ctx context.Context, rec *connect.Request[webhooks.DeleteEndpointRequest]) (*connect.Response[webhooks.DeleteEndpointResponse], error) {
+	e, err := svc.managed(ctx, rec.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.repo.DeleteEndpoint(ctx, e.ID); err != nil {
+		svc.log.Ctx(ctx).Error("Error deleting webhook endpoint: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Deleted webhook endpoint", zap.Int64("endpoint_id", e.ID))
+
+	return connect.NewResponse(&webhooks.DeleteEndpointResponse{}), nil
+}
+
+// ListDeliveries fetches a page of the deliveries to an endpoint, newest first
+func (svc *WebhookService) ListDeliveries(ctx context.Context, rec *connect.Request[webhooks.ListDeliveriesRequest]) (*connect.Response[webhooks.ListDeliveriesResponse], error) {
+	size := rec.Msg.First
+	if size <= 0 || size > maxPageSize {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("page size must be between 1 and %d", maxPageSize))
+	}
+
+	e, err := svc.managed(ctx, rec.Msg.EndpointId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra delivery to learn whether another page follows.
+	records, err := svc.repo.FindDeliveries(ctx, e.ID, rec.Msg.After, int(size)+1)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error listing webhook deliveries: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	more := len(records) > int(size)
+	if more {
+		records = records[:size]
+	}
+
+	page := &webhooks.ListDeliveriesResponse{
+		Deliveries:  make([]*webhooks.Delivery, 0, len(records)),
+		HasNextPage: more,
+	}
+
+	for _, record := range records {
+		page.Deliveries = append(page.Deliveries, deliveryToProto(record))
+	}
+
+	if len(records) > 0 {
+		page.EndCursor = records[len(records)-1].ID
+	}
+
+	return connect.NewResponse(page), nil
+}
+
+// ReplayDelivery delivers the event of a past delivery again
+func (svc *WebhookService) ReplayDelivery(ctx context.Context, rec *connect.Request[webhooks.ReplayDeliveryRequest]) (*connect.Response[webhooks.ReplayDeliveryResponse], error) {
+	d, err := svc.repo.FindDeliveryByID(ctx, rec.Msg.Id)
+	if err != nil {
+		if !errors.Is(err, repository.ErrDeliveryNotFound) {
+			svc.log.Ctx(ctx).Error("Error retrieving webhook delivery: ", zap.Error(err))
+		}
+
+		return nil, toConnectError(err)
+	}
+
+	if _, err := svc.managed(ctx, d.EndpointID); err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return nil, toConnectError(repository.ErrDeliveryNotFound)
+		}
+
+		return nil, err
+	}
+
+	record, err := svc.repo.Replay(ctx, d.ID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error replaying webhook delivery: ", zap.Error(err))
+		return nil, toConnectError(err)
+	}
+
+	svc.log.Ctx(ctx).Info("Replayed webhook delivery", zap.Int64("delivery_id", d.ID), zap.Int64("replay_id", record.ID))
+
+	return connect.NewResponse(&webhooks.ReplayDeliveryResponse{Delivery: deliveryToProto(record)}), nil
+}
+
+// administers checks that the caller may manage the endpoints of an organization.
+func (svc *WebhookService) administers(ctx context.Context, orgID int64) error {
+	id, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return auth.Errorf("webhooks require an authenticated caller")
+	}
+
+	if id.Admin {
+		return nil
+	}
+
+	role, err := svc.members.Role(ctx, orgID, id.UserID)
+	if err != nil {
+		svc.log.Ctx(ctx).Error("Error checking organization role: ", zap.Error(err))
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	if id.UserID == 0 || role != orgRepository.RoleAdmin {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("only organization admins may manage its webhooks"))
+	}
+
+	return nil
+}
+
+// managed finds an endpoint the caller may manage, hiding the endpoints of other organizations as not found.
+func (svc *WebhookService) managed(ctx context.Context, endpointID int64) (*repository.Endpoint, error) {
+	e, err := svc.repo.FindEndpointByID(ctx, endpointID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrEndpointNotFound) {
+			svc.log.Ctx(ctx).Error("Error retrieving webhook endpoint: ", zap.Error(err))
+		}
+
+		return nil, toConnectError(err)
+	}
+
+	if err := svc.administers(ctx, e.OrganizationID); err != nil {
+		if connect.CodeOf(err) == connect.CodePermissionDenied {
+			return nil, toConnectError(repository.ErrEndpointNotFound)
+		}
+
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// validateEventTypes checks that every type names a domain event.
+func validateEventTypes(types []string) error {
+	for _, t := range types {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(t))
+		if err != nil || mt.Descriptor().ParentFile().Package() != eventsPackage {
+			return fmt.Errorf("event type %q is not a known %s event", t, eventsPackage)
+		}
+	}
+
+	return nil
+}
+
+// generate returns a random secret.
+func generate() string {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return Secret + hex.EncodeToString(b)
+}
+
+func toProto(e *repository.Endpoint) *webhooks.Endpoint {
+	res := &webhooks.Endpoint{
+		Id:             e.ID,
+		OrganizationId: e.OrganizationID,
+		Url:            e.URL,
+		EventTypes:     e.EventTypes,
+		Description:    e.Description,
+		Failures:       int32(e.Failures),
+		CreatedAt:      timestamppb.New(e.CreatedAt),
+	}
+
+	if e.DisabledAt != nil {
+		res.DisabledAt = timestamppb.New(*e.DisabledAt)
+	}
+
+	return res
+}
+
+func deliveryToProto(d *repository.Delivery) *webhooks.Delivery {
+	res := &webhooks.Delivery{
+		Id:         d.ID,
+		EndpointId: d.EndpointID,
+		EventId:    d.EventID,
+		EventType:  d.EventType,
+		ReplayOf:   d.ReplayOf,
+		Status:     toStatus(d.Status),
+		Attempts:   make([]*webhooks.Attempt, 0, len(d.Attempts)),
+		CreatedAt:  timestamppb.New(d.CreatedAt),
+	}
+
+	for _, a := range d.Attempts {
+		res.Attempts = append(res.Attempts, &webhooks.Attempt{
+			Id:          a.ID,
+			StatusCode:  int32(a.StatusCode),
+			Error:       a.Error,
+			Duration:    durationpb.New(a.Duration),
+			AttemptedAt: timestamppb.New(a.AttemptedAt),
+		})
+	}
+
+	if d.Status == repository.StatusPending {
+		res.NextAttemptAt = timestamppb.New(d.NextAttemptAt)
+	}
+
+	if d.DeliveredAt != nil {
+		res.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+
+	return res
+}
+
+func toStatus(status string) webhooks.DeliveryStatus {
+	switch status {
+	case repository.StatusPending:
+		return webhooks.DeliveryStatus_DELIVERY_STATUS_PENDING
+	case repository.StatusDelivered:
+		return webhooks.DeliveryStatus_DELIVERY_STATUS_DELIVERED
+	case repository.StatusFailed:
+		return webhooks.DeliveryStatus_DELIVERY_STATUS_FAILED
+	default:
+		return webhooks.DeliveryStatus_DELIVERY_STATUS_UNSPECIFIED
+	}
+}
+
+// toConnectError maps repository errors onto connect error codes.
+func toConnectError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, repository.ErrEndpointNotFound), errors.Is(err, repository.ErrDeliveryNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
@@ -2,8 +2,12 @@
 
 set -e
 
-# change this when we implement a proper secrets integration flow w/ secrets-manager
-POSTGRES_PASSWORD="pass"
+# Prefer a mounted secret, eg: a k8s secret volume, over the environment
+if [[ -n "${POSTGRES_PASSWORD_FILE}" ]]; then
+  POSTGRES_PASSWORD="$(cat "${POSTGRES_PASSWORD_FILE}")"
+fi
+
+POSTGRES_PASSWORD="${POSTGRES_PASSWORD:-pass}"
 
 dsn="postgresql://${POSTGRES_USER}:${POSTGRES_PASSWORD}@${POSTGRES_HOST}:${POSTGRES_PORT}/${POSTGRES_DB}?sslmode=disable&x-multi-statement=true"
 
@@ -0,0 +1,3 @@
+module github.com/jmandel1027/perspex/services/migration
+
+go 1.19
@@ -0,0 +1,8 @@
+package migration
+
+import "embed"
+
+// Perspex holds the migrations for the perspex database, under `src/perspex`.
+//
+//go:embed src/perspex/*.sql
+var Perspex embed.FS
@@ -0,0 +1,5 @@
+-- migrate:down transaction:false
+
+DROP INDEX CONCURRENTLY IF EXISTS organization_members_user_id_index;
+
+DROP TABLE IF EXISTS "organization_members";
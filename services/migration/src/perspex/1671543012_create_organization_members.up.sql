@@ -0,0 +1,13 @@
+-- migrate:up transaction:false
+
+CREATE TABLE IF NOT EXISTS organization_members (
+  organization_id BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+  user_id         BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role            TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'admin')),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS organization_members_user_id_index
+	ON "organization_members" (user_id);
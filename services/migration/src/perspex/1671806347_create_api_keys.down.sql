@@ -0,0 +1,7 @@
+-- migrate:down transaction:false
+
+DROP INDEX CONCURRENTLY IF EXISTS api_keys_user_id_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS api_keys_prefix_uindex;
+
+DROP TABLE IF EXISTS "api_keys";
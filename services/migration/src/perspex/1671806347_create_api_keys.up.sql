@@ -0,0 +1,22 @@
+-- migrate:up transaction:false
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id              BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  prefix          TEXT NOT NULL,
+  secret_hash     BYTEA NOT NULL,
+  name            TEXT NOT NULL,
+  scopes          TEXT[] NOT NULL DEFAULT '{}',
+  user_id         BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  organization_id BIGINT REFERENCES organizations (id) ON DELETE CASCADE,
+  expires_at      TIMESTAMPTZ,
+  last_used_at    TIMESTAMPTZ,
+  revoked_at      TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS api_keys_prefix_uindex
+	ON "api_keys" (prefix);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS api_keys_user_id_index
+	ON "api_keys" (user_id);
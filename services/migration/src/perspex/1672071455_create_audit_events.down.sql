@@ -0,0 +1,13 @@
+-- migrate:down transaction:false
+
+DROP INDEX CONCURRENTLY IF EXISTS audit_events_created_at_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS audit_events_entity_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS audit_events_impersonator_user_id_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS audit_events_actor_user_id_index;
+
+DROP TABLE IF EXISTS "audit_events";
+
+DROP FUNCTION IF EXISTS audit_events_append_only();
@@ -0,0 +1,40 @@
+-- migrate:up transaction:false
+
+-- Events outlive the users and entities they refer to, so none of the IDs reference other tables.
+CREATE TABLE IF NOT EXISTS audit_events (
+  id                   BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  actor_user_id        BIGINT,
+  actor_subject        TEXT NOT NULL DEFAULT '',
+  impersonator_user_id BIGINT,
+  impersonator_subject TEXT NOT NULL DEFAULT '',
+  procedure            TEXT NOT NULL DEFAULT '',
+  entity_type          TEXT NOT NULL,
+  entity_id            TEXT NOT NULL,
+  diff                 JSONB NOT NULL DEFAULT '{}',
+  request_id           TEXT NOT NULL DEFAULT '',
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+
+CREATE TRIGGER audit_events_append_only
+  BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS audit_events_actor_user_id_index
+	ON "audit_events" (actor_user_id, id);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS audit_events_impersonator_user_id_index
+	ON "audit_events" (impersonator_user_id, id) WHERE impersonator_user_id IS NOT NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS audit_events_entity_index
+	ON "audit_events" (entity_type, entity_id, id);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS audit_events_created_at_index
+	ON "audit_events" (created_at);
@@ -0,0 +1,9 @@
+-- migrate:down transaction:false
+
+DROP INDEX CONCURRENTLY IF EXISTS outbox_published_at_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS outbox_available_at_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS outbox_pending_index;
+
+DROP TABLE IF EXISTS "outbox";
@@ -0,0 +1,27 @@
+-- migrate:up transaction:false
+
+-- Domain events awaiting relay. Events are published in ID order within each aggregate, so a relay only claims the
+-- oldest pending event of an aggregate, see pkg/outbox/repository. Published events are deleted once older than the
+-- retention of the relay.
+CREATE TABLE IF NOT EXISTS outbox (
+  id             BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id   TEXT NOT NULL,
+  event_type     TEXT NOT NULL,
+  payload        JSONB NOT NULL,
+  request_id     TEXT NOT NULL DEFAULT '',
+  attempts       INTEGER NOT NULL DEFAULT 0,
+  last_error     TEXT NOT NULL DEFAULT '',
+  available_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  published_at   TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS outbox_pending_index
+	ON "outbox" (aggregate_type, aggregate_id, id) WHERE published_at IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS outbox_available_at_index
+	ON "outbox" (available_at, id) WHERE published_at IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS outbox_published_at_index
+	ON "outbox" (published_at) WHERE published_at IS NOT NULL;
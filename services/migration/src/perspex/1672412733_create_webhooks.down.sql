@@ -0,0 +1,17 @@
+-- migrate:down transaction:false
+
+DROP INDEX CONCURRENTLY IF EXISTS webhook_attempts_delivery_id_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS webhook_deliveries_pending_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS webhook_deliveries_endpoint_id_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS webhook_deliveries_event_uindex;
+
+DROP INDEX CONCURRENTLY IF EXISTS webhook_endpoints_organization_id_index;
+
+DROP TABLE IF EXISTS "webhook_attempts";
+
+DROP TABLE IF EXISTS "webhook_deliveries";
+
+DROP TABLE IF EXISTS "webhook_endpoints";
@@ -0,0 +1,55 @@
+-- migrate:up transaction:false
+
+-- Secrets are kept in the clear, as signing payloads needs them.
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id              BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  organization_id BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+  url             TEXT NOT NULL,
+  description     TEXT NOT NULL DEFAULT '',
+  event_types     TEXT[] NOT NULL DEFAULT '{}',
+  secret          TEXT NOT NULL,
+  failures        INTEGER NOT NULL DEFAULT 0,
+  disabled_at     TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A delivery is an outbox event sent to an endpoint, or the replay of an earlier delivery.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id              BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  endpoint_id     BIGINT NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+  event_id        BIGINT NOT NULL,
+  event_type      TEXT NOT NULL,
+  payload         JSONB NOT NULL,
+  replay_of       BIGINT REFERENCES webhook_deliveries (id) ON DELETE SET NULL,
+  status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  delivered_at    TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+  id           BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  delivery_id  BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+  status_code  INTEGER NOT NULL DEFAULT 0,
+  error        TEXT NOT NULL DEFAULT '',
+  duration_ms  BIGINT NOT NULL DEFAULT 0,
+  attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_endpoints_organization_id_index
+	ON "webhook_endpoints" (organization_id);
+
+-- Events are relayed at least once, so each is only delivered once to an endpoint, replays aside.
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS webhook_deliveries_event_uindex
+	ON "webhook_deliveries" (endpoint_id, event_id) WHERE replay_of IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_deliveries_endpoint_id_index
+	ON "webhook_deliveries" (endpoint_id, id);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_deliveries_pending_index
+	ON "webhook_deliveries" (next_attempt_at, id) WHERE status = 'pending';
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_attempts_delivery_id_index
+	ON "webhook_attempts" (delivery_id, id);
@@ -0,0 +1,13 @@
+-- migrate:down transaction:false
+
+DROP TRIGGER IF EXISTS users_deleting ON users;
+
+DROP TRIGGER IF EXISTS users_changed ON users;
+
+DROP FUNCTION IF EXISTS record_user_change();
+
+DROP INDEX CONCURRENTLY IF EXISTS user_changes_xid_id_index;
+
+DROP INDEX CONCURRENTLY IF EXISTS user_changes_changed_at_index;
+
+DROP TABLE IF EXISTS user_changes;
@@ -0,0 +1,59 @@
+-- migrate:up transaction:false
+
+-- Changes to users, written by the triggers below and streamed by WatchUsers, see pkg/user/watch. IDs are drawn before
+-- commit, so they may become visible out of order: changes are instead read ordered by the transaction that made them,
+-- then by ID, and only once every transaction older than theirs has ended, when no change can appear before them
+-- anymore. The organizations are those the user is a member of as of the change, captured before a deletion cascades
+-- to their memberships.
+CREATE TABLE IF NOT EXISTS user_changes (
+  id               BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  user_id          BIGINT NOT NULL,
+  operation        TEXT NOT NULL CHECK (operation IN ('created', 'updated', 'deleted')),
+  organization_ids BIGINT[] NOT NULL DEFAULT '{}',
+  xid              XID8 NOT NULL DEFAULT pg_current_xact_id(),
+  changed_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS user_changes_xid_id_index
+	ON "user_changes" (xid, id);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS user_changes_changed_at_index
+	ON "user_changes" (changed_at);
+
+-- Records a change to a user, notifying the `user_changes` channel with its ID once committed.
+CREATE OR REPLACE FUNCTION record_user_change() RETURNS TRIGGER AS $$
+DECLARE
+  changed users;
+  change_id BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO user_changes (user_id, operation, organization_ids)
+  VALUES (
+    changed.id,
+    CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+    ARRAY(SELECT organization_id FROM organization_members WHERE user_id = changed.id ORDER BY organization_id)
+  )
+  RETURNING id INTO change_id;
+
+  PERFORM pg_notify('user_changes', change_id::TEXT);
+
+  RETURN changed;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_changed ON users;
+
+CREATE TRIGGER users_changed
+	AFTER INSERT OR UPDATE ON users
+	FOR EACH ROW EXECUTE FUNCTION record_user_change();
+
+DROP TRIGGER IF EXISTS users_deleting ON users;
+
+CREATE TRIGGER users_deleting
+	BEFORE DELETE ON users
+	FOR EACH ROW EXECUTE FUNCTION record_user_change();